@@ -0,0 +1,199 @@
+package apperror
+
+import (
+	"errors"
+	"go-crud/i18n"
+	"net/http"
+)
+
+// ================================
+// 🔹 KODE ERROR
+// ================================
+
+// Code adalah kode error yang stabil untuk dibaca mesin. Pesan boleh berubah
+// (misal diterjemahkan), kode tidak.
+type Code string
+
+const (
+	CodeBadRequest       Code = "bad_request"
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeConflict         Code = "conflict"
+	CodeValidation       Code = "validation_failed"
+	CodeOutOfStock       Code = "out_of_stock"
+	CodePayloadTooLarge  Code = "payload_too_large"
+	CodeUnsupportedMedia Code = "unsupported_media_type"
+	CodeTooManyRequests  Code = "too_many_requests"
+	CodeInternal         Code = "internal_error"
+	CodeUpstream         Code = "upstream_error" // layanan luar (identity provider, API wilayah) gagal
+)
+
+var codeStatus = map[Code]int{
+	CodeBadRequest:       http.StatusBadRequest,
+	CodeUnauthorized:     http.StatusUnauthorized,
+	CodeForbidden:        http.StatusForbidden,
+	CodeNotFound:         http.StatusNotFound,
+	CodeConflict:         http.StatusConflict,
+	CodeValidation:       http.StatusBadRequest,
+	CodeOutOfStock:       http.StatusConflict,
+	CodePayloadTooLarge:  http.StatusRequestEntityTooLarge,
+	CodeUnsupportedMedia: http.StatusUnsupportedMediaType,
+	CodeTooManyRequests:  http.StatusTooManyRequests,
+	CodeInternal:         http.StatusInternalServerError,
+	CodeUpstream:         http.StatusBadGateway,
+}
+
+// Status mengembalikan status HTTP untuk kode error, 500 kalau tidak dikenal
+func (c Code) Status() int {
+	if status, ok := codeStatus[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// CodeForStatus kebalikan Status, dipakai untuk error yang hanya punya status
+// HTTP (echo.HTTPError)
+func CodeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusMethodNotAllowed:
+		return CodeBadRequest
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMedia
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return CodeUpstream
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// ================================
+// 🔹 ERROR
+// ================================
+
+// Error adalah error yang aman dikirim ke client. Message dan Details tampil
+// di respons; Cause hanya ditulis ke log. Message dan isi Details berupa key
+// katalog i18n yang diterjemahkan HTTP error handler sesuai bahasa client.
+type Error struct {
+	Code    Code
+	Message string
+	// Args argumen format untuk Message, misal nama produk di "stok tidak mencukupi: %s"
+	Args []interface{}
+	// Details isi field "errors" di respons: []string, []i18n.Message atau
+	// []utils.FieldError
+	Details interface{}
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+// Is membuat errors.Is(err, apperror.ErrNotFound) cocok untuk semua error
+// dengan kode yang sama, apa pun pesannya
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Message == "" && t.Code == e.Code
+}
+
+func (e *Error) Status() int { return e.Code.Status() }
+
+// WithCause menyimpan error asli untuk log
+func (e *Error) WithCause(cause error) *Error {
+	e.Cause = cause
+	return e
+}
+
+// WithArgs mengisi argumen format Message
+func (e *Error) WithArgs(args ...interface{}) *Error {
+	e.Args = args
+	return e
+}
+
+// WithDetails mengisi field "errors" di respons
+func (e *Error) WithDetails(details interface{}) *Error {
+	e.Details = details
+	return e
+}
+
+// Sentinel untuk errors.Is, satu per kode
+var (
+	ErrBadRequest   = &Error{Code: CodeBadRequest}
+	ErrUnauthorized = &Error{Code: CodeUnauthorized}
+	ErrForbidden    = &Error{Code: CodeForbidden}
+	ErrNotFound     = &Error{Code: CodeNotFound}
+	ErrConflict     = &Error{Code: CodeConflict}
+	ErrValidation   = &Error{Code: CodeValidation}
+	ErrOutOfStock   = &Error{Code: CodeOutOfStock}
+	ErrTooLarge     = &Error{Code: CodePayloadTooLarge}
+	ErrUnsupported  = &Error{Code: CodeUnsupportedMedia}
+	ErrTooMany      = &Error{Code: CodeTooManyRequests}
+	ErrInternal     = &Error{Code: CodeInternal}
+	ErrUpstream     = &Error{Code: CodeUpstream}
+)
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func BadRequest(message string) *Error      { return New(CodeBadRequest, message) }
+func Unauthorized(message string) *Error    { return New(CodeUnauthorized, message) }
+func Forbidden(message string) *Error       { return New(CodeForbidden, message) }
+func NotFound(message string) *Error        { return New(CodeNotFound, message) }
+func Conflict(message string) *Error        { return New(CodeConflict, message) }
+func OutOfStock(message string) *Error      { return New(CodeOutOfStock, message) }
+func TooManyRequests(message string) *Error { return New(CodeTooManyRequests, message) }
+func BadGateway(message string) *Error      { return New(CodeUpstream, message) }
+
+// Validation untuk input yang tidak lolos aturan. details biasanya
+// []utils.FieldError dari package validation.
+func Validation(message string, details interface{}) *Error {
+	return New(CodeValidation, message).WithDetails(details)
+}
+
+// Internal untuk error yang tidak diharapkan (database, jaringan, ...).
+// Client hanya melihat message; cause masuk log.
+func Internal(message string, cause error) *Error {
+	return New(CodeInternal, message).WithCause(cause)
+}
+
+// As mengambil *Error dari rantai error
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// Wrap memberi judul message pada err. Kalau err sudah *Error, kodenya
+// dipertahankan dan pesan aslinya pindah ke Details (bentuk respons lama:
+// message = judul, errors = [alasan]). Error lain dianggap Internal.
+func Wrap(err error, message string) *Error {
+	appErr, ok := As(err)
+	if !ok {
+		return Internal(message, err)
+	}
+	wrapped := &Error{Code: appErr.Code, Message: message, Details: appErr.Details, Cause: appErr.Cause}
+	if wrapped.Details == nil && appErr.Message != "" {
+		wrapped.Details = []i18n.Message{i18n.M(appErr.Message, appErr.Args...)}
+	}
+	return wrapped
+}
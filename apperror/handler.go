@@ -0,0 +1,168 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"go-crud/i18n"
+	"go-crud/utils"
+	"go-crud/validation"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ================================
+// 🔹 HTTP ERROR HANDLER
+// ================================
+
+// MIMEProblemJSON content type RFC 7807
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem adalah body respons RFC 7807. Field code dan errors adalah extension
+// member, isinya sama dengan respons BaseResponse biasa.
+type Problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty"`
+	Code     Code        `json:"code"`
+	Errors   interface{} `json:"errors,omitempty"`
+}
+
+// NewHTTPErrorHandler membuat echo.HTTPErrorHandler yang mengubah semua error
+// dari handler/middleware menjadi utils.BaseResponse (atau problem+json kalau
+// problemJSON true atau diminta client lewat header Accept). Detail error
+// internal hanya ditulis ke log.
+func NewHTTPErrorHandler(problemJSON bool) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		appErr := localize(normalize(err), i18n.FromContext(c))
+		status := appErr.Status()
+		if status >= 500 {
+			req := c.Request()
+			log.Printf("[error] %s %s -> %d %s: %v", req.Method, req.URL.RequestURI(), status, appErr.Code, err)
+		}
+
+		var writeErr error
+		switch {
+		case c.Request().Method == http.MethodHead:
+			writeErr = c.NoContent(status)
+		case problemJSON || wantsProblem(c.Request()):
+			writeErr = writeProblem(c, status, appErr)
+		default:
+			writeErr = c.JSON(status, utils.BaseResponse{
+				Status:  false,
+				Code:    string(appErr.Code),
+				Message: appErr.Message,
+				Errors:  appErr.Details,
+				Data:    nil,
+			})
+		}
+		if writeErr != nil {
+			log.Printf("[error] gagal menulis respons error: %v", writeErr)
+		}
+	}
+}
+
+// normalize mengubah error apa pun menjadi *Error yang aman ditampilkan
+func normalize(err error) *Error {
+	if appErr, ok := As(err); ok {
+		if appErr.Message == "" {
+			// jangan ubah sentinel (ErrNotFound dsb), buat salinan
+			copied := *appErr
+			copied.Message = errorKey(appErr.Code)
+			return &copied
+		}
+		return appErr
+	}
+
+	var verrs validation.Errors
+	if errors.As(err, &verrs) {
+		return Validation("common.invalid_input", verrs)
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		// pesan bawaan echo ("Not Found", "missing or malformed jwt", ...)
+		// diganti pesan katalog per kode supaya ikut diterjemahkan
+		code := CodeForStatus(he.Code)
+		appErr := New(code, errorKey(code))
+		// pesan HTTPError 5xx bisa berisi detail internal
+		if he.Code >= 500 {
+			appErr.Cause = fmt.Errorf("%v", he.Message)
+		}
+		if he.Internal != nil {
+			appErr.Cause = he.Internal
+		}
+		return appErr
+	}
+
+	return Internal(errorKey(CodeInternal), err)
+}
+
+// errorKey key katalog pesan umum untuk kode error, misal "error.not_found"
+func errorKey(code Code) string {
+	return "error." + string(code)
+}
+
+// localize menerjemahkan Message dan Details ke bahasa lang. Hasilnya salinan,
+// err asli (bisa saja sentinel) tidak diubah.
+func localize(appErr *Error, lang i18n.Lang) *Error {
+	out := *appErr
+	out.Message = i18n.Translate(lang, appErr.Message, appErr.Args...)
+	out.Args = nil
+
+	switch details := appErr.Details.(type) {
+	case []string:
+		msgs := make([]string, len(details))
+		for i, key := range details {
+			msgs[i] = i18n.Translate(lang, key)
+		}
+		out.Details = msgs
+	case []i18n.Message:
+		msgs := make([]string, len(details))
+		for i, m := range details {
+			msgs[i] = m.In(lang)
+		}
+		out.Details = msgs
+	case validation.Errors:
+		out.Details = localizeFields(details, lang)
+	case []utils.FieldError:
+		out.Details = localizeFields(details, lang)
+	}
+	return &out
+}
+
+func localizeFields(fields []utils.FieldError, lang i18n.Lang) []utils.FieldError {
+	out := make([]utils.FieldError, len(fields))
+	for i, fe := range fields {
+		fe.Message = i18n.Translate(lang, fe.Message, fe.Args...)
+		out[i] = fe
+	}
+	return out
+}
+
+func wantsProblem(req *http.Request) bool {
+	return strings.Contains(req.Header.Get(echo.HeaderAccept), MIMEProblemJSON)
+}
+
+func writeProblem(c echo.Context, status int, appErr *Error) error {
+	body := Problem{
+		Type:     "/errors/" + string(appErr.Code),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: c.Request().URL.Path,
+		Code:     appErr.Code,
+		Errors:   appErr.Details,
+	}
+	// c.JSON tidak menimpa Content-Type yang sudah di-set
+	c.Response().Header().Set(echo.HeaderContentType, MIMEProblemJSON)
+	return c.JSON(status, body)
+}
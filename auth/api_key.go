@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"go-crud/config"
+	"go-crud/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix menandai string sebagai API key (bukan JWT), berguna juga untuk
+// secret scanner di repository / log
+const APIKeyPrefix = "gck_"
+
+// last_used_at hanya ditulis ulang kalau sudah lewat interval ini supaya
+// setiap request tidak selalu menulis ke database
+const apiKeyTouchInterval = time.Minute
+
+var ErrInvalidAPIKey = errors.New("API key tidak valid, sudah dicabut, atau sudah kedaluwarsa")
+
+// IsAPIKey membedakan API key dari JWT di header Authorization
+func IsAPIKey(raw string) bool {
+	return strings.HasPrefix(raw, APIKeyPrefix)
+}
+
+// NewAPIKey membuat key baru dengan format gck_<id>_<secret>. Bagian
+// gck_<id> disimpan apa adanya sebagai prefix untuk mencari key di database.
+func NewAPIKey() (raw, prefix, hash string, err error) {
+	id, err := randomString(6)
+	if err != nil {
+		return "", "", "", err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return "", "", "", err
+	}
+	// karakter "_" dipakai sebagai pemisah, jadi tidak boleh muncul di id
+	id = strings.NewReplacer("_", "x", "-", "y").Replace(id)
+
+	prefix = APIKeyPrefix + id
+	raw = prefix + "_" + secret
+	return raw, prefix, hashToken(raw), nil
+}
+
+// apiKeyPrefix mengambil gck_<id>; id selalu 8 karakter (6 byte base64url)
+func apiKeyPrefix(raw string) string {
+	n := len(APIKeyPrefix) + 8
+	if len(raw) <= n || raw[n] != '_' {
+		return ""
+	}
+	return raw[:n]
+}
+
+// AuthenticateAPIKey mencari key berdasarkan prefix, membandingkan hash, lalu
+// mengembalikan user pemilik dengan Scopes yang sudah dibatasi ke permission key
+func AuthenticateAPIKey(raw, ip string) (*models.APIKey, *models.User, error) {
+	prefix := apiKeyPrefix(raw)
+	if prefix == "" {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	var key models.APIKey
+	if err := config.DB.Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashToken(raw))) != 1 {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	var user models.User
+	if err := config.DB.Preload("Roles").Preload("TwoFactor").First(&user, key.IDUser).Error; err != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	user.Scopes = key.PermissionList()
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		config.DB.Model(&key).Updates(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		})
+	}
+	return &key, &user, nil
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"go-crud/config"
+	"go-crud/migrations"
+	"go-crud/models"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// openTestDB menyiapkan config.App bawaan dan database SQLite di memori yang
+// sudah dimigrasi, dipasang sebagai config.DB selama test berjalan
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	app := config.App
+	config.App = config.Default()
+	cfg := config.App.Database
+	cfg.Driver, cfg.Name = "sqlite", ":memory:"
+	config.ConnectDatabase(cfg)
+	t.Cleanup(func() {
+		config.CloseDatabase()
+		config.App = app
+	})
+	if _, err := migrations.Up(config.DB); err != nil {
+		t.Fatalf("migrasi: %v", err)
+	}
+	return config.DB
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) models.User {
+	t.Helper()
+	user := models.User{Nama: "Test", Email: email, KataSandi: "-"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("membuat user: %v", err)
+	}
+	return user
+}
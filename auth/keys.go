@@ -0,0 +1,346 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"go-crud/config"
+	"go-crud/lifecycle"
+	"go-crud/models"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var ErrNoSigningKey = errors.New("belum ada signing key aktif")
+
+// jarak minimal antar reload saat menerima kid yang belum dikenal, supaya
+// token palsu dengan kid acak tidak membuat query database terus-menerus
+const keyReloadInterval = 10 * time.Second
+
+type signingKey struct {
+	kid         string
+	method      jwt.SigningMethod
+	private     crypto.Signer
+	activatesAt time.Time
+}
+
+// KeyManager menyimpan semua signing key yang masih berlaku di memori.
+// Sumber kebenarannya tabel signing_keys sehingga semua instance memakai key
+// yang sama; rotasi dijalankan oleh worker StartKeyRotation.
+type KeyManager struct {
+	mu         sync.RWMutex
+	keys       []signingKey // terurut dari activatesAt paling lama
+	lastReload time.Time
+}
+
+// Keys dipakai untuk menandatangani dan memverifikasi semua JWT
+var Keys = &KeyManager{}
+
+// ================================
+// 🔹 Tanda Tangan & Verifikasi
+// ================================
+
+// Sign menandatangani claims dengan key aktif terbaru (header kid diisi)
+func (m *KeyManager) Sign(claims jwt.MapClaims) (string, error) {
+	key := m.current(time.Now())
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+	claims["iss"] = config.App.JWT.Issuer
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
+}
+
+// Keyfunc mencari public key berdasarkan header kid
+func (m *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("header kid tidak ada")
+	}
+
+	key := m.find(kid)
+	if key == nil && m.reloadAllowed() {
+		// kemungkinan key baru dibuat instance lain
+		if err := m.Load(config.DB); err != nil {
+			return nil, err
+		}
+		key = m.find(kid)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("kid %q tidak dikenal", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("algoritma %s tidak cocok dengan key %s", token.Method.Alg(), kid)
+	}
+	return key.private.Public(), nil
+}
+
+// ParseToken memverifikasi tanda tangan, iss dan exp token
+func ParseToken(raw string) (*jwt.Token, error) {
+	return jwt.Parse(raw, Keys.Keyfunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(config.App.JWT.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+}
+
+func (m *KeyManager) current(now time.Time) *signingKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if !m.keys[i].activatesAt.After(now) {
+			key := m.keys[i]
+			return &key
+		}
+	}
+	return nil
+}
+
+func (m *KeyManager) find(kid string) *signingKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.kid == kid {
+			key := key
+			return &key
+		}
+	}
+	return nil
+}
+
+func (m *KeyManager) reloadAllowed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if time.Since(m.lastReload) < keyReloadInterval {
+		return false
+	}
+	m.lastReload = time.Now()
+	return true
+}
+
+// ================================
+// 🔹 Load & Rotasi
+// ================================
+
+// Load membaca ulang semua key dari database
+func (m *KeyManager) Load(db *gorm.DB) error {
+	var rows []models.SigningKey
+	if err := db.Order("activates_at asc").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	keys := make([]signingKey, 0, len(rows))
+	for _, row := range rows {
+		key, err := decodeSigningKey(row)
+		if err != nil {
+			return fmt.Errorf("signing key %s rusak: %v", row.KID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.mu.Unlock()
+	return nil
+}
+
+// Rotate memastikan selalu ada key aktif, menyiapkan key berikutnya sebelum
+// waktunya (supaya sudah ada di JWKS), dan menghapus key yang semua tokennya
+// sudah kedaluwarsa. Aman dijalankan bersamaan oleh beberapa instance:
+// baris signing_key_locks dikunci dulu sehingga cek-lalu-insert di bawah
+// berjalan bergantian.
+func (m *KeyManager) Rotate(db *gorm.DB) error {
+	cfg := config.App.JWT
+	now := time.Now()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := lockRotation(tx, now); err != nil {
+			return err
+		}
+
+		var rows []models.SigningKey
+		if err := tx.Order("activates_at asc").Find(&rows).Error; err != nil {
+			return err
+		}
+
+		var active, newest *models.SigningKey
+		for i := range rows {
+			if !rows[i].ActivatesAt.After(now) {
+				active = &rows[i]
+			}
+			newest = &rows[i]
+		}
+
+		switch {
+		case active == nil:
+			// database baru: key pertama langsung aktif
+			if err := createSigningKey(tx, cfg.Algorithm, now); err != nil {
+				return err
+			}
+		case newest.ActivatesAt.After(now) && newest.Algorithm == cfg.Algorithm:
+			// key berikutnya sudah disiapkan
+		case newest.Algorithm != cfg.Algorithm,
+			!now.Before(newest.ActivatesAt.Add(cfg.RotationInterval - cfg.Prepublish)):
+			next := newest.ActivatesAt.Add(cfg.RotationInterval)
+			if earliest := now.Add(cfg.Prepublish); next.Before(earliest) {
+				next = earliest
+			}
+			if err := createSigningKey(tx, cfg.Algorithm, next); err != nil {
+				return err
+			}
+		}
+
+		return deleteRetiredKeys(tx, rows, now)
+	})
+	if err != nil {
+		return err
+	}
+	return m.Load(db)
+}
+
+// lockRotation mengunci baris sentinel sampai transaksi selesai. Dipakai
+// UPDATE, bukan SELECT ... FOR UPDATE, karena SQLite mengabaikan FOR UPDATE
+// sedangkan UPDATE langsung mengambil write lock di semua driver.
+func lockRotation(tx *gorm.DB, now time.Time) error {
+	res := tx.Model(&models.SigningKeyLock{}).Where("id = ?", 1).Update("rotated_at", now)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		// baris sentinel terhapus; instance lain yang membuatnya bersamaan
+		// gagal di primary key dan mencoba lagi pada rotasi berikutnya
+		return tx.Create(&models.SigningKeyLock{ID: 1, RotatedAt: now}).Error
+	}
+	return nil
+}
+
+// key pensiun setelah key penggantinya aktif ditambah umur token terpanjang
+func keyGracePeriod() time.Duration {
+	grace := config.App.JWT.TokenTTL
+	if config.App.TwoFactor.ChallengeTTL > grace {
+		grace = config.App.TwoFactor.ChallengeTTL
+	}
+	return grace + time.Minute
+}
+
+func deleteRetiredKeys(tx *gorm.DB, rows []models.SigningKey, now time.Time) error {
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ActivatesAt.Before(rows[j].ActivatesAt) })
+
+	for i := 0; i+1 < len(rows); i++ {
+		successor := rows[i+1].ActivatesAt
+		if successor.After(now) || now.Before(successor.Add(keyGracePeriod())) {
+			continue
+		}
+		if err := tx.Delete(&models.SigningKey{}, "kid = ?", rows[i].KID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitKeys dipanggil saat server start sebelum menerima request
+func InitKeys() error {
+	return Keys.Rotate(config.DB)
+}
+
+// StartKeyRotation menjalankan Rotate berkala sebagai worker background
+func StartKeyRotation(interval time.Duration) {
+	lifecycle.Go("jwt-key-rotation", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := Keys.Rotate(config.DB.WithContext(ctx)); err != nil {
+					log.Printf("gagal rotasi signing key: %v", err)
+				}
+			}
+		}
+	})
+}
+
+// ================================
+// 🔹 Generate & Encode Key
+// ================================
+
+func createSigningKey(tx *gorm.DB, algorithm string, activatesAt time.Time) error {
+	var (
+		private crypto.Signer
+		err     error
+	)
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.SigningMethodEdDSA.Alg():
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return fmt.Errorf("algoritma JWT tidak didukung: %s", algorithm)
+	}
+	if err != nil {
+		return err
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return err
+	}
+	kid, err := thumbprint(private.Public())
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&models.SigningKey{
+		KID:         kid,
+		Algorithm:   algorithm,
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})),
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})),
+		ActivatesAt: activatesAt,
+	}).Error
+}
+
+func decodeSigningKey(row models.SigningKey) (signingKey, error) {
+	block, _ := pem.Decode([]byte(row.PrivateKey))
+	if block == nil {
+		return signingKey{}, errors.New("PEM tidak valid")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	key := signingKey{kid: row.KID, activatesAt: row.ActivatesAt}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private = jwt.SigningMethodRS256, private
+	case ed25519.PrivateKey:
+		key.method, key.private = jwt.SigningMethodEdDSA, private
+	default:
+		return signingKey{}, fmt.Errorf("tipe key %T tidak didukung", parsed)
+	}
+	if key.method.Alg() != row.Algorithm {
+		return signingKey{}, fmt.Errorf("algoritma %s tidak cocok dengan isi key", row.Algorithm)
+	}
+	return key, nil
+}
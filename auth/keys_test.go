@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"go-crud/config"
+	"go-crud/migrations"
+	"go-crud/models"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// Beberapa instance yang menjalankan Rotate bersamaan pada database kosong
+// hanya boleh menghasilkan satu key aktif
+func TestRotateConcurrent(t *testing.T) {
+	app := config.App
+	config.App = config.Default()
+	// file, bukan ":memory:", supaya pool benar-benar memakai banyak koneksi
+	cfg := config.App.Database
+	cfg.Driver, cfg.Name = "sqlite", filepath.Join(t.TempDir(), "keys.db")
+	config.ConnectDatabase(cfg)
+	t.Cleanup(func() {
+		config.CloseDatabase()
+		config.App = app
+	})
+	db := config.DB
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("migrasi: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := (&KeyManager{}).Rotate(db); err != nil {
+				t.Errorf("Rotate: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var count int64
+	db.Model(&models.SigningKey{}).Count(&count)
+	if count != 1 {
+		t.Errorf("%d signing key dibuat, seharusnya 1", count)
+	}
+}
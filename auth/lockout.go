@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"go-crud/config"
+	"go-crud/ratelimit"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrAccountLocked = errors.New("akun dikunci sementara karena terlalu banyak percobaan login gagal")
+	ErrLoginBackoff  = errors.New("terlalu banyak percobaan login, tunggu sebentar lalu coba lagi")
+)
+
+// Key counter diturunkan dari email (bukan id user) supaya email yang tidak
+// terdaftar diperlakukan sama persis dan tidak bisa dipakai enumerasi akun
+func accountKey(kind, email string) string {
+	return "login:" + kind + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// CheckLoginAllowed dipanggil sebelum memeriksa password. Mengembalikan sisa
+// waktu tunggu bersama ErrAccountLocked / ErrLoginBackoff.
+func CheckLoginAllowed(ctx context.Context, email string) (time.Duration, error) {
+	if locked, ttl, err := ratelimit.Default.Get(ctx, accountKey("lock", email)); err != nil {
+		return 0, err
+	} else if locked > 0 {
+		return ttl, ErrAccountLocked
+	}
+	if waiting, ttl, err := ratelimit.Default.Get(ctx, accountKey("wait", email)); err != nil {
+		return 0, err
+	} else if waiting > 0 {
+		return ttl, ErrLoginBackoff
+	}
+	return 0, nil
+}
+
+// RecordLoginFailure mencatat gagal login. Setiap kegagalan menambah waktu
+// tunggu secara eksponensial, setelah max_failures akun dikunci.
+func RecordLoginFailure(ctx context.Context, email string) error {
+	cfg := config.App.RateLimit
+
+	failures, _, err := ratelimit.Default.Incr(ctx, accountKey("fail", email), cfg.FailureWindow)
+	if err != nil {
+		return err
+	}
+	if failures >= int64(cfg.MaxFailures) {
+		if err := ratelimit.Default.Set(ctx, accountKey("lock", email), 1, cfg.LockoutDuration); err != nil {
+			return err
+		}
+		return ratelimit.Default.Delete(ctx, accountKey("fail", email), accountKey("wait", email))
+	}
+
+	delay := cfg.BackoffBase << (failures - 1)
+	if delay > cfg.BackoffMax || delay <= 0 {
+		delay = cfg.BackoffMax
+	}
+	return ratelimit.Default.Set(ctx, accountKey("wait", email), 1, delay)
+}
+
+// ResetLoginFailures dipanggil setelah login berhasil
+func ResetLoginFailures(ctx context.Context, email string) error {
+	return ratelimit.Default.Delete(ctx, accountKey("fail", email), accountKey("wait", email))
+}
+
+// UnlockAccount dipakai admin untuk membuka kunci akun sebelum waktunya
+func UnlockAccount(ctx context.Context, email string) error {
+	return ratelimit.Default.Delete(ctx, accountKey("fail", email), accountKey("wait", email), accountKey("lock", email))
+}
+
+// LockStatus mengembalikan jumlah gagal login saat ini dan sisa waktu kunci
+func LockStatus(ctx context.Context, email string) (failures int64, lockedFor time.Duration, err error) {
+	failures, _, err = ratelimit.Default.Get(ctx, accountKey("fail", email))
+	if err != nil {
+		return 0, 0, err
+	}
+	locked, ttl, err := ratelimit.Default.Get(ctx, accountKey("lock", email))
+	if err != nil || locked == 0 {
+		return failures, 0, err
+	}
+	return failures, ttl, nil
+}
+
+// ================================
+// 🔹 Cek Password
+// ================================
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// CheckPassword membandingkan password dengan hash bcrypt. Kalau user tidak
+// ditemukan (hash kosong) tetap dijalankan bcrypt terhadap hash dummy supaya
+// waktu respons tidak membocorkan email mana yang terdaftar.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password-tidak-dipakai"), bcrypt.DefaultCost)
+		})
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"go-crud/config"
+	"go-crud/models"
+	"go-crud/oidc"
+	"time"
+)
+
+var (
+	ErrInvalidOIDCState = errors.New("sesi login SSO tidak valid atau sudah kedaluwarsa, silakan ulangi")
+	// callback login menerima state milik alur tautkan akun; code + state harus
+	// diteruskan ke endpoint tautkan akun dengan token user yang memulainya
+	ErrOIDCLinkPending = errors.New("state ini untuk menautkan akun SSO")
+)
+
+// BeginOIDC menyiapkan state, nonce dan code_verifier PKCE lalu mengembalikan
+// URL halaman login IdP. linkUserID diisi kalau user yang sedang login ingin
+// menautkan akun SSO ke akunnya.
+func BeginOIDC(ctx context.Context, linkUserID *uint64) (string, error) {
+	if oidc.Default == nil {
+		return "", oidc.ErrDisabled
+	}
+
+	state, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomString(48)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := oidc.Default.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", err
+	}
+
+	row := models.OIDCState{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		IDUser:       linkUserID,
+		ExpiresAt:    time.Now().Add(config.App.OIDC.StateTTL),
+	}
+	if err := config.DB.WithContext(ctx).Create(&row).Error; err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// CompleteOIDC memproses callback dari IdP: state dipakai sekali, code ditukar
+// dengan id_token (bersama code_verifier), lalu id_token diverifikasi.
+// linkUserID harus sama dengan yang dipakai di BeginOIDC, supaya state untuk
+// menautkan akun tidak bisa diselesaikan lewat login biasa atau oleh user lain.
+func CompleteOIDC(ctx context.Context, state, code string, linkUserID *uint64) (*oidc.Claims, error) {
+	if oidc.Default == nil {
+		return nil, oidc.ErrDisabled
+	}
+	if state == "" || code == "" {
+		return nil, ErrInvalidOIDCState
+	}
+
+	var row models.OIDCState
+	if err := config.DB.WithContext(ctx).Where("state_hash = ?", hashToken(state)).First(&row).Error; err != nil {
+		return nil, ErrInvalidOIDCState
+	}
+	if !sameUser(row.IDUser, linkUserID) {
+		// state tidak dihapus, supaya pemiliknya masih bisa menyelesaikan alurnya
+		if row.IDUser != nil && linkUserID == nil {
+			return nil, ErrOIDCLinkPending
+		}
+		return nil, ErrInvalidOIDCState
+	}
+	// delete dengan RowsAffected mencegah callback yang sama diproses dua kali
+	res := config.DB.WithContext(ctx).Delete(&row)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 || time.Now().After(row.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	idToken, err := oidc.Default.Exchange(ctx, code, row.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	return oidc.Default.Verify(ctx, idToken, row.Nonce)
+}
+
+func sameUser(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"errors"
+	"go-crud/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidOneTimeToken = errors.New("token tidak valid, sudah dipakai, atau sudah kedaluwarsa")
+
+// CreateOneTimeToken membuat token sekali pakai (verifikasi email / reset kata
+// sandi). Token lama dengan tujuan yang sama otomatis tidak berlaku lagi.
+func CreateOneTimeToken(tx *gorm.DB, userID uint64, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	if err := tx.Model(&models.UserToken{}).
+		Where("id_user = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", now).Error; err != nil {
+		return "", err
+	}
+
+	raw, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	token := models.UserToken{
+		IDUser:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := tx.Create(&token).Error; err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// ConsumeOneTimeToken menandai token sudah dipakai dan mengembalikan datanya.
+// Harus dipanggil di dalam transaksi bersama perubahan yang dilindunginya.
+func ConsumeOneTimeToken(tx *gorm.DB, raw, purpose string) (*models.UserToken, error) {
+	var token models.UserToken
+	if err := tx.Where("token_hash = ? AND purpose = ?", hashToken(raw), purpose).First(&token).Error; err != nil {
+		return nil, ErrInvalidOneTimeToken
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil || now.After(token.ExpiresAt) {
+		return nil, ErrInvalidOneTimeToken
+	}
+
+	// used_at IS NULL mencegah token yang sama dipakai dua request bersamaan
+	res := tx.Model(&token).Where("used_at IS NULL").Update("used_at", now)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrInvalidOneTimeToken
+	}
+	return &token, nil
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"errors"
+	"go-crud/config"
+	"go-crud/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound   = errors.New("session tidak ditemukan")
+	ErrSessionTerminated = errors.New("session sudah diakhiri, silakan login ulang")
+)
+
+// last_seen_at hanya ditulis ulang kalau sudah lewat interval ini
+const sessionTouchInterval = time.Minute
+
+// ClientInfo diambil dari request saat login / refresh
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	// nama perangkat dari header X-Device-Name (aplikasi mobile), kosong = tebak dari user agent
+	DeviceName string
+}
+
+func (ci ClientInfo) device() string {
+	name := strings.TrimSpace(ci.DeviceName)
+	if name == "" {
+		name = DeviceName(ci.UserAgent)
+	}
+	return truncateRunes(name, 100)
+}
+
+// truncateRunes memotong s menjadi paling banyak n karakter (bukan byte,
+// kolom varchar dihitung per karakter) tanpa membelah UTF-8. Byte yang bukan
+// UTF-8 valid dari header client dibuang supaya tidak ditolak database.
+func truncateRunes(s string, n int) string {
+	s = strings.ToValidUTF8(s, "")
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// DeviceName menebak nama perangkat dari user agent, contoh "Chrome di Windows"
+func DeviceName(ua string) string {
+	if ua == "" {
+		return "Perangkat tidak dikenal"
+	}
+
+	browser := "Browser"
+	for _, b := range []struct{ token, name string }{
+		// urutan penting: Edge & Opera juga mengandung "Chrome", Chrome mengandung "Safari"
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"PostmanRuntime", "Postman"},
+		{"curl/", "curl"},
+		{"okhttp", "Aplikasi Android"},
+		{"Go-http-client", "Go HTTP client"},
+	} {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	for _, o := range []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, o.token) {
+			return browser + " di " + o.name
+		}
+	}
+	return browser
+}
+
+func createSession(tx *gorm.DB, userID uint64, familyID string, client ClientInfo, expiresAt time.Time) (*models.Session, error) {
+	ua := truncateRunes(client.UserAgent, 500)
+	session := models.Session{
+		IDUser:     userID,
+		FamilyID:   familyID,
+		Device:     client.device(),
+		UserAgent:  ua,
+		IP:         client.IP,
+		ExpiresAt:  expiresAt,
+		LastSeenAt: time.Now(),
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// CheckSession dipanggil AttachUser untuk token yang punya klaim sid.
+// Session yang diakhiri / kedaluwarsa ditolak, last_seen_at diperbarui berkala.
+func CheckSession(sessionID uint64, ip string) error {
+	var session models.Session
+	if err := config.DB.First(&session, sessionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionTerminated
+		}
+		return err
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return ErrSessionTerminated
+	}
+	if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		config.DB.Model(&session).Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"ip":           ip,
+		})
+	}
+	return nil
+}
+
+// ListSessions mengembalikan session aktif milik user, terbaru dipakai di atas
+func ListSessions(userID uint64) ([]models.Session, error) {
+	var sessions []models.Session
+	err := config.DB.
+		Where("id_user = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at desc").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// TerminateSession mengakhiri satu session milik user beserta refresh token-nya.
+// Access token yang masih berlaku ikut ditolak karena AttachUser mengecek sid.
+func TerminateSession(tx *gorm.DB, userID, sessionID uint64) error {
+	var session models.Session
+	if err := tx.Where("id = ? AND id_user = ? AND revoked_at IS NULL", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return revokeFamily(tx, session.FamilyID)
+}
+
+// revokeFamily mencabut semua refresh token satu family dan session-nya
+func revokeFamily(tx *gorm.DB, familyID string) error {
+	now := time.Now()
+	if err := tx.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		n    int
+		want string
+	}{
+		{"pendek", "Pixel 8", 100, "Pixel 8"},
+		{"ascii tepat di batas", strings.Repeat("a", 5), 5, "aaaaa"},
+		{"ascii dipotong", strings.Repeat("a", 7), 5, "aaaaa"},
+		// emoji 4 byte tidak boleh terbelah di byte ke-5
+		{"emoji di batas byte", "a📱📱📱", 2, "a📱"},
+		{"multibyte dihitung per karakter", "Ponsel Budi 📱📱", 13, "Ponsel Budi 📱"},
+		{"utf-8 rusak dibuang", "HP\xff\xfeBudi", 100, "HPBudi"},
+		{"kosong", "", 3, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := truncateRunes(tc.in, tc.n)
+			if got != tc.want {
+				t.Errorf("truncateRunes(%q, %d) = %q, seharusnya %q", tc.in, tc.n, got, tc.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("hasil %q bukan UTF-8 valid", got)
+			}
+		})
+	}
+}
+
+// Nama perangkat dari header dibatasi 100 karakter, bukan 100 byte
+func TestClientInfoDevice(t *testing.T) {
+	name := strings.Repeat("📱", 150)
+	got := ClientInfo{DeviceName: name}.device()
+	if n := utf8.RuneCountInString(got); n != 100 || !utf8.ValidString(got) {
+		t.Errorf("device %d karakter (valid %v), seharusnya 100 karakter valid", n, utf8.ValidString(got))
+	}
+}
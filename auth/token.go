@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"go-crud/config"
+	"go-crud/lifecycle"
+	"go-crud/models"
+	"log"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token tidak valid atau sudah kedaluwarsa")
+	ErrRefreshTokenReused  = errors.New("refresh token sudah pernah dipakai, semua sesi terkait dicabut")
+)
+
+// TokenPair dikirim ke client setelah login / refresh
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// ================================
+// 🔹 Access Token
+// ================================
+
+func newAccessToken(user models.User, sessionID uint64) (string, error) {
+	jti, err := randomString(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"tv":      user.TokenVersion,
+		"sid":     sessionID,
+		"jti":     jti,
+		"iat":     now.Unix(),
+		"exp":     now.Add(config.App.JWT.TokenTTL).Unix(),
+	}
+	return Keys.Sign(claims)
+}
+
+// RevokeAccessToken memasukkan jti ke daftar cabut sampai token kedaluwarsa
+func RevokeAccessToken(userID uint64, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return nil
+	}
+	revoked := models.RevokedToken{JTI: jti, IDUser: userID, ExpiresAt: expiresAt}
+	return config.DB.Where(models.RevokedToken{JTI: jti}).FirstOrCreate(&revoked).Error
+}
+
+// IsAccessTokenRevoked dicek oleh middleware.AttachUser di setiap request
+func IsAccessTokenRevoked(jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+	var count int64
+	err := config.DB.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// ================================
+// 🔹 Refresh Token
+// ================================
+
+func newRefreshToken(tx *gorm.DB, userID uint64, familyID string) (string, *models.RefreshToken, error) {
+	raw, err := randomString(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rt := models.RefreshToken{
+		IDUser:    userID,
+		TokenHash: hashToken(raw),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(config.App.JWT.RefreshTTL),
+	}
+	if err := tx.Create(&rt).Error; err != nil {
+		return "", nil, err
+	}
+	return raw, &rt, nil
+}
+
+// IssueTokens membuat session baru untuk login beserta access token dan
+// refresh token (family baru)
+func IssueTokens(user models.User, client ClientInfo) (*TokenPair, error) {
+	familyID, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		refresh string
+		session *models.Session
+	)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var rt *models.RefreshToken
+		refresh, rt, err = newRefreshToken(tx, user.ID, familyID)
+		if err != nil {
+			return err
+		}
+		session, err = createSession(tx, user.ID, familyID, client, rt.ExpiresAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := newAccessToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int64(config.App.JWT.TokenTTL.Seconds()),
+	}, nil
+}
+
+// Refresh menukar refresh token dengan pasangan token baru (rotasi). Refresh
+// token lama langsung dicabut; kalau token yang sudah dicabut dipakai lagi,
+// seluruh family-nya (dan session-nya) dicabut karena kemungkinan besar token itu dicuri.
+func Refresh(raw string, client ClientInfo) (*TokenPair, *models.User, error) {
+	var (
+		pair *TokenPair
+		user models.User
+	)
+
+	reused := false
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Where("token_hash = ?", hashToken(raw)).First(&current).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		now := time.Now()
+		if current.RevokedAt != nil {
+			// token yang dicabut lewat logout / hapus session tidak punya pengganti,
+			// jadi bukan indikasi token dicuri
+			if current.ReplacedBy == nil {
+				return ErrInvalidRefreshToken
+			}
+			reused = true
+			return revokeFamily(tx, current.FamilyID)
+		}
+		if now.After(current.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		var session models.Session
+		err := tx.Where("family_id = ?", current.FamilyID).First(&session).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// refresh token dari sebelum ada fitur session
+			created, err := createSession(tx, current.IDUser, current.FamilyID, client, current.ExpiresAt)
+			if err != nil {
+				return err
+			}
+			session = *created
+		case err != nil:
+			return err
+		case session.RevokedAt != nil:
+			return ErrInvalidRefreshToken
+		}
+
+		if err := tx.First(&user, current.IDUser).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		refresh, next, err := newRefreshToken(tx, user.ID, current.FamilyID)
+		if err != nil {
+			return err
+		}
+		// kondisi revoked_at IS NULL mencegah dua request refresh bersamaan sama-sama lolos
+		res := tx.Model(&current).Where("revoked_at IS NULL").Updates(map[string]interface{}{
+			"revoked_at":  now,
+			"replaced_by": next.ID,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := tx.Model(&session).Updates(map[string]interface{}{
+			"expires_at":   next.ExpiresAt,
+			"last_seen_at": now,
+			"ip":           client.IP,
+		}).Error; err != nil {
+			return err
+		}
+
+		access, err := newAccessToken(user, session.ID)
+		if err != nil {
+			return err
+		}
+		pair = &TokenPair{
+			AccessToken:  access,
+			RefreshToken: refresh,
+			ExpiresIn:    int64(config.App.JWT.TokenTTL.Seconds()),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if reused {
+		return nil, nil, ErrRefreshTokenReused
+	}
+	return pair, &user, nil
+}
+
+// RevokeRefreshToken dipakai saat logout, hanya boleh mencabut token milik
+// user sendiri. Session dari token tersebut ikut diakhiri.
+func RevokeRefreshToken(userID uint64, raw string) error {
+	var rt models.RefreshToken
+	err := config.DB.Where("token_hash = ? AND id_user = ?", hashToken(raw), userID).First(&rt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeFamily(tx, rt.FamilyID)
+	})
+}
+
+// RevokeAll mengeluarkan user dari semua sesi: semua refresh token dicabut dan
+// token_version dinaikkan sehingga access token lama ditolak AttachUser
+func RevokeAll(tx *gorm.DB, userID uint64) error {
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).
+		UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := tx.Model(&models.RefreshToken{}).
+		Where("id_user = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Session{}).
+		Where("id_user = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+}
+
+// ================================
+// 🧹 Pembersihan
+// ================================
+
+// StartCleanup menghapus token kedaluwarsa secara berkala sebagai worker background
+func StartCleanup(interval time.Duration) {
+	lifecycle.Go("auth-token-cleanup", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				now := time.Now()
+				if err := config.DB.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+					log.Printf("gagal membersihkan revoked_tokens: %v", err)
+				}
+				if err := config.DB.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RefreshToken{}).Error; err != nil {
+					log.Printf("gagal membersihkan refresh_tokens: %v", err)
+				}
+				if err := config.DB.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.Session{}).Error; err != nil {
+					log.Printf("gagal membersihkan sessions: %v", err)
+				}
+				if err := config.DB.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.OIDCState{}).Error; err != nil {
+					log.Printf("gagal membersihkan oidc_states: %v", err)
+				}
+			}
+		}
+	})
+}
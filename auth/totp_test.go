@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"errors"
+	"go-crud/models"
+	"testing"
+	"time"
+)
+
+// secret RFC 6238 lampiran B untuk SHA1: ASCII "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func rfcKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := base32NoPad.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// Vektor RFC 6238 (8 digit); kode 6 digit adalah 6 digit terakhirnya
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key := rfcKey(t)
+	for _, tc := range []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},          // 94287082
+		{1111111109, "081804"},  // 07081804
+		{1111111111, "050471"},  // 14050471
+		{1234567890, "005924"},  // 89005924
+		{2000000000, "279037"},  // 69279037
+		{20000000000, "353130"}, // 65353130
+	} {
+		if got := totpCode(key, totpStep(time.Unix(tc.unix, 0))); got != tc.want {
+			t.Errorf("T=%d: kode %s, seharusnya %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	key := rfcKey(t)
+	now := time.Unix(1111111111, 0)
+	current := totpStep(now)
+
+	cases := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{"step sekarang", 0, true},
+		{"satu step sebelumnya", -1, true},
+		{"satu step sesudahnya", 1, true},
+		{"dua step sebelumnya", -2, false},
+		{"dua step sesudahnya", 2, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			code := totpCode(key, current+tc.offset)
+			step, ok := validateTOTP(rfcSecret, code, now, 0)
+			if ok != tc.ok {
+				t.Fatalf("ok = %v, seharusnya %v", ok, tc.ok)
+			}
+			if ok && step != current+tc.offset {
+				t.Errorf("step %d, seharusnya %d", step, current+tc.offset)
+			}
+		})
+	}
+
+	// spasi dari aplikasi authenticator dan secret huruf kecil tetap diterima
+	if _, ok := validateTOTP("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", " 050 471 ", now, 0); !ok {
+		t.Error("kode dengan spasi dan secret huruf kecil ditolak")
+	}
+	for _, code := range []string{"", "05047", "0504711", "abcdef"} {
+		if _, ok := validateTOTP(rfcSecret, code, now, 0); ok {
+			t.Errorf("kode %q seharusnya ditolak", code)
+		}
+	}
+}
+
+// Step yang tidak lebih besar dari last_used_step ditolak
+func TestValidateTOTPReplay(t *testing.T) {
+	key := rfcKey(t)
+	now := time.Unix(1111111111, 0)
+	current := totpStep(now)
+
+	cases := []struct {
+		name     string
+		offset   int64
+		lastStep int64
+		ok       bool
+	}{
+		{"belum pernah dipakai", 0, current - 1, true},
+		{"kode yang sama dipakai ulang", 0, current, false},
+		{"kode lama setelah kode baru dipakai", -1, current, false},
+		{"kode berikutnya setelah kode sekarang dipakai", 1, current, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := validateTOTP(rfcSecret, totpCode(key, current+tc.offset), now, tc.lastStep)
+			if ok != tc.ok {
+				t.Errorf("ok = %v, seharusnya %v", ok, tc.ok)
+			}
+		})
+	}
+}
+
+// VerifySecondFactor menyimpan last_used_step sehingga kode yang sama tidak
+// bisa dipakai dua kali, dan recovery code hanya berlaku sekali
+func TestVerifySecondFactorReplay(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "totp@example.com")
+	enabled := time.Now()
+	if err := db.Create(&models.UserTwoFactor{IDUser: user.ID, Secret: rfcSecret, EnabledAt: &enabled}).Error; err != nil {
+		t.Fatal(err)
+	}
+	recovery, err := RegenerateRecoveryCodes(db, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step := totpStep(time.Now())
+	code := totpCode(rfcKey(t), step)
+	if err := VerifySecondFactor(db, user.ID, code); err != nil {
+		t.Fatalf("kode pertama ditolak: %v", err)
+	}
+	var row models.UserTwoFactor
+	db.First(&row, "id_user = ?", user.ID)
+	if row.LastUsedStep < step {
+		t.Errorf("last_used_step %d, seharusnya minimal %d", row.LastUsedStep, step)
+	}
+	if err := VerifySecondFactor(db, user.ID, code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("kode yang sama dipakai ulang: err %v, seharusnya ErrInvalidTwoFactorCode", err)
+	}
+
+	if err := VerifySecondFactor(db, user.ID, recovery[0]); err != nil {
+		t.Fatalf("recovery code ditolak: %v", err)
+	}
+	if err := VerifySecondFactor(db, user.ID, recovery[0]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("recovery code dipakai ulang: err %v, seharusnya ErrInvalidTwoFactorCode", err)
+	}
+
+	other := createTestUser(t, db, "tanpa2fa@example.com")
+	if err := VerifySecondFactor(db, other.ID, code); !errors.Is(err, ErrTwoFactorNotEnabled) {
+		t.Errorf("user tanpa 2FA: err %v, seharusnya ErrTwoFactorNotEnabled", err)
+	}
+}
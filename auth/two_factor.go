@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"go-crud/config"
+	"go-crud/models"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("2FA sudah aktif")
+	ErrTwoFactorNotEnabled     = errors.New("2FA belum aktif")
+	ErrTwoFactorNotSetup       = errors.New("jalankan setup 2FA terlebih dahulu")
+	ErrInvalidTwoFactorCode    = errors.New("kode 2FA salah atau sudah dipakai")
+	ErrInvalidChallenge        = errors.New("challenge token tidak valid atau sudah kedaluwarsa")
+)
+
+// jumlah recovery code yang dibuat setiap kali enable / regenerate
+const recoveryCodeCount = 10
+
+// claim typ untuk membedakan challenge token dari access token
+const challengeTokenType = "2fa_challenge"
+
+// RequiresTwoFactor true kalau user punya role yang wajib 2FA (two_factor.required_roles)
+func RequiresTwoFactor(user models.User) bool {
+	for _, role := range config.App.TwoFactor.RequiredRoles {
+		if user.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// ================================
+// 🔹 Enrollment
+// ================================
+
+// BeginTOTPSetup membuat secret baru (belum aktif) dan URI untuk QR code.
+// Memanggil ulang sebelum enable akan mengganti secret sebelumnya.
+func BeginTOTPSetup(tx *gorm.DB, user models.User) (secret, uri string, err error) {
+	var current models.UserTwoFactor
+	err = tx.Where("id_user = ?", user.ID).First(&current).Error
+	switch {
+	case err == nil && current.EnabledAt != nil:
+		return "", "", ErrTwoFactorAlreadyEnabled
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		return "", "", err
+	}
+
+	secret, err = NewTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+	row := models.UserTwoFactor{IDUser: user.ID, Secret: secret}
+	if err := tx.Save(&row).Error; err != nil {
+		return "", "", err
+	}
+	return secret, ProvisioningURI(config.App.TwoFactor.Issuer, user.Email, secret), nil
+}
+
+// EnableTOTP mengaktifkan 2FA setelah kode pertama dari aplikasi authenticator
+// cocok, lalu mengembalikan recovery code (hanya ditampilkan sekali)
+func EnableTOTP(tx *gorm.DB, userID uint64, code string) ([]string, error) {
+	var row models.UserTwoFactor
+	if err := tx.Where("id_user = ?", userID).First(&row).Error; err != nil {
+		return nil, ErrTwoFactorNotSetup
+	}
+	if row.EnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := validateTOTP(row.Secret, code, time.Now(), row.LastUsedStep)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	now := time.Now()
+	if err := tx.Model(&row).Updates(map[string]interface{}{
+		"enabled_at":     now,
+		"last_used_step": step,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return RegenerateRecoveryCodes(tx, userID)
+}
+
+// DisableTOTP menghapus secret dan semua recovery code
+func DisableTOTP(tx *gorm.DB, userID uint64) error {
+	if err := tx.Where("id_user = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id_user = ?", userID).Delete(&models.UserTwoFactor{}).Error
+}
+
+// ================================
+// 🔹 Verifikasi Kode
+// ================================
+
+// VerifySecondFactor menerima kode TOTP 6 digit atau recovery code
+func VerifySecondFactor(tx *gorm.DB, userID uint64, code string) error {
+	var row models.UserTwoFactor
+	if err := tx.Where("id_user = ?", userID).First(&row).Error; err != nil || row.EnabledAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := validateTOTP(row.Secret, code, time.Now(), row.LastUsedStep); ok {
+		// last_used_step < step mencegah kode yang sama lolos di dua request bersamaan
+		res := tx.Model(&models.UserTwoFactor{}).
+			Where("id_user = ? AND last_used_step < ?", userID, step).
+			Update("last_used_step", step)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	return useRecoveryCode(tx, userID, code)
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func useRecoveryCode(tx *gorm.DB, userID uint64, code string) error {
+	code = normalizeRecoveryCode(code)
+	if code == "" {
+		return ErrInvalidTwoFactorCode
+	}
+	res := tx.Model(&models.RecoveryCode{}).
+		Where("id_user = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(code)).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes mengganti semua recovery code lama dengan yang baru
+func RegenerateRecoveryCodes(tx *gorm.DB, userID uint64) ([]string, error) {
+	if err := tx.Where("id_user = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	rows := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		secret, err := NewTOTPSecret()
+		if err != nil {
+			return nil, err
+		}
+		// 10 karakter base32 (50 bit), ditampilkan sebagai xxxxx-xxxxx
+		raw := strings.ToLower(secret[:10])
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		rows = append(rows, models.RecoveryCode{IDUser: userID, CodeHash: hashToken(raw)})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// RemainingRecoveryCodes menghitung recovery code yang belum dipakai
+func RemainingRecoveryCodes(userID uint64) (int64, error) {
+	var count int64
+	err := config.DB.Model(&models.RecoveryCode{}).
+		Where("id_user = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// ================================
+// 🔹 Challenge Token
+// ================================
+
+// NewChallengeToken dikirim Login ke user yang 2FA-nya aktif. Token ini bukan
+// access token (tidak punya user_id) sehingga ditolak middleware.AttachUser.
+func NewChallengeToken(user models.User) (string, int64, error) {
+	jti, err := randomString(16)
+	if err != nil {
+		return "", 0, err
+	}
+
+	ttl := config.App.TwoFactor.ChallengeTTL
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"typ": challengeTokenType,
+		"sub": fmt.Sprint(user.ID),
+		"tv":  user.TokenVersion,
+		"jti": jti,
+		"iat": now.Unix(),
+		"exp": now.Add(ttl).Unix(),
+	}
+	token, err := Keys.Sign(claims)
+	return token, int64(ttl.Seconds()), err
+}
+
+// ChallengeClaims hasil parse challenge token yang masih berlaku
+type ChallengeClaims struct {
+	UserID       uint64
+	TokenVersion int
+	JTI          string
+	ExpiresAt    time.Time
+}
+
+func ParseChallengeToken(raw string) (*ChallengeClaims, error) {
+	token, err := ParseToken(raw)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidChallenge
+	}
+	if typ, _ := claims["typ"].(string); typ != challengeTokenType {
+		return nil, ErrInvalidChallenge
+	}
+
+	var result ChallengeClaims
+	sub, _ := claims.GetSubject()
+	if _, err := fmt.Sscan(sub, &result.UserID); err != nil {
+		return nil, ErrInvalidChallenge
+	}
+	tv, _ := claims["tv"].(float64)
+	result.TokenVersion = int(tv)
+	result.JTI, _ = claims["jti"].(string)
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+
+	// challenge hanya boleh dipakai sekali
+	revoked, err := IsAccessTokenRevoked(result.JTI)
+	if err != nil {
+		return nil, err
+	}
+	if revoked || result.JTI == "" {
+		return nil, ErrInvalidChallenge
+	}
+	return &result, nil
+}
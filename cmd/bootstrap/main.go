@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go-crud/config"
+	"go-crud/models"
+	"log"
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Membuat akun admin pertama. /register tidak lagi bisa membuat admin, jadi
+// command ini dipakai sekali saat deploy baru:
+//
+//	ADMIN_PASSWORD=rahasia go run ./cmd/bootstrap -email admin@toko.id -nama "Admin"
+func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	email := fs.String("email", "", "email admin (wajib)")
+	nama := fs.String("nama", "Administrator", "nama admin")
+	force := fs.Bool("force", false, "tetap buat admin walaupun sudah ada admin lain")
+
+	cfg, err := config.LoadFlags(fs, os.Args[1:])
+	if err != nil {
+		log.Fatal("Gagal load konfigurasi: ", err)
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		log.Fatal("Konfigurasi database tidak valid:\n", err)
+	}
+
+	// kata sandi lewat env supaya tidak tersimpan di history shell
+	password := os.Getenv("ADMIN_PASSWORD")
+	if *email == "" || password == "" {
+		log.Fatal("Flag -email dan env ADMIN_PASSWORD wajib diisi")
+	}
+	if len(password) < 8 {
+		log.Fatal("ADMIN_PASSWORD minimal 8 karakter")
+	}
+
+	config.ConnectDatabase(cfg.Database)
+
+	var adminCount int64
+	config.DB.Model(&models.UserRole{}).Where("role = ?", models.RoleAdmin).Count(&adminCount)
+	if adminCount > 0 && !*force {
+		log.Fatalf("Sudah ada %d admin, gunakan -force untuk menambah admin lewat command ini", adminCount)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatal("Gagal hash password: ", err)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Where("email = ?", *email).First(&user).Error
+		switch {
+		case err == nil:
+			// user sudah ada: cukup tambahkan role admin
+			fmt.Println("User sudah terdaftar, menambahkan role admin")
+		case err == gorm.ErrRecordNotFound:
+			user = models.User{Nama: *nama, Email: *email, KataSandi: string(hashed)}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		role := models.UserRole{IDUser: user.ID, Role: models.RoleAdmin}
+		return tx.Where(role).FirstOrCreate(&role).Error
+	})
+	if err != nil {
+		log.Fatal("Gagal membuat admin: ", err)
+	}
+
+	fmt.Println("✅ Admin siap:", *email)
+}
@@ -1,30 +1,152 @@
 package main
 
 import (
+	"fmt"
 	"go-crud/config"
-	"go-crud/models"
+	"go-crud/migrations"
 	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
-func main() {
-	config.ConnectDatabase()
-
-	err := config.DB.AutoMigrate(
-		&models.User{},
-		&models.Toko{},
-		&models.Alamat{},
-		&models.Category{},
-		&models.Produk{},
-		&models.FotoProduk{},
-		&models.LogProduk{},
-		&models.Trx{},
-		&models.DetailTrx{},
-	)
+const usage = `Pemakaian: migrate [flag konfigurasi] <perintah>
+
+Perintah:
+  up              jalankan semua migrasi yang belum dijalankan
+  down [n]        rollback n migrasi terakhir (default 1)
+  status          tampilkan status semua migrasi
+  create <nama>   buat file migrasi baru di folder migrations/`
 
+func main() {
+	cfg, args, err := config.LoadArgs(os.Args[0], os.Args[1:])
 	if err != nil {
-		log.Fatal("AutoMigrate failed:", err)
+		log.Fatal("Gagal load konfigurasi: ", err)
+	}
+	if len(args) == 0 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	// create tidak butuh koneksi database
+	if args[0] == "create" {
+		if len(args) < 2 {
+			log.Fatal("Nama migrasi wajib diisi, contoh: migrate create add_berat_produk")
+		}
+		path, err := createMigration("migrations", args[1])
+		if err != nil {
+			log.Fatal("Gagal membuat file migrasi: ", err)
+		}
+		fmt.Println("✅ File migrasi dibuat:", path)
+		return
 	}
 
-	println("All tables migrated successfully!")
+	if err := cfg.Database.Validate(); err != nil {
+		log.Fatal("Konfigurasi database tidak valid:\n", err)
+	}
+	config.ConnectDatabase(cfg.Database)
+
+	switch args[0] {
+	case "up":
+		ran, err := migrations.Up(config.DB)
+		for _, m := range ran {
+			fmt.Printf("✅ %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(ran) == 0 {
+			fmt.Println("Tidak ada migrasi baru")
+		}
+
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			n, err = strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatal("Jumlah rollback harus angka >= 1")
+			}
+		}
+		reverted, err := migrations.Down(config.DB, n)
+		for _, m := range reverted {
+			fmt.Printf("↩️  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("Tidak ada migrasi untuk di-rollback")
+		}
+
+	case "status":
+		list, err := migrations.GetStatus(config.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range list {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Missing {
+				state += " (file migrasi tidak ditemukan)"
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, state)
+		}
+
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
 
-}
\ No newline at end of file
+var validName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+const migrationTemplate = `package migrations
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: %d,
+		Name:    %q,
+		Up: func(tx *gorm.DB) error {
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
+`
+
+func createMigration(dir, name string) (string, error) {
+	name = strings.ToLower(name)
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("nama migrasi hanya boleh huruf kecil, angka dan underscore: %q", name)
+	}
+
+	// ambil nomor terbesar dari migrasi terdaftar maupun file yang belum ter-compile
+	version := migrations.LatestVersion()
+	files, _ := filepath.Glob(filepath.Join(dir, "[0-9]*_*.go"))
+	for _, f := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(f), "_")
+		if v, err := strconv.ParseInt(prefix, 10, 64); err == nil && v > version {
+			version = v
+		}
+	}
+	version++
+
+	path := filepath.Join(dir, fmt.Sprintf("%04d_%s.go", version, name))
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("file %s sudah ada", path)
+	}
+
+	content := fmt.Sprintf(migrationTemplate, version, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
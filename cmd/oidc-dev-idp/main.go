@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"go-crud/oidc/devidp"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Identity provider OIDC minimal untuk development & test lokal login SSO,
+// lihat package oidc/devidp.
+//
+//	go run ./cmd/oidc-dev-idp -addr :9000 -redirect-uri http://localhost:8080/oidc/callback
+//
+// lalu jalankan server dengan:
+//
+//	OIDC_ISSUER=http://localhost:9000 OIDC_CLIENT_ID=go-crud OIDC_CLIENT_SECRET=dev-secret \
+//	OIDC_REDIRECT_URL=http://localhost:8080/oidc/callback go run .
+//
+// JANGAN dipakai di produksi.
+func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	addr := fs.String("addr", ":9000", "alamat listen")
+	issuer := fs.String("issuer", "", "issuer (default http://localhost<addr>)")
+	clientID := fs.String("client-id", "go-crud", "client_id yang diterima")
+	clientSecret := fs.String("client-secret", "dev-secret", "client_secret, kosong = public client (PKCE saja)")
+	redirects := fs.String("redirect-uri", "http://localhost:8080/oidc/callback", "redirect_uri yang diizinkan, pisahkan dengan koma")
+	fs.Parse(os.Args[1:])
+
+	if *issuer == "" {
+		*issuer = "http://localhost" + *addr
+	}
+
+	idp, err := devidp.New(devidp.Config{
+		Issuer:       *issuer,
+		ClientID:     *clientID,
+		ClientSecret: *clientSecret,
+		RedirectURIs: strings.Split(*redirects, ","),
+	})
+	if err != nil {
+		log.Fatal("Gagal membuat key: ", err)
+	}
+
+	log.Printf("🔑 OIDC dev IdP di %s (issuer %s, client_id %s)", *addr, idp.Issuer(), *clientID)
+	log.Fatal(http.ListenAndServe(*addr, idp))
+}
@@ -0,0 +1,70 @@
+package main
+
+// Data statis untuk generator seed. Semua id provinsi/kota di bawah adalah
+// kode wilayah yang valid di API emsifa (utils.GetProvinceByID / GetCityByID),
+// sehingga tidak perlu akses internet saat seeding.
+
+type wilayah struct {
+	IDProvinsi string
+	IDKota     string
+	NamaKota   string
+}
+
+var daftarWilayah = []wilayah{
+	{"11", "1171", "Kota Banda Aceh"},
+	{"12", "1275", "Kota Medan"},
+	{"31", "3171", "Kota Jakarta Selatan"},
+	{"31", "3173", "Kota Jakarta Pusat"},
+	{"32", "3273", "Kota Bandung"},
+	{"32", "3275", "Kota Bekasi"},
+	{"33", "3374", "Kota Semarang"},
+	{"34", "3471", "Kota Yogyakarta"},
+	{"35", "3578", "Kota Surabaya"},
+	{"35", "3573", "Kota Malang"},
+	{"51", "5171", "Kota Denpasar"},
+	{"73", "7371", "Kota Makassar"},
+}
+
+var namaDepan = []string{
+	"Andi", "Budi", "Citra", "Dewi", "Eka", "Fajar", "Gita", "Hendra", "Indah", "Joko",
+	"Kartika", "Lestari", "Made", "Nur", "Oki", "Putri", "Rizky", "Sari", "Taufik", "Wulan",
+}
+
+var namaBelakang = []string{
+	"Saputra", "Wijaya", "Pratama", "Lestari", "Nugroho", "Hidayat", "Kusuma", "Siregar",
+	"Santoso", "Permata", "Halim", "Utami", "Setiawan", "Rahmawati",
+}
+
+var daftarPekerjaan = []string{
+	"Wiraswasta", "Karyawan Swasta", "Mahasiswa", "Guru", "Desainer", "Programmer", "Pedagang",
+}
+
+var judulAlamat = []string{"Rumah", "Kantor", "Kos", "Rumah Orang Tua"}
+
+var namaJalan = []string{
+	"Jl. Merdeka", "Jl. Sudirman", "Jl. Diponegoro", "Jl. Gajah Mada", "Jl. Ahmad Yani",
+	"Jl. Pahlawan", "Jl. Melati", "Jl. Kenanga", "Jl. Cempaka", "Jl. Veteran",
+}
+
+var metodeBayar = []string{"transfer_bank", "cod", "e_wallet", "virtual_account"}
+
+// kategori beserta nama barang dan kisaran harga konsumen (rupiah)
+type kategori struct {
+	Nama     string
+	Barang   []string
+	HargaMin int
+	HargaMax int
+}
+
+var daftarKategori = []kategori{
+	{"Elektronik", []string{"Earphone Bluetooth", "Power Bank", "Kabel Data", "Speaker Mini", "Mouse Wireless"}, 25000, 750000},
+	{"Fashion Pria", []string{"Kemeja Flanel", "Kaos Polos", "Celana Chino", "Jaket Bomber", "Sepatu Sneakers"}, 50000, 450000},
+	{"Fashion Wanita", []string{"Gamis", "Blouse", "Rok Plisket", "Tas Selempang", "Hijab Segi Empat"}, 35000, 400000},
+	{"Makanan & Minuman", []string{"Keripik Pedas", "Kopi Bubuk", "Sambal Botol", "Madu Hutan", "Teh Celup"}, 10000, 150000},
+	{"Kesehatan & Kecantikan", []string{"Masker Wajah", "Sabun Herbal", "Minyak Kayu Putih", "Serum Vitamin C"}, 15000, 250000},
+	{"Rumah Tangga", []string{"Rak Piring", "Sprei Katun", "Panci Set", "Lampu Tidur", "Keset Kaki"}, 20000, 500000},
+	{"Olahraga", []string{"Matras Yoga", "Botol Minum", "Raket Badminton", "Sarung Tangan Gym"}, 25000, 600000},
+	{"Buku", []string{"Novel Remaja", "Buku Resep", "Komik", "Buku Belajar Go"}, 30000, 200000},
+}
+
+var sifatBarang = []string{"Premium", "Original", "Murah", "Terlaris", "Edisi Terbatas", "Import", "Lokal"}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go-crud/config"
+	"go-crud/migrations"
+	"go-crud/models"
+	"go-crud/rbac"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Semua akun seed memakai domain ini supaya mudah dikenali
+const seedEmailDomain = "seed.go-crud.test"
+
+type options struct {
+	Admins          int
+	Staff           int
+	Sellers         int
+	Buyers          int
+	ProductsPerToko int
+	Transactions    int
+	Seed            int64
+	Password        string
+	Reset           bool
+}
+
+func main() {
+	var opt options
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.IntVar(&opt.Admins, "admins", 1, "jumlah akun admin")
+	fs.IntVar(&opt.Staff, "staff", 1, "jumlah akun support dan finance (masing-masing)")
+	fs.IntVar(&opt.Sellers, "sellers", 5, "jumlah penjual (masing-masing otomatis punya toko)")
+	fs.IntVar(&opt.Buyers, "buyers", 10, "jumlah pembeli")
+	fs.IntVar(&opt.ProductsPerToko, "products", 8, "jumlah produk per toko")
+	fs.IntVar(&opt.Transactions, "transactions", 30, "jumlah transaksi historis")
+	fs.Int64Var(&opt.Seed, "seed", 42, "seed random, nilai sama menghasilkan data yang sama")
+	fs.StringVar(&opt.Password, "password", "password123", "kata sandi untuk semua akun seed")
+	fs.BoolVar(&opt.Reset, "reset", false, "hapus SEMUA data di tabel aplikasi sebelum seeding")
+
+	cfg, err := config.LoadFlags(fs, os.Args[1:])
+	if err != nil {
+		log.Fatal("Gagal load konfigurasi: ", err)
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		log.Fatal("Konfigurasi database tidak valid:\n", err)
+	}
+	config.ConnectDatabase(cfg.Database)
+
+	if err := ensureMigrated(config.DB); err != nil {
+		log.Fatal(err)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if opt.Reset {
+			if err := reset(tx); err != nil {
+				return fmt.Errorf("gagal reset data: %v", err)
+			}
+		} else {
+			var count int64
+			tx.Model(&models.User{}).Where("email LIKE ?", "%@"+seedEmailDomain).Count(&count)
+			if count > 0 {
+				return fmt.Errorf("data seed sudah ada (%d user), jalankan ulang dengan -reset", count)
+			}
+		}
+		return newSeeder(tx, opt).run()
+	})
+	if err != nil {
+		log.Fatal("Seeding gagal: ", err)
+	}
+}
+
+func ensureMigrated(db *gorm.DB) error {
+	list, err := migrations.GetStatus(db)
+	if err != nil {
+		return err
+	}
+	for _, s := range list {
+		if s.AppliedAt == nil {
+			return fmt.Errorf("migrasi %04d_%s belum dijalankan, jalankan `go run ./cmd/migrate up` dulu", s.Version, s.Name)
+		}
+	}
+	return nil
+}
+
+// urutan kebalikan dari dependensi foreign key
+func reset(tx *gorm.DB) error {
+	tables := []interface{}{
+		&models.DetailTrx{},
+		&models.Trx{},
+		&models.LogProduk{},
+		&models.FotoProduk{},
+		&models.Produk{},
+		&models.Category{},
+		&models.Alamat{},
+		&models.APIKey{},
+		&models.Toko{},
+		&models.RecoveryCode{},
+		&models.UserTwoFactor{},
+		&models.UserToken{},
+		&models.OIDCState{},
+		&models.UserIdentity{},
+		&models.Session{},
+		&models.RevokedToken{},
+		&models.RefreshToken{},
+		&models.UserRole{},
+		&models.User{},
+	}
+	for _, t := range tables {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(t).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ================================
+// 🌱 Seeder
+// ================================
+
+type seeder struct {
+	tx   *gorm.DB
+	opt  options
+	rnd  *rand.Rand
+	hash string
+	// waktu acuan tetap supaya tanggal transaksi juga deterministik
+	now time.Time
+
+	sellers    []models.User
+	buyers     []models.User
+	tokos      []models.Toko
+	categories []models.Category
+	products   []models.Produk
+	alamat     map[uint64][]models.Alamat
+}
+
+func newSeeder(tx *gorm.DB, opt options) *seeder {
+	return &seeder{
+		tx:     tx,
+		opt:    opt,
+		rnd:    rand.New(rand.NewSource(opt.Seed)),
+		now:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local),
+		alamat: map[uint64][]models.Alamat{},
+	}
+}
+
+func (s *seeder) run() error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(s.opt.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	s.hash = string(hashed)
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"admin & staff", s.seedAdmins},
+		{"penjual & toko", s.seedSellers},
+		{"pembeli", s.seedBuyers},
+		{"alamat", s.seedAlamat},
+		{"kategori", s.seedCategories},
+		{"produk & foto", s.seedProducts},
+		{"transaksi", s.seedTransactions},
+	}
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			return fmt.Errorf("seed %s: %v", step.name, err)
+		}
+		fmt.Printf("✅ Seed %s selesai\n", step.name)
+	}
+
+	fmt.Printf("\nLogin dengan email <role><n>@%s dan kata sandi %q\n", seedEmailDomain, s.opt.Password)
+	return nil
+}
+
+func (s *seeder) pick(list []string) string {
+	return list[s.rnd.Intn(len(list))]
+}
+
+func (s *seeder) newUser(prefix string, n int, roles ...string) models.User {
+	nama := s.pick(namaDepan) + " " + s.pick(namaBelakang)
+	wil := daftarWilayah[s.rnd.Intn(len(daftarWilayah))]
+	noTelp := fmt.Sprintf("08%02d%08d", 11+s.rnd.Intn(89), s.rnd.Intn(100000000))
+	jk := models.JenisKelaminLakiLaki
+	if s.rnd.Intn(2) == 0 {
+		jk = models.JenisKelaminPerempuan
+	}
+	lahir := time.Date(1970+s.rnd.Intn(35), time.Month(1+s.rnd.Intn(12)), 1+s.rnd.Intn(28), 0, 0, 0, 0, time.Local)
+	pekerjaan := s.pick(daftarPekerjaan)
+
+	user := models.User{
+		Nama:         nama,
+		KataSandi:    s.hash,
+		NoTelp:       &noTelp,
+		TanggalLahir: &lahir,
+		JenisKelamin: &jk,
+		Pekerjaan:    &pekerjaan,
+		Email:        fmt.Sprintf("%s%d@%s", prefix, n, seedEmailDomain),
+		IDProvinsi:   &wil.IDProvinsi,
+		IDKota:       &wil.IDKota,
+	}
+	for _, role := range roles {
+		user.Roles = append(user.Roles, models.UserRole{Role: role})
+	}
+	return user
+}
+
+func (s *seeder) seedAdmins() error {
+	for i := 1; i <= s.opt.Admins; i++ {
+		user := s.newUser("admin", i, models.RoleAdmin)
+		if err := s.tx.Create(&user).Error; err != nil {
+			return err
+		}
+	}
+	for i := 1; i <= s.opt.Staff; i++ {
+		for _, role := range []string{models.RoleSupport, models.RoleFinance} {
+			user := s.newUser(role, i, role)
+			if err := s.tx.Create(&user).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedSellers() error {
+	for i := 1; i <= s.opt.Sellers; i++ {
+		user := s.newUser("seller", i, rbac.DefaultRoles...)
+		if err := s.tx.Create(&user).Error; err != nil {
+			return err
+		}
+		s.sellers = append(s.sellers, user)
+
+		// sama seperti Register: user non-admin otomatis punya toko
+		toko := models.Toko{
+			IDUser:   user.ID,
+			NamaToko: "Toko " + user.Nama,
+		}
+		if err := s.tx.Create(&toko).Error; err != nil {
+			return err
+		}
+		s.tokos = append(s.tokos, toko)
+	}
+	return nil
+}
+
+func (s *seeder) seedBuyers() error {
+	for i := 1; i <= s.opt.Buyers; i++ {
+		user := s.newUser("buyer", i, rbac.DefaultRoles...)
+		if err := s.tx.Create(&user).Error; err != nil {
+			return err
+		}
+		s.buyers = append(s.buyers, user)
+
+		// pembeli juga dapat toko (kosong) seperti alur Register
+		toko := models.Toko{
+			IDUser:   user.ID,
+			NamaToko: "Toko " + user.Nama,
+		}
+		if err := s.tx.Create(&toko).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedAlamat() error {
+	users := append(append([]models.User{}, s.sellers...), s.buyers...)
+	for _, user := range users {
+		jumlah := 1 + s.rnd.Intn(2)
+		for i := 0; i < jumlah; i++ {
+			wil := daftarWilayah[s.rnd.Intn(len(daftarWilayah))]
+			if i == 0 {
+				// alamat pertama mengikuti domisili user
+				for _, w := range daftarWilayah {
+					if w.IDKota == *user.IDKota {
+						wil = w
+						break
+					}
+				}
+			}
+			alamat := models.Alamat{
+				IDUser:       user.ID,
+				JudulAlamat:  judulAlamat[i%len(judulAlamat)],
+				NamaPenerima: user.Nama,
+				NoTelp:       *user.NoTelp,
+				DetailAlamat: fmt.Sprintf("%s No. %d, %s", s.pick(namaJalan), 1+s.rnd.Intn(200), wil.NamaKota),
+			}
+			if err := s.tx.Create(&alamat).Error; err != nil {
+				return err
+			}
+			s.alamat[user.ID] = append(s.alamat[user.ID], alamat)
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedCategories() error {
+	for _, k := range daftarKategori {
+		category := models.Category{NamaCategory: k.Nama}
+		if err := s.tx.Where(models.Category{NamaCategory: k.Nama}).FirstOrCreate(&category).Error; err != nil {
+			return err
+		}
+		s.categories = append(s.categories, category)
+	}
+	return nil
+}
+
+func (s *seeder) seedProducts() error {
+	for _, toko := range s.tokos {
+		for i := 1; i <= s.opt.ProductsPerToko; i++ {
+			idx := s.rnd.Intn(len(daftarKategori))
+			k := daftarKategori[idx]
+			categoryID := s.categories[idx].ID
+
+			nama := fmt.Sprintf("%s %s", s.pick(k.Barang), s.pick(sifatBarang))
+			// harga dibulatkan ke ribuan, reseller lebih murah 10-25%
+			harga := (k.HargaMin + s.rnd.Intn(k.HargaMax-k.HargaMin)) / 1000 * 1000
+			hargaReseller := harga * (75 + s.rnd.Intn(16)) / 100 / 500 * 500
+			deskripsi := fmt.Sprintf("%s dari %s. Kualitas terjamin, siap kirim dari %s.", nama, toko.NamaToko, k.Nama)
+
+			produk := models.Produk{
+				NamaProduk: nama,
+				// slug dibuat unik per toko karena kolom slug unique
+				Slug:          fmt.Sprintf("%s-%d-%d", strings.ToLower(strings.ReplaceAll(nama, " ", "-")), toko.ID, i),
+				HargaReseller: hargaReseller,
+				HargaKonsumen: harga,
+				Stok:          10 + s.rnd.Intn(190),
+				Deskripsi:     &deskripsi,
+				IDToko:        toko.ID,
+				IDCategory:    &categoryID,
+			}
+			if err := s.tx.Create(&produk).Error; err != nil {
+				return err
+			}
+
+			jumlahFoto := 1 + s.rnd.Intn(3)
+			for f := 1; f <= jumlahFoto; f++ {
+				foto := models.FotoProduk{
+					IDProduk: produk.ID,
+					URL:      fmt.Sprintf("https://picsum.photos/seed/%s-%d/600/600", produk.Slug, f),
+				}
+				if err := s.tx.Create(&foto).Error; err != nil {
+					return err
+				}
+			}
+			s.products = append(s.products, produk)
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedTransactions() error {
+	if len(s.buyers) == 0 || len(s.products) == 0 {
+		return nil
+	}
+
+	for i := 1; i <= s.opt.Transactions; i++ {
+		buyer := s.buyers[s.rnd.Intn(len(s.buyers))]
+		alamatList := s.alamat[buyer.ID]
+		alamatID := alamatList[s.rnd.Intn(len(alamatList))].ID
+		metode := s.pick(metodeBayar)
+		// tersebar di 180 hari ke belakang dari waktu acuan
+		createdAt := s.now.Add(-time.Duration(s.rnd.Intn(180*24)) * time.Hour)
+
+		trx := models.Trx{
+			IDUser:           buyer.ID,
+			AlamatPengiriman: &alamatID,
+			KodeInvoice:      fmt.Sprintf("INV-%d-%04d", createdAt.Unix(), i),
+			MethodBayar:      &metode,
+			CreatedAt:        createdAt,
+			UpdatedAt:        createdAt,
+		}
+		if err := s.tx.Create(&trx).Error; err != nil {
+			return err
+		}
+
+		total := 0
+		jumlahItem := 1 + s.rnd.Intn(3)
+		for j := 0; j < jumlahItem; j++ {
+			produk := &s.products[s.rnd.Intn(len(s.products))]
+			qty := 1 + s.rnd.Intn(3)
+			if produk.Stok < qty {
+				continue
+			}
+
+			// sama seperti CreateTransaction: snapshot produk ke log_produk lalu kurangi stok
+			logProduk := models.LogProduk{
+				IDProduk:      produk.ID,
+				NamaProduk:    produk.NamaProduk,
+				Slug:          produk.Slug,
+				HargaReseller: produk.HargaReseller,
+				HargaKonsumen: produk.HargaKonsumen,
+				Deskripsi:     produk.Deskripsi,
+				IDToko:        produk.IDToko,
+				IDCategory:    produk.IDCategory,
+				CreatedAt:     createdAt,
+				UpdatedAt:     createdAt,
+			}
+			if err := s.tx.Create(&logProduk).Error; err != nil {
+				return err
+			}
+
+			subtotal := qty * produk.HargaKonsumen
+			detail := models.DetailTrx{
+				IDTrx:       trx.ID,
+				IDLogProduk: logProduk.ID,
+				IDToko:      produk.IDToko,
+				Kuantitas:   qty,
+				HargaTotal:  subtotal,
+				CreatedAt:   createdAt,
+				UpdatedAt:   createdAt,
+			}
+			if err := s.tx.Create(&detail).Error; err != nil {
+				return err
+			}
+
+			produk.Stok -= qty
+			if err := s.tx.Model(produk).Update("stok", produk.Stok).Error; err != nil {
+				return err
+			}
+			total += subtotal
+		}
+
+		if err := s.tx.Model(&trx).UpdateColumn("harga_total", total).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,839 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ================================
+// ⚙️ Struct Konfigurasi
+// ================================
+type Config struct {
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	JWT       JWTConfig       `yaml:"jwt" toml:"jwt"`
+	External  ExternalConfig  `yaml:"external" toml:"external"`
+	Mail      MailConfig      `yaml:"mail" toml:"mail"`
+	TwoFactor TwoFactorConfig `yaml:"two_factor" toml:"two_factor"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	OIDC      OIDCConfig      `yaml:"oidc" toml:"oidc"`
+	Search    SearchConfig    `yaml:"search" toml:"search"`
+	Storage   StorageConfig   `yaml:"storage" toml:"storage"`
+	Image     ImageConfig     `yaml:"image" toml:"image"`
+}
+
+type ServerConfig struct {
+	Addr              string        `yaml:"addr" toml:"addr"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// batas waktu menunggu request yang sedang berjalan saat SIGINT/SIGTERM
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// isi keduanya untuk menjalankan HTTPS
+	TLSCertFile string `yaml:"tls_cert_file" toml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file" toml:"tls_key_file"`
+	// true kalau server di belakang reverse proxy, IP client diambil dari X-Forwarded-For
+	TrustProxy bool `yaml:"trust_proxy" toml:"trust_proxy"`
+	// true = semua respons error memakai format RFC 7807 (application/problem+json).
+	// Kalau false, format itu hanya dipakai bila client mengirim Accept: application/problem+json
+	ProblemJSON bool `yaml:"problem_json" toml:"problem_json"`
+}
+
+type DatabaseConfig struct {
+	Driver          string        `yaml:"driver" toml:"driver"`
+	User            string        `yaml:"user" toml:"user"`
+	Password        string        `yaml:"password" toml:"password"`
+	Host            string        `yaml:"host" toml:"host"`
+	Port            string        `yaml:"port" toml:"port"`
+	Name            string        `yaml:"name" toml:"name"`
+	Params          string        `yaml:"params" toml:"params"`
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+}
+
+type JWTConfig struct {
+	// RS256 | EdDSA; key privat dibuat & dirotasi otomatis, public key-nya
+	// dipublikasikan di /.well-known/jwks.json
+	Algorithm string `yaml:"algorithm" toml:"algorithm"`
+	// klaim iss di setiap token, dicek saat verifikasi
+	Issuer string `yaml:"issuer" toml:"issuer"`
+	// key baru dibuat setiap rotation_interval, dan sudah dipublikasikan di JWKS
+	// selama prepublish sebelum mulai dipakai menandatangani token
+	RotationInterval time.Duration `yaml:"rotation_interval" toml:"rotation_interval"`
+	Prepublish       time.Duration `yaml:"prepublish" toml:"prepublish"`
+	// masa berlaku access token (dibuat singkat, diperbarui lewat refresh token)
+	TokenTTL   time.Duration `yaml:"token_ttl" toml:"token_ttl"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" toml:"refresh_ttl"`
+}
+
+type ExternalConfig struct {
+	WilayahBaseURL string `yaml:"wilayah_base_url" toml:"wilayah_base_url"`
+}
+
+type MailConfig struct {
+	// smtp | file | log (file & log dipakai untuk development / test offline)
+	Driver       string `yaml:"driver" toml:"driver"`
+	From         string `yaml:"from" toml:"from"`
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host"`
+	SMTPPort     string `yaml:"smtp_port" toml:"smtp_port"`
+	SMTPUser     string `yaml:"smtp_user" toml:"smtp_user"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password"`
+	// folder tujuan file .eml untuk driver file
+	FileDir string `yaml:"file_dir" toml:"file_dir"`
+	// URL frontend untuk link di email, contoh https://toko.id -> https://toko.id/verify-email?token=...
+	LinkBaseURL    string        `yaml:"link_base_url" toml:"link_base_url"`
+	VerifyTokenTTL time.Duration `yaml:"verify_token_ttl" toml:"verify_token_ttl"`
+	ResetTokenTTL  time.Duration `yaml:"reset_token_ttl" toml:"reset_token_ttl"`
+}
+
+type TwoFactorConfig struct {
+	// nama yang tampil di aplikasi authenticator (Google Authenticator, Authy, ...)
+	Issuer string `yaml:"issuer" toml:"issuer"`
+	// masa berlaku challenge token antara langkah password dan kode TOTP
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" toml:"challenge_ttl"`
+	// role yang wajib mengaktifkan 2FA sebelum bisa memakai API
+	RequiredRoles []string `yaml:"required_roles" toml:"required_roles"`
+}
+
+type RateLimitConfig struct {
+	// memory (default, satu instance) | redis (dibagi antar instance)
+	Store         string `yaml:"store" toml:"store"`
+	RedisAddr     string `yaml:"redis_addr" toml:"redis_addr"`
+	RedisPassword string `yaml:"redis_password" toml:"redis_password"`
+	RedisDB       int    `yaml:"redis_db" toml:"redis_db"`
+	KeyPrefix     string `yaml:"key_prefix" toml:"key_prefix"`
+
+	// batas request per IP untuk /login (termasuk /login/2fa)
+	LoginIPLimit  int           `yaml:"login_ip_limit" toml:"login_ip_limit"`
+	LoginIPWindow time.Duration `yaml:"login_ip_window" toml:"login_ip_window"`
+	// batas request per IP untuk /register dan endpoint email (lupa kata sandi, dst)
+	RegisterIPLimit  int           `yaml:"register_ip_limit" toml:"register_ip_limit"`
+	RegisterIPWindow time.Duration `yaml:"register_ip_window" toml:"register_ip_window"`
+
+	// per akun: setiap gagal login harus menunggu backoff_base * 2^(gagal-1)
+	// (maksimal backoff_max), setelah max_failures akun dikunci selama lockout_duration
+	MaxFailures     int           `yaml:"max_failures" toml:"max_failures"`
+	FailureWindow   time.Duration `yaml:"failure_window" toml:"failure_window"`
+	LockoutDuration time.Duration `yaml:"lockout_duration" toml:"lockout_duration"`
+	BackoffBase     time.Duration `yaml:"backoff_base" toml:"backoff_base"`
+	BackoffMax      time.Duration `yaml:"backoff_max" toml:"backoff_max"`
+}
+
+type OIDCConfig struct {
+	// issuer identity provider (SSO perusahaan), kosong = login SSO dimatikan.
+	// Endpoint lain dibaca dari {issuer}/.well-known/openid-configuration
+	Issuer       string `yaml:"issuer" toml:"issuer"`
+	ClientID     string `yaml:"client_id" toml:"client_id"`
+	ClientSecret string `yaml:"client_secret" toml:"client_secret"`
+	// URL callback yang didaftarkan di IdP, contoh https://api.toko.id/oidc/callback
+	RedirectURL string   `yaml:"redirect_url" toml:"redirect_url"`
+	Scopes      []string `yaml:"scopes" toml:"scopes"`
+	// true = akun lama dengan email yang sama (dan sudah diverifikasi IdP)
+	// otomatis ditautkan saat login SSO pertama kali
+	LinkByEmail bool `yaml:"link_by_email" toml:"link_by_email"`
+	// domain email yang boleh dibuatkan akun otomatis, kosong = semua domain
+	AllowedDomains []string `yaml:"allowed_domains" toml:"allowed_domains"`
+	// batas waktu user menyelesaikan login di halaman IdP
+	StateTTL time.Duration `yaml:"state_ttl" toml:"state_ttl"`
+}
+
+type SearchConfig struct {
+	// index pencarian produk dibangun ulang dari database setiap interval ini,
+	// untuk menangkap perubahan dari instance lain. 0 = hanya saat start
+	RefreshInterval time.Duration `yaml:"refresh_interval" toml:"refresh_interval"`
+}
+
+type StorageConfig struct {
+	// local (folder di server, disajikan di /uploads) | s3 (AWS S3, MinIO, ...)
+	Driver   string `yaml:"driver" toml:"driver"`
+	LocalDir string `yaml:"local_dir" toml:"local_dir"`
+	// awal URL file yang dikirim ke client, contoh https://cdn.toko.id.
+	// Kosong = /uploads (local) atau {s3_endpoint}/{s3_bucket} (s3)
+	PublicBaseURL string `yaml:"public_base_url" toml:"public_base_url"`
+	S3Endpoint    string `yaml:"s3_endpoint" toml:"s3_endpoint"`
+	S3Region      string `yaml:"s3_region" toml:"s3_region"`
+	S3Bucket      string `yaml:"s3_bucket" toml:"s3_bucket"`
+	S3AccessKey   string `yaml:"s3_access_key" toml:"s3_access_key"`
+	S3SecretKey   string `yaml:"s3_secret_key" toml:"s3_secret_key"`
+	// true = {endpoint}/{bucket}/{key} (wajib untuk MinIO), false = {bucket}.{host}/{key}
+	S3PathStyle bool `yaml:"s3_path_style" toml:"s3_path_style"`
+
+	// batas ukuran satu foto produk (byte) dan jumlah foto per produk
+	PhotoMaxSize  int `yaml:"photo_max_size" toml:"photo_max_size"`
+	PhotoMaxCount int `yaml:"photo_max_count" toml:"photo_max_count"`
+}
+
+type ImageConfig struct {
+	// jumlah worker yang membuat varian foto di background
+	Workers int `yaml:"workers" toml:"workers"`
+	// kapasitas antrean; kalau penuh foto tetap diproses oleh sweep berikutnya
+	QueueSize int `yaml:"queue_size" toml:"queue_size"`
+	// sisi terpanjang (px) varian thumbnail dan web, gambar kecil tidak diperbesar
+	ThumbSize int `yaml:"thumb_size" toml:"thumb_size"`
+	WebSize   int `yaml:"web_size" toml:"web_size"`
+	// kualitas JPEG 1-100 (gambar transparan disimpan sebagai WebP lossless)
+	Quality int `yaml:"quality" toml:"quality"`
+	// foto yang belum selesai diproses (antrean penuh, server restart)
+	// dicari ulang setiap interval ini
+	SweepInterval time.Duration `yaml:"sweep_interval" toml:"sweep_interval"`
+}
+
+// Enabled true kalau login SSO dikonfigurasi
+func (o OIDCConfig) Enabled() bool {
+	return o.Issuer != ""
+}
+
+// App menyimpan konfigurasi yang sudah di-load, dipakai oleh package lain
+var App = Default()
+
+// Default mengembalikan nilai bawaan yang sama dengan setup lokal sebelumnya
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:          "mysql",
+			User:            "root",
+			Host:            "127.0.0.1",
+			Name:            "crud_go",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		JWT: JWTConfig{
+			Algorithm:        "RS256",
+			Issuer:           "go-crud",
+			RotationInterval: 30 * 24 * time.Hour,
+			Prepublish:       time.Hour,
+			TokenTTL:         15 * time.Minute,
+			RefreshTTL:       30 * 24 * time.Hour,
+		},
+		External: ExternalConfig{
+			WilayahBaseURL: "https://www.emsifa.com/api-wilayah-indonesia/api",
+		},
+		Mail: MailConfig{
+			Driver:         "log",
+			From:           "go-crud <no-reply@localhost>",
+			SMTPPort:       "587",
+			FileDir:        "tmp/mail",
+			LinkBaseURL:    "http://localhost:3000",
+			VerifyTokenTTL: 24 * time.Hour,
+			ResetTokenTTL:  time.Hour,
+		},
+		TwoFactor: TwoFactorConfig{
+			Issuer:        "go-crud",
+			ChallengeTTL:  5 * time.Minute,
+			RequiredRoles: []string{"admin"},
+		},
+		RateLimit: RateLimitConfig{
+			Store:            "memory",
+			KeyPrefix:        "go-crud:",
+			LoginIPLimit:     20,
+			LoginIPWindow:    15 * time.Minute,
+			RegisterIPLimit:  10,
+			RegisterIPWindow: time.Hour,
+			MaxFailures:      5,
+			FailureWindow:    15 * time.Minute,
+			LockoutDuration:  15 * time.Minute,
+			BackoffBase:      time.Second,
+			BackoffMax:       time.Minute,
+		},
+		OIDC: OIDCConfig{
+			Scopes:      []string{"openid", "email", "profile"},
+			LinkByEmail: true,
+			StateTTL:    10 * time.Minute,
+		},
+		Search: SearchConfig{
+			RefreshInterval: 10 * time.Minute,
+		},
+		Storage: StorageConfig{
+			Driver:        "local",
+			LocalDir:      "uploads",
+			S3Region:      "us-east-1",
+			S3PathStyle:   true,
+			PhotoMaxSize:  5 << 20,
+			PhotoMaxCount: 8,
+		},
+		Image: ImageConfig{
+			Workers:       2,
+			QueueSize:     256,
+			ThumbSize:     320,
+			WebSize:       1280,
+			Quality:       82,
+			SweepInterval: time.Minute,
+		},
+	}
+}
+
+// TLSEnabled true kalau cert & key diisi
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != "" && s.TLSKeyFile != ""
+}
+
+// ================================
+// 🔹 Load
+// ================================
+// Urutan prioritas: default < file (-config / CONFIG_FILE) < env < flag
+func Load(name string, args []string) (*Config, error) {
+	return LoadFlags(flag.NewFlagSet(name, flag.ContinueOnError), args)
+}
+
+// LoadArgs sama seperti Load tapi juga mengembalikan argumen sisa setelah flag,
+// dipakai command yang punya subcommand (misal cmd/migrate up)
+func LoadArgs(name string, args []string) (*Config, []string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cfg, err := LoadFlags(fs, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+// LoadFlags mendaftarkan flag konfigurasi ke fs yang sudah berisi flag milik
+// command (misal cmd/seed -sellers), lalu parse semuanya sekaligus
+func LoadFlags(fs *flag.FlagSet, args []string) (*Config, error) {
+	cfg := Default()
+
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path file konfigurasi (.yaml, .yml, .toml)")
+	addr := fs.String("addr", "", "alamat listen server, contoh :8080")
+	dbDriver := fs.String("db-driver", "", "driver database (mysql, postgres, sqlite)")
+	dbHost := fs.String("db-host", "", "host database")
+	dbPort := fs.String("db-port", "", "port database")
+	dbName := fs.String("db-name", "", "nama database")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	// flag hanya menimpa kalau benar-benar diisi
+	if *addr != "" {
+		cfg.Server.Addr = *addr
+	}
+	if *dbDriver != "" {
+		cfg.Database.Driver = *dbDriver
+	}
+	if *dbHost != "" {
+		cfg.Database.Host = *dbHost
+	}
+	if *dbPort != "" {
+		cfg.Database.Port = *dbPort
+	}
+	if *dbName != "" {
+		cfg.Database.Name = *dbName
+	}
+
+	App = cfg
+	return cfg, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("gagal membaca file konfigurasi: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, cfg)
+	case ".toml":
+		err = toml.Unmarshal(raw, cfg)
+	default:
+		return fmt.Errorf("format file konfigurasi tidak didukung: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal parse file konfigurasi %s: %v", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	setString(&cfg.Server.Addr, "APP_ADDR")
+	for key, dst := range map[string]*time.Duration{
+		"APP_READ_TIMEOUT":        &cfg.Server.ReadTimeout,
+		"APP_READ_HEADER_TIMEOUT": &cfg.Server.ReadHeaderTimeout,
+		"APP_WRITE_TIMEOUT":       &cfg.Server.WriteTimeout,
+		"APP_IDLE_TIMEOUT":        &cfg.Server.IdleTimeout,
+		"APP_SHUTDOWN_TIMEOUT":    &cfg.Server.ShutdownTimeout,
+	} {
+		if err := setDuration(dst, key); err != nil {
+			return err
+		}
+	}
+	setString(&cfg.Server.TLSCertFile, "TLS_CERT_FILE")
+	setString(&cfg.Server.TLSKeyFile, "TLS_KEY_FILE")
+	if err := setBool(&cfg.Server.TrustProxy, "APP_TRUST_PROXY"); err != nil {
+		return err
+	}
+	if err := setBool(&cfg.Server.ProblemJSON, "APP_PROBLEM_JSON"); err != nil {
+		return err
+	}
+
+	setString(&cfg.Database.Driver, "DB_DRIVER")
+	setString(&cfg.Database.User, "DB_USER")
+	setString(&cfg.Database.Password, "DB_PASSWORD")
+	setString(&cfg.Database.Host, "DB_HOST")
+	setString(&cfg.Database.Port, "DB_PORT")
+	setString(&cfg.Database.Name, "DB_NAME")
+	setString(&cfg.Database.Params, "DB_PARAMS")
+	if err := setInt(&cfg.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.Database.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME"); err != nil {
+		return err
+	}
+
+	setString(&cfg.JWT.Algorithm, "JWT_ALGORITHM")
+	setString(&cfg.JWT.Issuer, "JWT_ISSUER")
+	if err := setDuration(&cfg.JWT.RotationInterval, "JWT_ROTATION_INTERVAL"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.JWT.Prepublish, "JWT_KEY_PREPUBLISH"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.JWT.TokenTTL, "JWT_TOKEN_TTL"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.JWT.RefreshTTL, "JWT_REFRESH_TTL"); err != nil {
+		return err
+	}
+
+	setString(&cfg.External.WilayahBaseURL, "WILAYAH_API_URL")
+
+	setString(&cfg.Mail.Driver, "MAIL_DRIVER")
+	setString(&cfg.Mail.From, "MAIL_FROM")
+	setString(&cfg.Mail.SMTPHost, "SMTP_HOST")
+	setString(&cfg.Mail.SMTPPort, "SMTP_PORT")
+	setString(&cfg.Mail.SMTPUser, "SMTP_USER")
+	setString(&cfg.Mail.SMTPPassword, "SMTP_PASSWORD")
+	setString(&cfg.Mail.FileDir, "MAIL_FILE_DIR")
+	setString(&cfg.Mail.LinkBaseURL, "MAIL_LINK_BASE_URL")
+	if err := setDuration(&cfg.Mail.VerifyTokenTTL, "MAIL_VERIFY_TOKEN_TTL"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.Mail.ResetTokenTTL, "MAIL_RESET_TOKEN_TTL"); err != nil {
+		return err
+	}
+
+	setString(&cfg.TwoFactor.Issuer, "TOTP_ISSUER")
+	if err := setDuration(&cfg.TwoFactor.ChallengeTTL, "TOTP_CHALLENGE_TTL"); err != nil {
+		return err
+	}
+	setList(&cfg.TwoFactor.RequiredRoles, "TOTP_REQUIRED_ROLES")
+
+	setString(&cfg.RateLimit.Store, "RATE_LIMIT_STORE")
+	setString(&cfg.RateLimit.RedisAddr, "REDIS_ADDR")
+	setString(&cfg.RateLimit.RedisPassword, "REDIS_PASSWORD")
+	setString(&cfg.RateLimit.KeyPrefix, "RATE_LIMIT_KEY_PREFIX")
+	for key, dst := range map[string]*int{
+		"REDIS_DB":           &cfg.RateLimit.RedisDB,
+		"LOGIN_IP_LIMIT":     &cfg.RateLimit.LoginIPLimit,
+		"REGISTER_IP_LIMIT":  &cfg.RateLimit.RegisterIPLimit,
+		"LOGIN_MAX_FAILURES": &cfg.RateLimit.MaxFailures,
+	} {
+		if err := setInt(dst, key); err != nil {
+			return err
+		}
+	}
+	for key, dst := range map[string]*time.Duration{
+		"LOGIN_IP_WINDOW":        &cfg.RateLimit.LoginIPWindow,
+		"REGISTER_IP_WINDOW":     &cfg.RateLimit.RegisterIPWindow,
+		"LOGIN_FAILURE_WINDOW":   &cfg.RateLimit.FailureWindow,
+		"LOGIN_LOCKOUT_DURATION": &cfg.RateLimit.LockoutDuration,
+		"LOGIN_BACKOFF_BASE":     &cfg.RateLimit.BackoffBase,
+		"LOGIN_BACKOFF_MAX":      &cfg.RateLimit.BackoffMax,
+	} {
+		if err := setDuration(dst, key); err != nil {
+			return err
+		}
+	}
+
+	setString(&cfg.OIDC.Issuer, "OIDC_ISSUER")
+	setString(&cfg.OIDC.ClientID, "OIDC_CLIENT_ID")
+	setString(&cfg.OIDC.ClientSecret, "OIDC_CLIENT_SECRET")
+	setString(&cfg.OIDC.RedirectURL, "OIDC_REDIRECT_URL")
+	setList(&cfg.OIDC.Scopes, "OIDC_SCOPES")
+	setList(&cfg.OIDC.AllowedDomains, "OIDC_ALLOWED_DOMAINS")
+	if err := setBool(&cfg.OIDC.LinkByEmail, "OIDC_LINK_BY_EMAIL"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.OIDC.StateTTL, "OIDC_STATE_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDuration(&cfg.Search.RefreshInterval, "SEARCH_REFRESH_INTERVAL"); err != nil {
+		return err
+	}
+
+	setString(&cfg.Storage.Driver, "STORAGE_DRIVER")
+	setString(&cfg.Storage.LocalDir, "STORAGE_LOCAL_DIR")
+	setString(&cfg.Storage.PublicBaseURL, "STORAGE_PUBLIC_BASE_URL")
+	setString(&cfg.Storage.S3Endpoint, "S3_ENDPOINT")
+	setString(&cfg.Storage.S3Region, "S3_REGION")
+	setString(&cfg.Storage.S3Bucket, "S3_BUCKET")
+	setString(&cfg.Storage.S3AccessKey, "S3_ACCESS_KEY")
+	setString(&cfg.Storage.S3SecretKey, "S3_SECRET_KEY")
+	if err := setBool(&cfg.Storage.S3PathStyle, "S3_PATH_STYLE"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Storage.PhotoMaxSize, "PHOTO_MAX_SIZE"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Storage.PhotoMaxCount, "PHOTO_MAX_COUNT"); err != nil {
+		return err
+	}
+
+	if err := setInt(&cfg.Image.Workers, "IMAGE_WORKERS"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Image.QueueSize, "IMAGE_QUEUE_SIZE"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Image.ThumbSize, "IMAGE_THUMB_SIZE"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Image.WebSize, "IMAGE_WEB_SIZE"); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.Image.Quality, "IMAGE_QUALITY"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.Image.SweepInterval, "IMAGE_SWEEP_INTERVAL"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func setString(dst *string, key string) {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = v
+	}
+}
+
+// setList membaca daftar dipisah koma, env kosong berarti daftar kosong
+func setList(dst *[]string, key string) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	list := []string{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func setBool(dst *bool, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%s harus berupa true/false: %v", key, err)
+	}
+	*dst = b
+	return nil
+}
+
+func setInt(dst *int, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s harus berupa angka: %v", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func setDuration(dst *time.Duration, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s harus berupa durasi (contoh 30m, 72h): %v", key, err)
+	}
+	*dst = d
+	return nil
+}
+
+// ================================
+// 🔹 Validasi
+// ================================
+
+// Validate dipanggil saat server start, menolak boot kalau konfigurasi tidak lengkap
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr (APP_ADDR) wajib diisi"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout (APP_SHUTDOWN_TIMEOUT) harus lebih dari 0"))
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, errors.New("server.tls_cert_file (TLS_CERT_FILE) dan server.tls_key_file (TLS_KEY_FILE) harus diisi berpasangan"))
+	}
+	for _, f := range []string{c.Server.TLSCertFile, c.Server.TLSKeyFile} {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); err != nil {
+			errs = append(errs, fmt.Errorf("file TLS tidak bisa dibaca: %v", err))
+		}
+	}
+	if err := c.Database.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.JWT.Algorithm != "RS256" && c.JWT.Algorithm != "EdDSA" {
+		errs = append(errs, fmt.Errorf("jwt.algorithm (JWT_ALGORITHM) tidak dikenal: %q (pilihan: RS256, EdDSA)", c.JWT.Algorithm))
+	}
+	if c.JWT.Issuer == "" {
+		errs = append(errs, errors.New("jwt.issuer (JWT_ISSUER) wajib diisi"))
+	}
+	if c.JWT.Prepublish <= 0 || c.JWT.RotationInterval <= c.JWT.Prepublish {
+		errs = append(errs, errors.New("jwt.rotation_interval harus lebih lama dari jwt.prepublish (dan keduanya lebih dari 0)"))
+	}
+	if c.JWT.TokenTTL <= 0 {
+		errs = append(errs, errors.New("jwt.token_ttl (JWT_TOKEN_TTL) harus lebih dari 0"))
+	}
+	if c.JWT.RefreshTTL <= c.JWT.TokenTTL {
+		errs = append(errs, errors.New("jwt.refresh_ttl (JWT_REFRESH_TTL) harus lebih lama dari jwt.token_ttl"))
+	}
+	if c.External.WilayahBaseURL == "" {
+		errs = append(errs, errors.New("external.wilayah_base_url (WILAYAH_API_URL) wajib diisi"))
+	}
+	if err := c.Mail.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.TwoFactor.Issuer == "" {
+		errs = append(errs, errors.New("two_factor.issuer (TOTP_ISSUER) wajib diisi"))
+	}
+	if c.TwoFactor.ChallengeTTL <= 0 {
+		errs = append(errs, errors.New("two_factor.challenge_ttl (TOTP_CHALLENGE_TTL) harus lebih dari 0"))
+	}
+	if err := c.RateLimit.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.OIDC.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Search.RefreshInterval < 0 {
+		errs = append(errs, errors.New("search.refresh_interval (SEARCH_REFRESH_INTERVAL) tidak boleh negatif"))
+	}
+	if err := c.Storage.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Image.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m MailConfig) Validate() error {
+	var errs []error
+
+	switch m.Driver {
+	case "smtp":
+		if m.SMTPHost == "" {
+			errs = append(errs, errors.New("mail.smtp_host (SMTP_HOST) wajib diisi untuk driver smtp"))
+		}
+	case "file":
+		if m.FileDir == "" {
+			errs = append(errs, errors.New("mail.file_dir (MAIL_FILE_DIR) wajib diisi untuk driver file"))
+		}
+	case "log":
+	default:
+		errs = append(errs, fmt.Errorf("mail.driver (MAIL_DRIVER) tidak dikenal: %q (pilihan: smtp, file, log)", m.Driver))
+	}
+	if m.From == "" {
+		errs = append(errs, errors.New("mail.from (MAIL_FROM) wajib diisi"))
+	}
+	if m.VerifyTokenTTL <= 0 || m.ResetTokenTTL <= 0 {
+		errs = append(errs, errors.New("masa berlaku token email harus lebih dari 0"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r RateLimitConfig) Validate() error {
+	var errs []error
+
+	switch r.Store {
+	case "memory":
+	case "redis":
+		if r.RedisAddr == "" {
+			errs = append(errs, errors.New("rate_limit.redis_addr (REDIS_ADDR) wajib diisi untuk store redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("rate_limit.store (RATE_LIMIT_STORE) tidak dikenal: %q (pilihan: memory, redis)", r.Store))
+	}
+	if r.LoginIPLimit <= 0 || r.RegisterIPLimit <= 0 || r.MaxFailures <= 0 {
+		errs = append(errs, errors.New("batas rate limit dan rate_limit.max_failures harus lebih dari 0"))
+	}
+	for _, d := range []time.Duration{r.LoginIPWindow, r.RegisterIPWindow, r.FailureWindow, r.LockoutDuration, r.BackoffBase, r.BackoffMax} {
+		if d <= 0 {
+			errs = append(errs, errors.New("semua durasi rate_limit harus lebih dari 0"))
+			break
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (o OIDCConfig) Validate() error {
+	if !o.Enabled() {
+		return nil
+	}
+	var errs []error
+
+	if u, err := url.Parse(o.Issuer); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("oidc.issuer (OIDC_ISSUER) harus berupa URL lengkap: %q", o.Issuer))
+	}
+	if o.ClientID == "" {
+		errs = append(errs, errors.New("oidc.client_id (OIDC_CLIENT_ID) wajib diisi"))
+	}
+	if o.RedirectURL == "" {
+		errs = append(errs, errors.New("oidc.redirect_url (OIDC_REDIRECT_URL) wajib diisi"))
+	}
+	hasOpenID := false
+	for _, s := range o.Scopes {
+		if s == "openid" {
+			hasOpenID = true
+		}
+	}
+	if !hasOpenID {
+		errs = append(errs, errors.New("oidc.scopes (OIDC_SCOPES) harus memuat openid"))
+	}
+	if o.StateTTL <= 0 {
+		errs = append(errs, errors.New("oidc.state_ttl (OIDC_STATE_TTL) harus lebih dari 0"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s StorageConfig) Validate() error {
+	var errs []error
+
+	switch s.Driver {
+	case "local":
+		if s.LocalDir == "" {
+			errs = append(errs, errors.New("storage.local_dir (STORAGE_LOCAL_DIR) wajib diisi untuk driver local"))
+		}
+	case "s3":
+		if s.S3Endpoint == "" || s.S3Bucket == "" || s.S3Region == "" {
+			errs = append(errs, errors.New("storage.s3_endpoint, s3_bucket dan s3_region wajib diisi untuk driver s3"))
+		}
+		if s.S3AccessKey == "" || s.S3SecretKey == "" {
+			errs = append(errs, errors.New("storage.s3_access_key (S3_ACCESS_KEY) dan s3_secret_key (S3_SECRET_KEY) wajib diisi untuk driver s3"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.driver (STORAGE_DRIVER) tidak dikenal: %q (pilihan: local, s3)", s.Driver))
+	}
+	if s.PhotoMaxSize <= 0 || s.PhotoMaxCount <= 0 {
+		errs = append(errs, errors.New("storage.photo_max_size dan storage.photo_max_count harus lebih dari 0"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (i ImageConfig) Validate() error {
+	var errs []error
+
+	if i.Workers <= 0 || i.QueueSize <= 0 {
+		errs = append(errs, errors.New("image.workers (IMAGE_WORKERS) dan image.queue_size (IMAGE_QUEUE_SIZE) harus lebih dari 0"))
+	}
+	if i.ThumbSize <= 0 || i.WebSize < i.ThumbSize {
+		errs = append(errs, errors.New("image.thumb_size harus lebih dari 0 dan image.web_size tidak boleh lebih kecil dari thumb_size"))
+	}
+	if i.WebSize > 16384 {
+		errs = append(errs, errors.New("image.web_size (IMAGE_WEB_SIZE) maksimal 16384"))
+	}
+	if i.Quality < 1 || i.Quality > 100 {
+		errs = append(errs, errors.New("image.quality (IMAGE_QUALITY) harus 1-100"))
+	}
+	if i.SweepInterval <= 0 {
+		errs = append(errs, errors.New("image.sweep_interval (IMAGE_SWEEP_INTERVAL) harus lebih dari 0"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate cukup untuk command yang hanya butuh database (misal cmd/migrate)
+func (d DatabaseConfig) Validate() error {
+	var errs []error
+
+	driver, err := GetDriver(d.Driver)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	// sqlite cukup nama file, driver lain butuh host & user
+	if driver != nil && driver.Name() != "sqlite" {
+		if d.Host == "" {
+			errs = append(errs, errors.New("database.host (DB_HOST) wajib diisi"))
+		}
+		if d.User == "" {
+			errs = append(errs, errors.New("database.user (DB_USER) wajib diisi"))
+		}
+	}
+	if d.Name == "" {
+		errs = append(errs, errors.New("database.name (DB_NAME) wajib diisi"))
+	}
+	if d.MaxOpenConns < 0 || d.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("ukuran pool database tidak boleh negatif"))
+	}
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns tidak boleh lebih besar dari max_open_conns"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DSN dibentuk dari bagian-bagian konfigurasi database sesuai driver-nya
+func (d DatabaseConfig) DSN() (string, error) {
+	driver, err := GetDriver(d.Driver)
+	if err != nil {
+		return "", err
+	}
+	if d.Port == "" {
+		d.Port = driver.DefaultPort()
+	}
+	return driver.DSN(d), nil
+}
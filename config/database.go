@@ -3,26 +3,54 @@ package config
 import (
 	"fmt"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
-func ConnectDatabase() {
-	dbUser := "root"
-	dbPass := ""
-	dbHost := "127.0.0.1"
-	dbPort := "3306"
-	dbName := "crud_go"
-
-	// Format DSN (Data Source Name)
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", dbUser, dbPass, dbHost, dbPort, dbName)
+func ConnectDatabase(cfg DatabaseConfig) {
+	driver, err := GetDriver(cfg.Driver)
+	if err != nil {
+		panic(err.Error())
+	}
+	dsn, err := cfg.DSN()
+	if err != nil {
+		panic(err.Error())
+	}
 
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(driver.Dialector(dsn), &gorm.Config{})
 	if err != nil {
 		panic(fmt.Sprintf("Gagal koneksi ke database: %v", err))
 	}
 
-	fmt.Println("Berhasil konek ke database")
-}
\ No newline at end of file
+	// Atur ukuran pool koneksi
+	sqlDB, err := DB.DB()
+	if err != nil {
+		panic(fmt.Sprintf("Gagal mengambil pool database: %v", err))
+	}
+	if driver.Name() == "sqlite" && sqliteInMemory(cfg.Name) {
+		// database ":memory:" milik satu koneksi saja; koneksi lain di pool
+		// akan membuka database kosong sendiri. Pakai satu koneksi yang
+		// tidak pernah ditutup supaya isinya tidak hilang.
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	} else {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+
+	fmt.Printf("Berhasil konek ke database (%s)\n", driver.Name())
+}
+
+// CloseDatabase menutup pool koneksi, dipanggil saat server shutdown
+func CloseDatabase() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
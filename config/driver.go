@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// ================================
+// 🔌 Driver Database
+// ================================
+
+// Driver membungkus dialector GORM beserta default koneksinya
+type Driver interface {
+	Name() string
+	DefaultPort() string
+	DSN(cfg DatabaseConfig) string
+	Dialector(dsn string) gorm.Dialector
+}
+
+var drivers = map[string]Driver{}
+
+// RegisterDriver mendaftarkan driver baru, dipanggil dari init()
+func RegisterDriver(d Driver) {
+	drivers[d.Name()] = d
+}
+
+// GetDriver mencari driver berdasarkan nama di konfigurasi (database.driver)
+func GetDriver(name string) (Driver, error) {
+	d, ok := drivers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("driver database %q tidak dikenal (pilihan: %s)", name, strings.Join(DriverNames(), ", "))
+	}
+	return d, nil
+}
+
+func DriverNames() []string {
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterDriver(mysqlDriver{})
+	RegisterDriver(postgresDriver{})
+	RegisterDriver(sqliteDriver{})
+}
+
+// 🔹 MySQL
+type mysqlDriver struct{}
+
+func (mysqlDriver) Name() string        { return "mysql" }
+func (mysqlDriver) DefaultPort() string { return "3306" }
+
+func (mysqlDriver) DSN(cfg DatabaseConfig) string {
+	params := cfg.Params
+	if params == "" {
+		params = "charset=utf8mb4&parseTime=True&loc=Local"
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name, params)
+}
+
+func (mysqlDriver) Dialector(dsn string) gorm.Dialector { return mysql.Open(dsn) }
+
+// 🔹 PostgreSQL
+type postgresDriver struct{}
+
+func (postgresDriver) Name() string        { return "postgres" }
+func (postgresDriver) DefaultPort() string { return "5432" }
+
+func (postgresDriver) DSN(cfg DatabaseConfig) string {
+	params := cfg.Params
+	if params == "" {
+		params = "sslmode=disable TimeZone=Asia/Jakarta"
+	}
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s %s", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, params)
+}
+
+func (postgresDriver) Dialector(dsn string) gorm.Dialector { return postgres.Open(dsn) }
+
+// 🔹 SQLite (pure Go, tanpa cgo) — database.name berisi path file atau ":memory:"
+type sqliteDriver struct{}
+
+func (sqliteDriver) Name() string        { return "sqlite" }
+func (sqliteDriver) DefaultPort() string { return "" }
+
+func (sqliteDriver) DSN(cfg DatabaseConfig) string {
+	params := cfg.Params
+	if params == "" {
+		params = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	}
+	return cfg.Name + "?" + params
+}
+
+func (sqliteDriver) Dialector(dsn string) gorm.Dialector { return sqlite.Open(dsn) }
+
+// sqliteInMemory true untuk ":memory:" dan URI "file::memory:" / mode=memory
+func sqliteInMemory(name string) bool {
+	return strings.Contains(name, ":memory:") || strings.Contains(name, "mode=memory")
+}
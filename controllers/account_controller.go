@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/config"
+	"go-crud/i18n"
+	"go-crud/lifecycle"
+	"go-crud/mailer"
+	"go-crud/models"
+	"go-crud/repositories"
+	"go-crud/utils"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// newVerificationEmail membuat token verifikasi di dalam tx. Email-nya baru
+// dikirim (mailer.SendAsync) setelah transaksi commit.
+func newVerificationEmail(tx *gorm.DB, user models.User) (mailer.Message, error) {
+	raw, err := auth.CreateOneTimeToken(tx, user.ID, models.TokenPurposeVerifyEmail, config.App.Mail.VerifyTokenTTL)
+	if err != nil {
+		return mailer.Message{}, err
+	}
+	return mailer.VerificationEmail(user.Email, user.Nama, raw), nil
+}
+
+// AccountHooks menjalankan efek samping perubahan akun dari UserService:
+// email baru dikirimi verifikasi, ganti kata sandi mencabut semua sesi
+type AccountHooks struct{}
+
+func (AccountHooks) EmailChanged(ctx context.Context, user models.User) error {
+	msg, err := newVerificationEmail(repositories.Conn(ctx, config.DB), user)
+	if err != nil {
+		return err
+	}
+	repositories.AfterCommit(ctx, func() { mailer.SendAsync(msg) })
+	return nil
+}
+
+func (AccountHooks) PasswordChanged(ctx context.Context, user models.User) error {
+	return auth.RevokeAll(repositories.Conn(ctx, config.DB), user.ID)
+}
+
+// ===================================================
+// 📧 KIRIM ULANG VERIFIKASI EMAIL (POST /api/email/verification)
+// ===================================================
+func ResendVerification(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	if authUser.EmailVerifiedAt != nil {
+		return apperror.BadRequest("account.already_verified").WithDetails([]string{"email_already_verified"})
+	}
+
+	var msg mailer.Message
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		msg, err = newVerificationEmail(tx, *authUser)
+		return err
+	})
+	if err != nil {
+		return apperror.Internal("account.verify_token_failed", err)
+	}
+	mailer.SendAsync(msg)
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "account.verify_sent"), nil))
+}
+
+// ===================================================
+// ✅ VERIFIKASI EMAIL (POST /email/verify)
+// ===================================================
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func VerifyEmail(c echo.Context) error {
+	var req VerifyEmailRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		token, err := auth.ConsumeOneTimeToken(tx, req.Token, models.TokenPurposeVerifyEmail)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", token.IDUser).Update("email_verified_at", time.Now()).Error
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidOneTimeToken) {
+			return apperror.BadRequest(errKey(err)).WithDetails([]string{"invalid_token"})
+		}
+		return apperror.Internal("account.verify_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "account.verify_success"), nil))
+}
+
+// ===================================================
+// 🔑 LUPA KATA SANDI (POST /password/forgot)
+// ===================================================
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email,max=100"`
+}
+
+func ForgotPassword(c echo.Context) error {
+	var req ForgotPasswordRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+
+	// Cari user, buat token dan kirim email di background. Respons dikirim
+	// sebelum ada query apa pun, jadi isi dan waktunya sama untuk email
+	// terdaftar maupun tidak.
+	email := req.Email
+	lifecycle.Go("forgot-password", func(_ context.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := sendResetPasswordEmail(ctx, email); err != nil {
+			log.Printf("gagal memproses lupa kata sandi: %v", err)
+		}
+	})
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "account.reset_sent"), nil))
+}
+
+// sendResetPasswordEmail membuat token reset lalu mengirim emailnya. Email
+// yang tidak terdaftar diabaikan tanpa error.
+func sendResetPasswordEmail(ctx context.Context, email string) error {
+	db := config.DB.WithContext(ctx)
+	var user models.User
+	err := db.Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var raw string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		raw, err = auth.CreateOneTimeToken(tx, user.ID, models.TokenPurposeResetPassword, config.App.Mail.ResetTokenTTL)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return mailer.Default.Send(ctx, mailer.ResetPasswordEmail(user.Email, user.Nama, raw))
+}
+
+// ===================================================
+// 🔁 RESET KATA SANDI (POST /password/reset)
+// ===================================================
+type ResetPasswordRequest struct {
+	Token     string `json:"token" validate:"required"`
+	KataSandi string `json:"kata_sandi" validate:"required,min=8,max=72"`
+}
+
+func ResetPassword(c echo.Context) error {
+	var req ResetPasswordRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.KataSandi), bcrypt.DefaultCost)
+	if err != nil {
+		return apperror.Internal("auth.hash_failed", err)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		token, err := auth.ConsumeOneTimeToken(tx, req.Token, models.TokenPurposeResetPassword)
+		if err != nil {
+			return err
+		}
+		// link reset di email membuktikan kepemilikan email, jadi sekalian terverifikasi
+		now := time.Now()
+		err = tx.Model(&models.User{}).Where("id = ?", token.IDUser).Updates(map[string]interface{}{
+			"kata_sandi":        string(hashed),
+			"email_verified_at": gorm.Expr("COALESCE(email_verified_at, ?)", now),
+		}).Error
+		if err != nil {
+			return err
+		}
+		// keluarkan semua sesi lama
+		return auth.RevokeAll(tx, token.IDUser)
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidOneTimeToken) {
+			return apperror.BadRequest(errKey(err)).WithDetails([]string{"invalid_token"})
+		}
+		return apperror.Internal("account.reset_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "account.reset_success"), nil))
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"context"
+	"go-crud/auth"
+	"go-crud/mailer"
+	"go-crud/models"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// useMemoryMailer mengganti mailer.Default selama test
+func useMemoryMailer(t *testing.T) *mailer.MemoryMailer {
+	t.Helper()
+	m := &mailer.MemoryMailer{}
+	old := mailer.Default
+	mailer.Default = m
+	t.Cleanup(func() { mailer.Default = old })
+	return m
+}
+
+// waitForMail menunggu email ke-n (dikirim di background) sampai 5 detik
+func waitForMail(t *testing.T, m *mailer.MemoryMailer, n int) []mailer.Message {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(m.Sent()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d email terkirim, seharusnya %d", len(m.Sent()), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return m.Sent()
+}
+
+var tokenLink = regexp.MustCompile(`(/[a-z-]+)\?token=(\S+)`)
+
+// linkToken path dan token dari link di body email
+func linkToken(t *testing.T, msg mailer.Message) (string, string) {
+	t.Helper()
+	match := tokenLink.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("email %q tidak berisi link token:\n%s", msg.Subject, msg.Body)
+	}
+	token, err := url.QueryUnescape(match[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return match[1], token
+}
+
+func createUser(t *testing.T, db *gorm.DB, email string) models.User {
+	t.Helper()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("rahasia-lama"), bcrypt.MinCost)
+	user := models.User{Nama: "Budi", Email: email, KataSandi: string(hash)}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("membuat user: %v", err)
+	}
+	return user
+}
+
+// Email verifikasi dikirim setelah commit dan token di dalamnya memverifikasi
+// email tepat sekali
+func TestVerificationEmail(t *testing.T) {
+	db := openTestDB(t)
+	sent := useMemoryMailer(t)
+	user := createUser(t, db, "budi@example.com")
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		msg, err := newVerificationEmail(tx, user)
+		if err == nil {
+			mailer.SendAsync(msg)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := waitForMail(t, sent, 1)[0]
+	if msg.To != user.Email || !strings.Contains(msg.Body, "Budi") {
+		t.Errorf("email ke %s:\n%s", msg.To, msg.Body)
+	}
+	path, token := linkToken(t, msg)
+	if path != "/verify-email" {
+		t.Errorf("link %s, seharusnya /verify-email", path)
+	}
+
+	body := `{"token":"` + token + `"}`
+	if rec := postJSON(VerifyEmail, body); rec.Code != http.StatusOK {
+		t.Fatalf("verifikasi: %d %s", rec.Code, rec.Body)
+	}
+	db.First(&user, user.ID)
+	if user.EmailVerifiedAt == nil {
+		t.Error("email_verified_at belum terisi")
+	}
+	if rec := postJSON(VerifyEmail, body); rec.Code != http.StatusBadRequest {
+		t.Errorf("token dipakai ulang: %d, seharusnya 400", rec.Code)
+	}
+}
+
+// Respons lupa kata sandi sama untuk email terdaftar maupun tidak; hanya
+// email terdaftar yang dikirimi link reset
+func TestForgotPassword(t *testing.T) {
+	db := openTestDB(t)
+	sent := useMemoryMailer(t)
+	user := createUser(t, db, "budi@example.com")
+
+	registered := postJSON(ForgotPassword, `{"email":"budi@example.com"}`)
+	unknown := postJSON(ForgotPassword, `{"email":"siapa@example.com"}`)
+	if registered.Code != http.StatusOK || registered.Code != unknown.Code || registered.Body.String() != unknown.Body.String() {
+		t.Errorf("respons berbeda:\n%d %s\n%d %s", registered.Code, registered.Body, unknown.Code, unknown.Body)
+	}
+
+	msg := waitForMail(t, sent, 1)[0]
+	if msg.To != user.Email {
+		t.Errorf("email reset dikirim ke %s", msg.To)
+	}
+	path, token := linkToken(t, msg)
+	if path != "/reset-password" {
+		t.Errorf("link %s, seharusnya /reset-password", path)
+	}
+
+	// email tidak terdaftar tidak membuat token maupun email
+	if err := sendResetPasswordEmail(context.Background(), "siapa@example.com"); err != nil {
+		t.Errorf("email tidak terdaftar: %v", err)
+	}
+	var tokens int64
+	db.Model(&models.UserToken{}).Where("purpose = ?", models.TokenPurposeResetPassword).Count(&tokens)
+	if tokens != 1 {
+		t.Errorf("%d token reset, seharusnya 1", tokens)
+	}
+
+	rec := postJSON(ResetPassword, `{"token":"`+token+`","kata_sandi":"rahasia-baru"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reset: %d %s", rec.Code, rec.Body)
+	}
+	db.First(&user, user.ID)
+	if !auth.CheckPassword(user.KataSandi, "rahasia-baru") {
+		t.Error("kata sandi belum berganti")
+	}
+	if len(sent.Sent()) != 1 {
+		t.Errorf("%d email terkirim, seharusnya 1", len(sent.Sent()))
+	}
+}
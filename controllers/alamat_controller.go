@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"go-crud/apperror"
 	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
 	"go-crud/models"
 	"go-crud/utils"
 	"net/http"
@@ -16,36 +19,16 @@ import (
 func GetMyAlamat(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return err
 	}
 
 	// ambil semua kolom dulu
 	var alamat []models.Alamat
 	if err := config.DB.Where("id_user = ?", authUser.ID).Find(&alamat).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET data", []string{err.Error()}))
+		return apperror.Internal("common.get_failed", err)
 	}
 
-	// mapping ke struct untuk response clean
-	type AlamatResponse struct {
-		ID            uint64   `json:"id"`
-		JudulAlamat   string `json:"judul_alamat"`
-		NamaPenerima  string `json:"nama_penerima"`
-		NoTelp        string `json:"no_telp"`
-		DetailAlamat  string `json:"detail_alamat"`
-	}
-
-	var result []AlamatResponse
-	for _, a := range alamat {
-		result = append(result, AlamatResponse{
-			ID:           a.ID,
-			JudulAlamat:  a.JudulAlamat,
-			NamaPenerima: a.NamaPenerima,
-			NoTelp:       a.NoTelp,
-			DetailAlamat: a.DetailAlamat,
-		})
-	}
-
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", result))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewAlamatList(alamat)))
 }
 
 
@@ -56,107 +39,113 @@ func GetMyAlamat(c echo.Context) error {
 func GetAlamatByID(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return err
 	}
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to GET data", []string{"ID tidak valid"}))
+		return apperror.BadRequest("common.get_failed").WithDetails([]string{"common.invalid_id"})
 	}
 
 	var alamat models.Alamat
 	if err := config.DB.First(&alamat, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to GET data", []string{"Alamat tidak ditemukan"}))
+		return apperror.NotFound("common.get_failed").WithDetails([]string{"alamat.not_found"})
 	}
 
 	// Pastikan user hanya bisa lihat alamat miliknya
 	if alamat.IDUser != authUser.ID {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Failed to GET data", []string{"Tidak dapat melihat alamat orang lain"}))
+		return apperror.Forbidden("common.get_failed").WithDetails([]string{"alamat.view_forbidden"})
 	}
 
-	// Ambil data provinsi & kota dari API EMSIFA
-	var provinsi, kota interface{}
-	if alamat.User.IDProvinsi != nil && *alamat.User.IDProvinsi != "" {
-		provinsi, _ = utils.GetProvinceByID(*alamat.User.IDProvinsi)
+	// Ambil data provinsi & kota pemilik dari API EMSIFA. Pemiliknya pasti
+	// user login (dicek di atas); relasi alamat.User tidak di-Preload.
+	data := dto.AlamatDetail{Alamat: dto.NewAlamat(alamat)}
+	if authUser.IDProvinsi != nil && *authUser.IDProvinsi != "" {
+		data.Provinsi, _ = utils.GetProvinceByID(*authUser.IDProvinsi)
 	}
-	if alamat.User.IDKota != nil && *alamat.User.IDKota != "" {
-		kota, _ = utils.GetCityByID(*alamat.User.IDKota)
+	if authUser.IDKota != nil && *authUser.IDKota != "" {
+		data.Kota, _ = utils.GetCityByID(*authUser.IDKota)
 	}
 
-	data := map[string]interface{}{
-		"alamat":   alamat,
-		"provinsi": provinsi,
-		"kota":     kota,
-	}
-
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", data))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), data))
 }
 
 // ===================================================
 // POST /api/alamat
 // ===================================================
+type CreateAlamatRequest struct {
+	JudulAlamat  string `json:"judul_alamat" validate:"required,max=100"`
+	NamaPenerima string `json:"nama_penerima" validate:"required,max=100"`
+	NoTelp       string `json:"no_telp" validate:"required,phone_id"`
+	DetailAlamat string `json:"detail_alamat" validate:"required,max=1000"`
+}
+
 func CreateAlamat(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return err
 	}
 
-	var input models.Alamat
-	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to CREATE data", []string{"Input tidak valid"}))
+	var req CreateAlamatRequest
+	if err := bindAndValidate(c, "common.create_failed", &req); err != nil {
+		return err
 	}
 
-	input.IDUser = authUser.ID
+	input := models.Alamat{
+		IDUser:       authUser.ID,
+		JudulAlamat:  req.JudulAlamat,
+		NamaPenerima: req.NamaPenerima,
+		NoTelp:       req.NoTelp,
+		DetailAlamat: req.DetailAlamat,
+	}
 
 	if err := config.DB.Create(&input).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to CREATE data", []string{err.Error()}))
+		return apperror.Internal("common.create_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to CREATE data", input))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.create_success"), dto.NewAlamat(input)))
 }
 
 // ===================================================
 // PUT /api/alamat/:id
 // ===================================================
+
+// UpdateAlamatRequest field kosong berarti tidak diubah
+type UpdateAlamatRequest struct {
+	JudulAlamat  string `json:"judul_alamat" validate:"omitempty,max=100"`
+	NamaPenerima string `json:"nama_penerima" validate:"omitempty,max=100"`
+	NoTelp       string `json:"no_telp" validate:"omitempty,phone_id"`
+	DetailAlamat string `json:"detail_alamat" validate:"omitempty,max=1000"`
+}
+
 func UpdateAlamat(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return err
 	}
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to UPDATE data", []string{"ID tidak valid"}))
+		return apperror.BadRequest("common.update_failed").WithDetails([]string{"common.invalid_id"})
 	}
 
 	var alamat models.Alamat
 	if err := config.DB.First(&alamat, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to UPDATE data", []string{"Alamat tidak ditemukan"}))
+		return apperror.NotFound("common.update_failed").WithDetails([]string{"alamat.not_found"})
 	}
 
 	if alamat.IDUser != authUser.ID {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Failed to UPDATE data", []string{"Tidak dapat mengubah alamat orang lain"}))
+		return apperror.Forbidden("common.update_failed").WithDetails([]string{"alamat.edit_forbidden"})
 	}
 
-	var input models.Alamat
-	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to UPDATE data", []string{"Input tidak valid"}))
+	// field kosong berarti tidak diubah
+	var input UpdateAlamatRequest
+	if err := bindAndValidate(c, "common.update_failed", &input); err != nil {
+		return err
 	}
 
 	// 🔒 Siapkan field yang akan diupdate
 	updates := map[string]interface{}{}
-
-	// Cek apakah field User tidak nil sebelum akses
-	if input.User != nil {
-		if input.User.IDProvinsi != nil && *input.User.IDProvinsi != "" {
-			updates["id_provinsi"] = *input.User.IDProvinsi
-		}
-		if input.User.IDKota != nil && *input.User.IDKota != "" {
-			updates["id_kota"] = *input.User.IDKota
-		}
-	}
-
-	// Field lain
 	if input.DetailAlamat != "" {
 		updates["detail_alamat"] = input.DetailAlamat
 	}
@@ -171,14 +160,14 @@ func UpdateAlamat(c echo.Context) error {
 	}
 
 	if len(updates) == 0 {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to UPDATE data", []string{"Tidak ada data yang diubah"}))
+		return apperror.BadRequest("common.update_failed").WithDetails([]string{"common.nothing_changed"})
 	}
 
 	if err := config.DB.Model(&alamat).Updates(updates).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to UPDATE data", []string{err.Error()}))
+		return apperror.Internal("common.update_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to UPDATE data", ""))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.update_success"), ""))
 }
 
 // ===================================================
@@ -187,26 +176,26 @@ func UpdateAlamat(c echo.Context) error {
 func DeleteAlamat(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return err
 	}
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to DELETE data", []string{"ID tidak valid"}))
+		return apperror.BadRequest("common.delete_failed").WithDetails([]string{"common.invalid_id"})
 	}
 
 	var alamat models.Alamat
 	if err := config.DB.First(&alamat, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to DELETE data", []string{"Alamat tidak ditemukan"}))
+		return apperror.NotFound("common.delete_failed").WithDetails([]string{"alamat.not_found"})
 	}
 
 	if alamat.IDUser != authUser.ID {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Failed to DELETE data", []string{"Tidak dapat menghapus alamat orang lain"}))
+		return apperror.Forbidden("common.delete_failed").WithDetails([]string{"alamat.delete_forbidden"})
 	}
 
 	if err := config.DB.Delete(&alamat).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to DELETE data", []string{err.Error()}))
+		return apperror.Internal("common.delete_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to DELETE data", "Alamat berhasil dihapus"))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.delete_success"), i18n.T(c, "alamat.deleted")))
 }
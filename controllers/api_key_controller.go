@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/models"
+	"go-crud/rbac"
+	"go-crud/utils"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ===================================================
+// 🔑 GET /api/api-keys - daftar API key milik user login
+// ===================================================
+func GetMyAPIKeys(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var keys []models.APIKey
+	if err := config.DB.Where("id_user = ?", authUser.ID).Order("id desc").Find(&keys).Error; err != nil {
+		return apperror.Internal("api_key.list_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewAPIKeys(keys)))
+}
+
+// ===================================================
+// 🔑 POST /api/api-keys - buat API key baru
+// ===================================================
+// Body: {"name": "ERP", "permissions": ["product:write"], "id_toko": 1, "expires_in_days": 90}
+// Key asli hanya dikembalikan sekali di respons ini.
+type CreateAPIKeyRequest struct {
+	Name        string   `json:"name" validate:"required,notblank,max=100"`
+	Permissions []string `json:"permissions"`
+	// diisi untuk key khusus satu toko milik user
+	IDToko *uint64 `json:"id_toko"`
+	// 0 = tidak kedaluwarsa
+	ExpiresInDays int `json:"expires_in_days" validate:"min=0"`
+}
+
+func CreateAPIKey(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req CreateAPIKeyRequest
+	if err := bindAndValidate(c, "common.create_failed", &req); err != nil {
+		return err
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	if req.IDToko != nil {
+		var toko models.Toko
+		if err := config.DB.First(&toko, *req.IDToko).Error; err != nil {
+			return apperror.NotFound("common.create_failed").WithDetails([]string{"toko.not_found"})
+		}
+		if toko.IDUser != authUser.ID {
+			return apperror.Forbidden("common.create_failed").WithDetails([]string{"api_key.toko_owner_only"})
+		}
+	}
+
+	// permission key tidak boleh melebihi permission pemiliknya
+	seen := map[string]bool{}
+	var perms []string
+	for _, p := range req.Permissions {
+		if !rbac.IsValidPermission(p) {
+			return apperror.BadRequest("common.create_failed").WithDetails([]i18n.Message{i18n.M("rbac.unknown_permission", p)})
+		}
+		if !rbac.Can(*authUser, p) {
+			return apperror.Forbidden("common.create_failed").WithDetails([]i18n.Message{i18n.M("rbac.permission_not_owned", p)})
+		}
+		if req.IDToko != nil && !slices.Contains(rbac.TokoKeyPermissions, p) {
+			return apperror.BadRequest("common.create_failed").WithDetails([]i18n.Message{i18n.M("api_key.permission_not_for_toko", p)})
+		}
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+
+	raw, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return apperror.Internal("api_key.create_failed", err)
+	}
+
+	key := models.APIKey{
+		IDUser:      authUser.ID,
+		IDToko:      req.IDToko,
+		Name:        req.Name,
+		Prefix:      prefix,
+		KeyHash:     hash,
+		Permissions: strings.Join(perms, ","),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := config.DB.Create(&key).Error; err != nil {
+		return apperror.Internal("api_key.save_failed", err)
+	}
+
+	data := dto.CreatedAPIKey{APIKey: dto.NewAPIKey(key), Key: raw}
+	return c.JSON(http.StatusCreated, utils.SuccessResponse(i18n.T(c, "api_key.created"), data))
+}
+
+// ===================================================
+// 🔑 DELETE /api/api-keys/:id - cabut API key
+// ===================================================
+func RevokeAPIKey(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var key models.APIKey
+	if err := config.DB.First(&key, c.Param("id")).Error; err != nil {
+		return apperror.NotFound("common.delete_failed").WithDetails([]string{"api_key.not_found"})
+	}
+	// admin (user:write) boleh mencabut key milik siapa pun, misal saat key bocor
+	if key.IDUser != authUser.ID && !rbac.Can(*authUser, rbac.PermUserWrite) {
+		return apperror.NotFound("common.delete_failed").WithDetails([]string{"api_key.not_found"})
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now()
+		if err := config.DB.Model(&key).Update("revoked_at", now).Error; err != nil {
+			return apperror.Internal("api_key.revoke_failed", err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "api_key.revoked"), dto.NewAPIKey(key)))
+}
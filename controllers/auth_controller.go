@@ -1,141 +1,340 @@
 package controllers
 
 import (
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
 	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/mailer"
 	"go-crud/models"
+	"go-crud/ratelimit"
+	"go-crud/rbac"
 	"go-crud/utils"
 	"net/http"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
-
 // ===================================================
 // 🧾 REGISTER (POST)
 // ===================================================
+type RegisterRequest struct {
+	Nama      string  `json:"nama" validate:"required,max=100"`
+	Email     string  `json:"email" validate:"required,email,max=100"`
+	NoTelp    *string `json:"no_telp" validate:"omitnil,phone_id"`
+	KataSandi string  `json:"kata_sandi" validate:"required,min=8,max=72"`
+}
+
 func Register(c echo.Context) error {
-	type RegisterRequest struct {
-		Nama      string  `json:"nama"`
-		Email     string  `json:"email"`
-		NoTelp    *string `json:"no_telp"`
-		KataSandi string  `json:"kata_sandi"`
-		IsAdmin   bool    `json:"is_admin"`
+	var req RegisterRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
 	}
 
-	var req RegisterRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request body", []string{err.Error()}))
+	// Hash password (sebelum cek email supaya waktu responsnya sama)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.KataSandi), bcrypt.DefaultCost)
+	if err != nil {
+		return apperror.Internal("auth.hash_failed", err)
 	}
 
+	// respons selalu sama supaya endpoint ini tidak bisa dipakai mengecek
+	// email terdaftar; pemilik email diberi tahu lewat email
+	resp := utils.SuccessResponse(i18n.T(c, "auth.register_success"), dto.Register{Email: req.Email})
+
 	// Cek apakah email sudah digunakan
 	var existingUser models.User
 	if err := config.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Email sudah terdaftar", nil))
-	}
-
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.KataSandi), bcrypt.DefaultCost)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal hash password", []string{err.Error()}))
+		mailer.SendAsync(mailer.AccountExistsEmail(existingUser.Email, existingUser.Nama))
+		return c.JSON(http.StatusOK, resp)
 	}
 
-	// Simpan user baru
 	user := models.User{
 		Nama:      req.Nama,
 		Email:     req.Email,
 		NoTelp:    req.NoTelp,
 		KataSandi: string(hashedPassword),
-		IsAdmin:   req.IsAdmin,
 	}
 
-	if err := config.DB.Create(&user).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal menyimpan data pengguna", []string{err.Error()}))
-	}
-
-	// Buat toko otomatis hanya jika user bukan admin
-	if !user.IsAdmin {
-		toko := models.Toko{
-			IDUser:   user.ID,
-			NamaToko: "Toko " + user.Nama,
-			UrlFoto:  nil,
+	var verification mailer.Message
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := createAccount(tx, &user); err != nil {
+			return err
 		}
-		config.DB.Create(&toko)
+
+		verification, err = newVerificationEmail(tx, user)
+		return err
+	})
+	if err != nil {
+		return apperror.Internal("auth.register_failed", err)
 	}
+	mailer.SendAsync(verification)
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Register berhasil", map[string]interface{}{
-		"user_id": user.ID,
-		"email":   user.Email,
-	}))
+	return c.JSON(http.StatusOK, resp)
 }
 
 // ===================================================
 // 🔐 LOGIN (POST)
 // ===================================================
+type LoginRequest struct {
+	Email     string `json:"email" validate:"required,max=100"`
+	KataSandi string `json:"kata_sandi" validate:"required,max=72"`
+}
+
 func Login(c echo.Context) error {
-	var input models.User
+	var input LoginRequest
 	var user models.User
 
 	// Bind input JSON
-	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", []string{err.Error()}))
+	if err := bindAndValidate(c, "common.invalid_request", &input); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if retryAfter, err := auth.CheckLoginAllowed(ctx, input.Email); err != nil {
+		return loginThrottled(c, retryAfter, err)
+	}
+
+	// Cari user berdasarkan email lalu cek password. Email tidak terdaftar dan
+	// password salah sengaja diberi respons yang sama supaya akun tidak bisa ditebak.
+	if err := config.DB.Preload("Roles").Preload("TwoFactor").Where("email = ?", input.Email).First(&user).Error; err != nil {
+		user = models.User{}
+	}
+	if !auth.CheckPassword(user.KataSandi, input.KataSandi) {
+		if err := auth.RecordLoginFailure(ctx, input.Email); err != nil {
+			return apperror.Internal("auth.login_failed", err)
+		}
+		return apperror.Unauthorized("auth.invalid_credentials").WithDetails([]string{"invalid_credentials"})
+	}
+	if err := auth.ResetLoginFailures(ctx, input.Email); err != nil {
+		return apperror.Internal("auth.login_failed", err)
+	}
+
+	return loginOrChallenge(c, user)
+}
+
+// createAccount menyimpan user baru dengan role default (admin hanya dibuat
+// lewat cmd/bootstrap) sekaligus tokonya. Dipakai Register dan login SSO.
+func createAccount(tx *gorm.DB, user *models.User) error {
+	for _, role := range rbac.DefaultRoles {
+		user.Roles = append(user.Roles, models.UserRole{Role: role})
+	}
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+
+	// Buat toko otomatis untuk user baru
+	toko := models.Toko{
+		IDUser:   user.ID,
+		NamaToko: "Toko " + user.Nama,
+		UrlFoto:  nil,
+	}
+	return tx.Create(&toko).Error
+}
+
+// loginOrChallenge dipanggil setelah faktor pertama (password / SSO) lolos.
+// Kalau 2FA aktif, token baru diberikan setelah kode TOTP diverifikasi di /login/2fa.
+func loginOrChallenge(c echo.Context, user models.User) error {
+	if user.TwoFactorEnabled() {
+		challenge, expiresIn, err := auth.NewChallengeToken(user)
+		if err != nil {
+			return apperror.Internal("auth.challenge_failed", err)
+		}
+		return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "auth.enter_2fa_code"), dto.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresIn:         expiresIn,
+		}))
 	}
 
-	// Cari user berdasarkan email
-	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Email tidak ditemukan", []string{"email_not_found"}))
+	return loginSuccess(c, user)
+}
+
+// ===================================================
+// 🔐 LOGIN LANGKAH 2 - KODE 2FA (POST)
+// ===================================================
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	// kode TOTP 6 digit atau recovery code
+	Code string `json:"code" validate:"required"`
+}
+
+func LoginTwoFactor(c echo.Context) error {
+	var req LoginTwoFactorRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
 	}
 
-	// Cek password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.KataSandi), []byte(input.KataSandi)); err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Password salah", []string{"invalid_password"}))
+	challenge, err := auth.ParseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		return apperror.Unauthorized(errKey(auth.ErrInvalidChallenge)).WithDetails([]string{"invalid_challenge"})
+	}
+
+	var user models.User
+	if err := config.DB.Preload("Roles").Preload("TwoFactor").First(&user, challenge.UserID).Error; err != nil || user.TokenVersion != challenge.TokenVersion {
+		return apperror.Unauthorized(errKey(auth.ErrInvalidChallenge)).WithDetails([]string{"invalid_challenge"})
 	}
 
-	// Buat token JWT (berlaku 3 hari)
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"exp":     time.Now().Add(72 * time.Hour).Unix(),
+	// kode 2FA yang salah dihitung sama dengan password salah (backoff + lockout)
+	ctx := c.Request().Context()
+	if retryAfter, err := auth.CheckLoginAllowed(ctx, user.Email); err != nil {
+		return loginThrottled(c, retryAfter, err)
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return auth.VerifySecondFactor(tx, user.ID, req.Code)
+	})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal membuat token", []string{err.Error()}))
+		if errors.Is(err, auth.ErrInvalidTwoFactorCode) || errors.Is(err, auth.ErrTwoFactorNotEnabled) {
+			if err := auth.RecordLoginFailure(ctx, user.Email); err != nil {
+				return apperror.Internal("auth.verify_2fa_failed", err)
+			}
+			return apperror.Unauthorized(errKey(err)).WithDetails([]string{"invalid_2fa_code"})
+		}
+		return apperror.Internal("auth.verify_2fa_failed", err)
 	}
 
-	// Siapkan data respons
-	data := map[string]interface{}{
-		"id":             user.ID,
-		"nama":           user.Nama,
-		"no_telp":        user.NoTelp,
-		"tanggal_lahir":  user.TanggalLahir,
-		"tentang":        user.Tentang,
-		"pekerjaan":      user.Pekerjaan,
-		"email":          user.Email,
-		"id_provinsi":    user.IDProvinsi,
-		"id_kota":        user.IDKota,
-		"is_admin":       user.IsAdmin,
-		"token":          tokenString,
+	// challenge sekali pakai
+	if err := auth.RevokeAccessToken(user.ID, challenge.JTI, challenge.ExpiresAt); err != nil {
+		return apperror.Internal("auth.verify_2fa_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Login berhasil", data))
+	return loginSuccess(c, user)
+}
+
+// clientInfo mengambil data perangkat untuk session dari request
+func clientInfo(c echo.Context) auth.ClientInfo {
+	return auth.ClientInfo{
+		IP:         c.RealIP(),
+		UserAgent:  c.Request().UserAgent(),
+		DeviceName: c.Request().Header.Get("X-Device-Name"),
+	}
+}
+
+// loginThrottled membalas 429 + Retry-After saat akun sedang backoff / dikunci
+func loginThrottled(c echo.Context, retryAfter time.Duration, err error) error {
+	if !errors.Is(err, auth.ErrAccountLocked) && !errors.Is(err, auth.ErrLoginBackoff) {
+		return apperror.Internal("auth.login_failed", err)
+	}
+
+	code := "too_many_attempts"
+	if errors.Is(err, auth.ErrAccountLocked) {
+		code = "account_locked"
+	}
+	seconds := ratelimit.RetryAfterSeconds(retryAfter)
+	c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+	return apperror.TooManyRequests(errKey(err)).WithDetails([]i18n.Message{i18n.M(code), i18n.M("auth.retry_after", seconds)})
+}
+
+// loginSuccess membuat token dan respons login (dipakai Login & LoginTwoFactor)
+func loginSuccess(c echo.Context, user models.User) error {
+	// Buat access token (singkat) + refresh token
+	tokens, err := auth.IssueTokens(user, clientInfo(c))
+	if err != nil {
+		return apperror.Internal("auth.token_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "auth.login_success"), dto.NewLogin(user, *tokens)))
+}
+
+// ===================================================
+// 🔄 REFRESH TOKEN (POST)
+// ===================================================
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func Refresh(c echo.Context) error {
+	var req RefreshRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+
+	tokens, _, err := auth.Refresh(req.RefreshToken, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			return apperror.Unauthorized(errKey(err)).WithDetails([]string{"invalid_refresh_token"})
+		}
+		return apperror.Internal("auth.refresh_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "auth.refresh_success"), tokens))
+}
+
+// ===================================================
+// 🚪 LOGOUT (POST)
+// ===================================================
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	// true = keluar dari semua perangkat
+	All bool `json:"all"`
+}
+
+func Logout(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req LogoutRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+
+	if req.All {
+		if err := auth.RevokeAll(config.DB, authUser.ID); err != nil {
+			return apperror.Internal("auth.logout_failed", err)
+		}
+		return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "auth.logout_all_success"), nil))
+	}
+
+	// cabut access token yang sedang dipakai dan akhiri session-nya
+	if token, ok := c.Get("user").(*jwt.Token); ok {
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if sid, ok := claims["sid"].(float64); ok {
+				err := auth.TerminateSession(config.DB, authUser.ID, uint64(sid))
+				if err != nil && !errors.Is(err, auth.ErrSessionNotFound) {
+					return apperror.Internal("auth.logout_failed", err)
+				}
+			}
+			jti, _ := claims["jti"].(string)
+			exp, _ := claims.GetExpirationTime()
+			expiresAt := time.Now().Add(config.App.JWT.TokenTTL)
+			if exp != nil {
+				expiresAt = exp.Time
+			}
+			if err := auth.RevokeAccessToken(authUser.ID, jti, expiresAt); err != nil {
+				return apperror.Internal("auth.logout_failed", err)
+			}
+		}
+	}
+
+	if req.RefreshToken != "" {
+		if err := auth.RevokeRefreshToken(authUser.ID, req.RefreshToken); err != nil {
+			return apperror.Internal("auth.logout_failed", err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "auth.logout_success"), nil))
 }
 
 // ===================================================
 // 👤 PROFILE (GET)
 // ===================================================
 func Profile(c echo.Context) error {
+	if err := checkAPIKeyRoute(c); err != nil {
+		return err
+	}
 	authUser, ok := c.Get("authUser").(models.User)
 	if !ok {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User tidak ditemukan dalam context", []string{"unauthorized"}))
+		return apperror.Unauthorized("common.user_not_in_context").WithDetails([]string{"unauthorized"})
 	}
 
-	authUser.KataSandi = ""
-
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Token valid", map[string]interface{}{
-		"user": authUser,
-	}))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "auth.token_valid"), dto.Profile{User: dto.NewUser(authUser)}))
 }
@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"go-crud/apperror"
 	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
 	"go-crud/models"
 	"go-crud/utils"
 	"net/http"
@@ -14,91 +17,72 @@ import (
 func GetAllCategories(c echo.Context) error {
 	var categories []models.Category
 	if err := config.DB.Find(&categories).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET data", []string{err.Error()}))
+		return apperror.Internal("common.get_failed", err)
 	}
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", categories))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewCategories(categories)))
 }
 
 // GET /api/categories/:id
 func GetCategoryByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid category ID", []string{err.Error()}))
+		return apperror.BadRequest("category.invalid_id").WithDetails([]string{"category.invalid_id"})
 	}
 
 	var category models.Category
 	if err := config.DB.First(&category, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Category not found", []string{err.Error()}))
+		return apperror.NotFound("category.not_found").WithDetails([]string{"category.not_found"})
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", category))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewCategory(category)))
 }
 
-// POST /api/categories (Admin only)
-func CreateCategory(c echo.Context) error {
-	authUser, err := getAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
-	}
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Only admin can create category"}))
-	}
+type CategoryRequest struct {
+	NamaCategory string `json:"nama_category" validate:"required,max=100"`
+}
 
-	var req models.Category
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid input", []string{err.Error()}))
+// POST /api/categories (permission category:write)
+func CreateCategory(c echo.Context) error {
+	var req CategoryRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
 	}
 
-	if err := config.DB.Create(&req).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create category", []string{err.Error()}))
+	category := models.Category{NamaCategory: req.NamaCategory}
+	if err := config.DB.Create(&category).Error; err != nil {
+		return apperror.Internal("category.create_failed", err)
 	}
 
-	return c.JSON(http.StatusCreated, utils.SuccessResponse("Category created successfully", req))
+	return c.JSON(http.StatusCreated, utils.SuccessResponse(i18n.T(c, "category.created"), dto.NewCategory(category)))
 }
 
-// PUT /api/categories/:id (Admin only)
+// PUT /api/categories/:id (permission category:write)
 func UpdateCategory(c echo.Context) error {
-	authUser, err := getAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
-	}
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Only admin can update category"}))
-	}
-
 	id, _ := strconv.Atoi(c.Param("id"))
 	var category models.Category
 	if err := config.DB.First(&category, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Category not found", []string{err.Error()}))
+		return apperror.NotFound("category.not_found").WithDetails([]string{"category.not_found"})
 	}
 
-	var req models.Category
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid input", []string{err.Error()}))
+	var req CategoryRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
 	}
 
 	category.NamaCategory = req.NamaCategory
 	if err := config.DB.Save(&category).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update category", []string{err.Error()}))
+		return apperror.Internal("category.update_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Category updated successfully", category))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "category.updated"), dto.NewCategory(category)))
 }
 
-// DELETE /api/categories/:id (Admin only)
+// DELETE /api/categories/:id (permission category:write)
 func DeleteCategory(c echo.Context) error {
-	authUser, err := getAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
-	}
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Only admin can delete category"}))
-	}
-
 	id, _ := strconv.Atoi(c.Param("id"))
 	if err := config.DB.Delete(&models.Category{}, id).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete category", []string{err.Error()}))
+		return apperror.Internal("category.delete_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Category deleted successfully", nil))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "category.deleted"), nil))
 }
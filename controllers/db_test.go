@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"go-crud/apperror"
+	"go-crud/config"
+	"go-crud/migrations"
+	"go-crud/validation"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// openTestDB menyiapkan config.App bawaan dan database SQLite di memori yang
+// sudah dimigrasi, dipasang sebagai config.DB selama test berjalan
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	app := config.App
+	config.App = config.Default()
+	cfg := config.App.Database
+	cfg.Driver, cfg.Name = "sqlite", ":memory:"
+	config.ConnectDatabase(cfg)
+	t.Cleanup(func() {
+		config.CloseDatabase()
+		config.App = app
+	})
+	if _, err := migrations.Up(config.DB); err != nil {
+		t.Fatalf("migrasi: %v", err)
+	}
+	return config.DB
+}
+
+// postJSON memanggil handler langsung dengan body JSON, error handler-nya
+// sama dengan server
+func postJSON(h echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Validator = validation.New()
+	e.HTTPErrorHandler = apperror.NewHTTPErrorHandler(false)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if err := h(c); err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+	return rec
+}
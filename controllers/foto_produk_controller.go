@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"errors"
+	"go-crud/apperror"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/services"
+	"go-crud/utils"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type FotoProdukController struct {
+	fotos  services.FotoProdukService
+	limits services.PhotoLimits
+}
+
+func NewFotoProdukController(fotos services.FotoProdukService, limits services.PhotoLimits) *FotoProdukController {
+	return &FotoProdukController{fotos: fotos, limits: limits}
+}
+
+// multipartMemory bagian form yang disimpan di memori, sisanya ke file sementara
+const multipartMemory = 8 << 20
+
+func parseProdukID(c echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, apperror.BadRequest("product.invalid_id").WithDetails([]string{"product.invalid_id"})
+	}
+	return id, nil
+}
+
+func parseFotoID(c echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(c.Param("id_foto"), 10, 64)
+	if err != nil {
+		return 0, apperror.BadRequest("photo.invalid_id").WithDetails([]string{"photo.invalid_id"})
+	}
+	return id, nil
+}
+
+// GET /api/products/:id/photos
+func (h *FotoProdukController) ListPhotos(c echo.Context) error {
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+
+	fotos, err := h.fotos.List(c.Request().Context(), idProduk)
+	if err != nil {
+		return serviceError("common.get_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewFotoProdukList(fotos)))
+}
+
+// parsePhotoForm membaca file di field "foto" dari body multipart. Batas body
+// dicek sebelum form dibaca, supaya upload raksasa berhenti di awal; ukuran
+// tiap file dicek lagi oleh service. cleanup menutup dan menghapus file
+// sementara.
+func parsePhotoForm(c echo.Context, filesSize int64) (files []services.PhotoUpload, cleanup func(), err error) {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, filesSize+multipartMemory)
+	if err := req.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, nil, apperror.New(apperror.CodePayloadTooLarge, "photo.request_too_large").WithCause(err)
+		}
+		return nil, nil, bindError("photo.upload_failed", err)
+	}
+
+	var opened []multipart.File
+	cleanup = func() {
+		for _, f := range opened {
+			f.Close()
+		}
+		req.MultipartForm.RemoveAll()
+	}
+	for _, fh := range req.MultipartForm.File["foto"] {
+		f, err := fh.Open()
+		if err != nil {
+			cleanup()
+			return nil, nil, apperror.Internal("photo.upload_failed", err)
+		}
+		opened = append(opened, f)
+		files = append(files, services.PhotoUpload{Filename: fh.Filename, Size: fh.Size, Body: f})
+	}
+	return files, cleanup, nil
+}
+
+type UploadPhotosRequest struct {
+	Foto []*multipart.FileHeader `json:"foto" form:"foto" doc:"Satu atau beberapa file gambar (JPEG, PNG, WebP, GIF)"`
+}
+
+// POST /api/products/:id/photos (multipart/form-data, pemilik toko)
+func (h *FotoProdukController) UploadPhotos(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+
+	files, cleanup, err := parsePhotoForm(c, int64(h.limits.MaxCount)*h.limits.MaxSize)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	fotos, err := h.fotos.Upload(c.Request().Context(), *authUser, idProduk, files)
+	if err != nil {
+		return serviceError("photo.upload_failed", err)
+	}
+
+	return c.JSON(http.StatusCreated, utils.SuccessResponse(i18n.T(c, "photo.uploaded"), dto.NewFotoProdukList(fotos)))
+}
+
+type ReorderPhotosRequest struct {
+	IDs []uint64 `json:"ids" validate:"required,min=1,dive,min=1" doc:"Semua ID foto produk dalam urutan baru"`
+}
+
+// PUT /api/products/:id/photos/order (pemilik toko)
+func (h *FotoProdukController) ReorderPhotos(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+
+	var req ReorderPhotosRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
+	}
+
+	fotos, err := h.fotos.Reorder(c.Request().Context(), *authUser, idProduk, req.IDs)
+	if err != nil {
+		return serviceError("common.update_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "photo.reordered"), dto.NewFotoProdukList(fotos)))
+}
+
+// PUT /api/products/:id/photos/:id_foto/primary (pemilik toko)
+func (h *FotoProdukController) SetPrimaryPhoto(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseFotoID(c)
+	if err != nil {
+		return err
+	}
+
+	fotos, err := h.fotos.SetPrimary(c.Request().Context(), *authUser, idProduk, id)
+	if err != nil {
+		return serviceError("common.update_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "photo.primary_set"), dto.NewFotoProdukList(fotos)))
+}
+
+// DELETE /api/products/:id/photos/:id_foto (pemilik toko)
+func (h *FotoProdukController) DeletePhoto(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseFotoID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.fotos.Delete(c.Request().Context(), *authUser, idProduk, id); err != nil {
+		return serviceError("photo.delete_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "photo.deleted"), nil))
+}
@@ -1,11 +1,12 @@
 package controllers
 
 import (
+	"go-crud/i18n"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
 
 func Home(c echo.Context) error {
-    return c.String(http.StatusOK, "Welcome to the Home Page!")
+    return c.String(http.StatusOK, i18n.T(c, "home.welcome"))
 }
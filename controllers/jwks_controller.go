@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"go-crud/auth"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ===================================================
+// 🔑 GET /.well-known/jwks.json - public key untuk verifikasi JWT
+// ===================================================
+// Format mengikuti RFC 7517 (bukan BaseResponse) supaya bisa langsung dipakai
+// library JWT di layanan lain.
+func JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, auth.Keys.JWKS())
+}
@@ -0,0 +1,358 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/mailer"
+	"go-crud/models"
+	"go-crud/oidc"
+	"go-crud/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	errOIDCEmailMissing = errors.New("IdP tidak mengirim email, pastikan scope email diizinkan")
+	errOIDCEmailTaken   = errors.New("email sudah terdaftar, login dengan kata sandi lalu tautkan akun SSO dari menu profil")
+	errOIDCDomain       = errors.New("domain email ini tidak diizinkan login lewat SSO")
+	errIdentityLinked   = errors.New("akun SSO ini sudah ditautkan ke pengguna lain")
+	errIdentityNotFound = errors.New("akun SSO tidak ditemukan")
+)
+
+// ===================================================
+// 🔑 GET /oidc/login - arahkan ke halaman login IdP
+// ===================================================
+func OIDCLogin(c echo.Context) error {
+	authURL, err := auth.BeginOIDC(c.Request().Context(), nil)
+	if err != nil {
+		return oidcError(err)
+	}
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// ===================================================
+// 🔑 GET|POST /oidc/callback - IdP mengembalikan code + state
+// ===================================================
+// GET dipanggil langsung oleh redirect IdP; POST (JSON {code, state}) untuk
+// frontend yang memakai redirect_url ke halamannya sendiri.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" query:"code" form:"code" validate:"required_without=Error"`
+	State string `json:"state" query:"state" form:"state" validate:"required_without=Error"`
+	// diisi IdP kalau login dibatalkan / ditolak
+	Error            string `json:"error" query:"error" form:"error"`
+	ErrorDescription string `json:"error_description" query:"error_description" form:"error_description"`
+}
+
+func OIDCCallback(c echo.Context) error {
+	var req OIDCCallbackRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+	if req.Error != "" {
+		// user membatalkan login atau IdP menolak request
+		errs := []string{req.Error}
+		if req.ErrorDescription != "" {
+			errs = append(errs, req.ErrorDescription)
+		}
+		return apperror.BadRequest("oidc.cancelled").WithDetails(errs)
+	}
+
+	claims, err := auth.CompleteOIDC(c.Request().Context(), req.State, req.Code, nil)
+	if errors.Is(err, auth.ErrOIDCLinkPending) {
+		// code tidak berguna tanpa code_verifier yang disimpan server, dan hanya
+		// bisa diselesaikan oleh user yang memulai penautan
+		return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "oidc.link_continue"), dto.OIDCLinkPending{
+			LinkPending: true,
+			Code:        req.Code,
+			State:       req.State,
+		}))
+	}
+	if err != nil {
+		return oidcError(err)
+	}
+
+	var (
+		user         models.User
+		verification *mailer.Message
+	)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, verification, err = resolveOIDCUser(tx, claims)
+		return err
+	})
+	if err != nil {
+		return oidcError(err)
+	}
+	if verification != nil {
+		mailer.SendAsync(*verification)
+	}
+
+	if err := config.DB.Preload("Roles").Preload("TwoFactor").First(&user, user.ID).Error; err != nil {
+		return apperror.Internal("auth.login_failed", err)
+	}
+	return loginOrChallenge(c, user)
+}
+
+// resolveOIDCUser mencari user dari identitas SSO. Urutannya: identitas yang
+// sudah tertaut, lalu akun lama dengan email sama (kalau oidc.link_by_email
+// dan email sudah diverifikasi IdP), terakhir membuat akun + toko baru
+// seperti Register.
+func resolveOIDCUser(tx *gorm.DB, claims *oidc.Claims) (models.User, *mailer.Message, error) {
+	var user models.User
+	now := time.Now()
+
+	var identity models.UserIdentity
+	err := tx.Where("issuer = ? AND subject = ?", claims.Issuer, claims.Subject).First(&identity).Error
+	if err == nil {
+		if err := tx.Model(&identity).Updates(map[string]interface{}{
+			"email":         claims.Email,
+			"last_login_at": now,
+		}).Error; err != nil {
+			return user, nil, err
+		}
+		err = tx.First(&user, identity.IDUser).Error
+		return user, nil, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, nil, err
+	}
+
+	if claims.Email == "" {
+		return user, nil, errOIDCEmailMissing
+	}
+
+	var verification *mailer.Message
+	err = tx.Where("email = ?", claims.Email).First(&user).Error
+	switch {
+	case err == nil:
+		// tanpa email_verified siapa pun bisa mengaku pemilik email di IdP
+		if !config.App.OIDC.LinkByEmail || !claims.EmailVerified {
+			return user, nil, errOIDCEmailTaken
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if !oidcDomainAllowed(claims.Email) {
+			return user, nil, errOIDCDomain
+		}
+		user, verification, err = provisionOIDCUser(tx, claims)
+		if err != nil {
+			return user, nil, err
+		}
+	default:
+		return user, nil, err
+	}
+
+	identity = models.UserIdentity{
+		IDUser:      user.ID,
+		Issuer:      claims.Issuer,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	}
+	if err := tx.Create(&identity).Error; err != nil {
+		return user, nil, err
+	}
+	return user, verification, nil
+}
+
+// provisionOIDCUser membuat akun untuk user SSO yang baru pertama kali login.
+// Kata sandi diisi acak, user bisa memasang kata sandi sendiri lewat lupa kata sandi.
+func provisionOIDCUser(tx *gorm.DB, claims *oidc.Claims) (models.User, *mailer.Message, error) {
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return models.User{}, nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(base64.RawURLEncoding.EncodeToString(password)), bcrypt.DefaultCost)
+	if err != nil {
+		return models.User{}, nil, err
+	}
+
+	nama := claims.Name
+	if nama == "" {
+		nama = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	user := models.User{
+		Nama:      nama,
+		Email:     claims.Email,
+		KataSandi: string(hashedPassword),
+	}
+	if claims.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := createAccount(tx, &user); err != nil {
+		return user, nil, err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return user, nil, nil
+	}
+	verification, err := newVerificationEmail(tx, user)
+	if err != nil {
+		return user, nil, err
+	}
+	return user, &verification, nil
+}
+
+func oidcDomainAllowed(email string) bool {
+	domains := config.App.OIDC.AllowedDomains
+	if len(domains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range domains {
+		if strings.ToLower(d) == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// ===================================================
+// 🔗 GET /api/identities - akun SSO yang tertaut
+// ===================================================
+func GetMyIdentities(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var identities []models.UserIdentity
+	if err := config.DB.Where("id_user = ?", authUser.ID).Order("id").Find(&identities).Error; err != nil {
+		return apperror.Internal("oidc.list_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewIdentities(identities)))
+}
+
+// ===================================================
+// 🔗 POST /api/identities/link - mulai menautkan akun SSO
+// ===================================================
+// Mengembalikan URL login IdP. Setelah redirect, frontend mengirim code + state
+// ke POST /api/identities/callback dengan token user yang sama.
+func LinkIdentity(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	authURL, err := auth.BeginOIDC(c.Request().Context(), &authUser.ID)
+	if err != nil {
+		return oidcError(err)
+	}
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "oidc.continue"), dto.AuthorizationURL{AuthorizationURL: authURL}))
+}
+
+// ===================================================
+// 🔗 POST /api/identities/callback - selesaikan penautan akun SSO
+// ===================================================
+type LinkIdentityRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
+
+func LinkIdentityCallback(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req LinkIdentityRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+
+	claims, err := auth.CompleteOIDC(c.Request().Context(), req.State, req.Code, &authUser.ID)
+	if err != nil {
+		return oidcError(err)
+	}
+
+	now := time.Now()
+	identity := models.UserIdentity{
+		IDUser:      authUser.ID,
+		Issuer:      claims.Issuer,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.UserIdentity
+		err := tx.Where("issuer = ? AND subject = ?", claims.Issuer, claims.Subject).First(&existing).Error
+		switch {
+		case err == nil && existing.IDUser == authUser.ID:
+			identity = existing
+			return nil
+		case err == nil:
+			return errIdentityLinked
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		return tx.Create(&identity).Error
+	})
+	if err != nil {
+		return oidcError(err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "oidc.linked"), dto.NewIdentity(identity)))
+}
+
+// ===================================================
+// 🔗 DELETE /api/identities/:id - lepas tautan akun SSO
+// ===================================================
+func UnlinkIdentity(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("common.delete_failed").WithDetails([]string{"common.invalid_id"})
+	}
+
+	res := config.DB.Where("id = ? AND id_user = ?", id, authUser.ID).Delete(&models.UserIdentity{})
+	if res.Error != nil {
+		return apperror.Internal("common.delete_failed", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("common.delete_failed").WithDetails([]string{errKey(errIdentityNotFound)})
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "oidc.unlinked"), nil))
+}
+
+// oidcError memetakan error alur SSO ke apperror. Detail error dari IdP
+// (token endpoint / verifikasi id_token) hanya dicatat di log.
+func oidcError(err error) error {
+	switch {
+	case errors.Is(err, oidc.ErrDisabled):
+		return apperror.NotFound(errKey(err))
+	case errors.Is(err, auth.ErrInvalidOIDCState), errors.Is(err, errOIDCEmailMissing):
+		return apperror.BadRequest("oidc.failed").WithDetails([]string{errKey(err)})
+	case errors.Is(err, errOIDCEmailTaken), errors.Is(err, errIdentityLinked):
+		return apperror.Conflict("oidc.failed").WithDetails([]string{errKey(err)})
+	case errors.Is(err, errOIDCDomain):
+		return apperror.Forbidden("oidc.failed").WithDetails([]string{errKey(err)})
+	case errors.Is(err, oidc.ErrInvalidIDToken):
+		log.Printf("login SSO: %v", err)
+		return apperror.Unauthorized("oidc.failed").WithDetails([]string{errKey(oidc.ErrInvalidIDToken)})
+	default:
+		return apperror.BadGateway("oidc.failed").WithDetails([]string{"oidc.provider_failed"}).WithCause(err)
+	}
+}
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"go-crud/auth"
+	"go-crud/config"
+	"go-crud/mailer"
+	"go-crud/models"
+	"go-crud/oidc"
+	"go-crud/oidc/devidp"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+const oidcRedirectURL = "http://toko.test/oidc/callback"
+
+// startIdP menjalankan oidc/devidp di httptest lalu mengaktifkan login SSO
+// ke IdP tersebut. Dipanggil setelah openTestDB.
+func startIdP(t *testing.T) *httptest.Server {
+	t.Helper()
+	var idp *devidp.IdP
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	var err error
+	idp, err = devidp.New(devidp.Config{
+		Issuer:       srv.URL,
+		ClientID:     "go-crud",
+		ClientSecret: "dev-secret",
+		RedirectURIs: []string{oidcRedirectURL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.App.OIDC.Issuer = srv.URL
+	config.App.OIDC.ClientID = "go-crud"
+	config.App.OIDC.ClientSecret = "dev-secret"
+	config.App.OIDC.RedirectURL = oidcRedirectURL
+	old := oidc.Default
+	oidc.Init(config.App.OIDC)
+	t.Cleanup(func() { oidc.Default = old })
+	return srv
+}
+
+// idpLogin membuka halaman login IdP dari authURL lalu mengisi formnya
+// seperti browser, dan mengembalikan state + code dari redirect ke callback
+func idpLogin(t *testing.T, srv *httptest.Server, authURL, email string, verified bool) (string, string) {
+	t.Helper()
+	client := srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("halaman login IdP: status %d", resp.StatusCode)
+	}
+
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	form := url.Values{"email": {email}, "name": {"Budi SSO"}}
+	for _, k := range []string{"redirect_uri", "state", "nonce", "code_challenge"} {
+		form.Set(k, q.Get(k))
+	}
+	if verified {
+		form.Set("email_verified", "true")
+	}
+	resp, err = client.PostForm(srv.URL+"/authorize", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	if resp.StatusCode != http.StatusFound || err != nil || !strings.HasPrefix(loc.String(), oidcRedirectURL) {
+		t.Fatalf("IdP tidak redirect ke callback: %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	return loc.Query().Get("state"), loc.Query().Get("code")
+}
+
+// oidcLogin menjalankan BeginOIDC, login di IdP, lalu CompleteOIDC
+func oidcLogin(t *testing.T, srv *httptest.Server, email string, verified bool) *oidc.Claims {
+	t.Helper()
+	ctx := context.Background()
+	authURL, err := auth.BeginOIDC(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginOIDC: %v", err)
+	}
+	state, code := idpLogin(t, srv, authURL, email, verified)
+	claims, err := auth.CompleteOIDC(ctx, state, code, nil)
+	if err != nil {
+		t.Fatalf("CompleteOIDC: %v", err)
+	}
+	return claims
+}
+
+func TestOIDCAuthorizationCodePKCE(t *testing.T) {
+	db := openTestDB(t)
+	srv := startIdP(t)
+	ctx := context.Background()
+
+	authURL, err := auth.BeginOIDC(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginOIDC: %v", err)
+	}
+	q, _ := url.Parse(authURL)
+	var state models.OIDCState
+	db.First(&state)
+	if q.Query().Get("code_challenge_method") != "S256" || q.Query().Get("code_challenge") != oidc.S256Challenge(state.CodeVerifier) {
+		t.Fatalf("code_challenge %q bukan S256 dari code_verifier yang disimpan", q.Query().Get("code_challenge"))
+	}
+
+	rawState, code := idpLogin(t, srv, authURL, "budi@example.com", true)
+	claims, err := auth.CompleteOIDC(ctx, rawState, code, nil)
+	if err != nil {
+		t.Fatalf("CompleteOIDC: %v", err)
+	}
+	if claims.Issuer != srv.URL || claims.Email != "budi@example.com" || !claims.EmailVerified || claims.Subject == "" {
+		t.Errorf("claims %+v", claims)
+	}
+
+	// state hanya bisa dipakai sekali
+	if _, err := auth.CompleteOIDC(ctx, rawState, code, nil); !errors.Is(err, auth.ErrInvalidOIDCState) {
+		t.Errorf("callback diulang: err %v, seharusnya ErrInvalidOIDCState", err)
+	}
+
+	// IdP menolak code kalau code_verifier tidak cocok dengan code_challenge
+	authURL, _ = auth.BeginOIDC(ctx, nil)
+	rawState, code = idpLogin(t, srv, authURL, "budi@example.com", true)
+	db.Model(&models.OIDCState{}).Where("1 = 1").Update("code_verifier", strings.Repeat("x", 64))
+	if _, err := auth.CompleteOIDC(ctx, rawState, code, nil); err == nil || errors.Is(err, auth.ErrInvalidOIDCState) {
+		t.Errorf("code_verifier salah: err %v, seharusnya ditolak IdP", err)
+	}
+}
+
+// state untuk menautkan akun hanya bisa diselesaikan oleh user yang memulainya
+func TestOIDCLinkState(t *testing.T) {
+	db := openTestDB(t)
+	srv := startIdP(t)
+	ctx := context.Background()
+	user := createUser(t, db, "budi@example.com")
+	other := user.ID + 1
+
+	authURL, err := auth.BeginOIDC(ctx, &user.ID)
+	if err != nil {
+		t.Fatalf("BeginOIDC: %v", err)
+	}
+	state, code := idpLogin(t, srv, authURL, "budi.sso@example.com", true)
+
+	if _, err := auth.CompleteOIDC(ctx, state, code, nil); !errors.Is(err, auth.ErrOIDCLinkPending) {
+		t.Errorf("lewat callback login: err %v, seharusnya ErrOIDCLinkPending", err)
+	}
+	if _, err := auth.CompleteOIDC(ctx, state, code, &other); !errors.Is(err, auth.ErrInvalidOIDCState) {
+		t.Errorf("oleh user lain: err %v, seharusnya ErrInvalidOIDCState", err)
+	}
+	claims, err := auth.CompleteOIDC(ctx, state, code, &user.ID)
+	if err != nil {
+		t.Fatalf("oleh pemiliknya: %v", err)
+	}
+	if claims.Email != "budi.sso@example.com" {
+		t.Errorf("email %s", claims.Email)
+	}
+}
+
+func TestResolveOIDCUser(t *testing.T) {
+	cases := []struct {
+		name        string
+		existing    bool // sudah ada akun dengan email yang sama
+		verified    bool // email_verified dari IdP
+		linkByEmail bool
+		err         error
+		// akun baru dibuat (bukan akun lama yang ditautkan)
+		provisioned bool
+	}{
+		{"akun baru, email terverifikasi", false, true, true, nil, true},
+		{"akun baru, email belum terverifikasi", false, false, true, nil, true},
+		{"akun lama ditautkan lewat email terverifikasi", true, true, true, nil, false},
+		{"akun lama, email belum terverifikasi", true, false, true, errOIDCEmailTaken, false},
+		{"akun lama, link_by_email mati", true, true, false, errOIDCEmailTaken, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := openTestDB(t)
+			srv := startIdP(t)
+			config.App.OIDC.LinkByEmail = tc.linkByEmail
+			const email = "budi@example.com"
+			var existing models.User
+			if tc.existing {
+				existing = createUser(t, db, email)
+			}
+
+			resolve := func() (models.User, *mailer.Message, error) {
+				claims := oidcLogin(t, srv, email, tc.verified)
+				var (
+					user         models.User
+					verification *mailer.Message
+				)
+				err := db.Transaction(func(tx *gorm.DB) error {
+					var err error
+					user, verification, err = resolveOIDCUser(tx, claims)
+					return err
+				})
+				return user, verification, err
+			}
+
+			user, verification, err := resolve()
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err %v, seharusnya %v", err, tc.err)
+			}
+			var identities int64
+			db.Model(&models.UserIdentity{}).Count(&identities)
+			if err != nil {
+				if identities != 0 {
+					t.Errorf("%d identitas tersimpan padahal gagal", identities)
+				}
+				return
+			}
+			if identities != 1 {
+				t.Errorf("%d identitas tersimpan, seharusnya 1", identities)
+			}
+
+			if tc.provisioned {
+				var toko models.Toko
+				if err := db.Where("id_user = ?", user.ID).First(&toko).Error; err != nil {
+					t.Errorf("toko user baru: %v", err)
+				}
+				if user.Nama != "Budi SSO" {
+					t.Errorf("nama %q, seharusnya dari klaim name", user.Nama)
+				}
+				// email yang belum diverifikasi IdP harus diverifikasi sendiri
+				if got := user.EmailVerifiedAt != nil; got != tc.verified {
+					t.Errorf("email_verified_at terisi %v, seharusnya %v", got, tc.verified)
+				}
+				if got := verification != nil; got == tc.verified {
+					t.Errorf("email verifikasi dibuat %v, seharusnya %v", got, !tc.verified)
+				}
+			} else if user.ID != existing.ID || verification != nil {
+				t.Errorf("user %d (verifikasi %v), seharusnya akun lama %d", user.ID, verification != nil, existing.ID)
+			}
+
+			// login berikutnya memakai identitas yang sudah tertaut
+			again, _, err := resolve()
+			if err != nil || again.ID != user.ID {
+				t.Errorf("login kedua: user %d err %v, seharusnya user %d", again.ID, err, user.ID)
+			}
+			var users int64
+			db.Model(&models.User{}).Count(&users)
+			if users != 1 {
+				t.Errorf("%d user, seharusnya 1", users)
+			}
+		})
+	}
+}
@@ -1,156 +1,201 @@
 package controllers
 
 import (
-	"go-crud/config"
-	"go-crud/models"
+	"go-crud/apperror"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/services"
 	"go-crud/utils"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
-// GET /api/products
-func GetAllProducts(c echo.Context) error {
-	var products []models.Produk
-	if err := config.DB.Preload("Toko").Preload("Category").Preload("Fotos").Find(&products).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET data", []string{err.Error()}))
-	}
+type ProdukController struct {
+	produk services.ProdukService
+}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", products))
+func NewProdukController(produk services.ProdukService) *ProdukController {
+	return &ProdukController{produk: produk}
 }
 
-// GET /api/products/:id
-func GetProductByID(c echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+type ListProductsQuery struct {
+	Q          string  `query:"q" validate:"omitempty,max=100" doc:"Kata kunci di nama_produk dan deskripsi"`
+	IDCategory *uint64 `query:"id_category" validate:"omitempty,min=1"`
+	IDToko     *uint64 `query:"id_toko" validate:"omitempty,min=1"`
+	HargaMin   *int    `query:"harga_min" validate:"omitempty,min=0" doc:"Batas bawah harga_konsumen"`
+	HargaMax   *int    `query:"harga_max" validate:"omitempty,min=0" doc:"Batas atas harga_konsumen"`
+	InStock    bool    `query:"in_stock" doc:"Hanya produk dengan stok > 0"`
+	Sort       string  `query:"sort" validate:"omitempty,oneof=newest price_asc price_desc name" doc:"Default newest"`
+	Page       int     `query:"page" validate:"omitempty,min=1,max=10000" doc:"Default 1"`
+	Limit      int     `query:"limit" validate:"omitempty,min=1,max=100" doc:"Default 20"`
+}
+
+// GET /api/products
+func (h *ProdukController) GetAllProducts(c echo.Context) error {
+	var query ListProductsQuery
+	if err := bindAndValidate(c, "common.invalid_input", &query); err != nil {
+		return err
+	}
+
+	result, err := h.produk.List(c.Request().Context(), services.ProdukQuery{
+		Keyword:    query.Q,
+		IDCategory: query.IDCategory,
+		IDToko:     query.IDToko,
+		MinHarga:   query.HargaMin,
+		MaxHarga:   query.HargaMax,
+		InStock:    query.InStock,
+		Sort:       query.Sort,
+		Page:       query.Page,
+		Limit:      query.Limit,
+	})
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid product ID", []string{err.Error()}))
+		return serviceError("common.get_failed", err)
 	}
 
-	var product models.Produk
-	if err := config.DB.Preload("Toko").Preload("Category").Preload("Fotos").First(&product, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Product not found", []string{"Produk tidak ditemukan"}))
-	}
+	data := dto.NewPage(dto.NewProdukList(result.Items), result.Page, result.Limit, result.Total)
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), data))
+}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", product))
+type SearchProductsQuery struct {
+	Q          string  `query:"q" validate:"omitempty,max=100" doc:"Kata kunci; kosong = semua produk"`
+	IDCategory *uint64 `query:"id_category" validate:"omitempty,min=1"`
+	IDToko     *uint64 `query:"id_toko" validate:"omitempty,min=1"`
+	Exact      bool    `query:"exact" doc:"Matikan toleransi typo dan pencocokan awalan kata"`
+	Page       int     `query:"page" validate:"omitempty,min=1,max=10000" doc:"Default 1"`
+	Limit      int     `query:"limit" validate:"omitempty,min=1,max=100" doc:"Default 20"`
 }
 
-// POST /api/products (pemilik toko)
-func CreateProduct(c echo.Context) error {
-	authUser, err := getAuthUser(c)
+// GET /api/products/search
+func (h *ProdukController) SearchProducts(c echo.Context) error {
+	var query SearchProductsQuery
+	if err := bindAndValidate(c, "common.invalid_input", &query); err != nil {
+		return err
+	}
+
+	result, err := h.produk.Search(c.Request().Context(), services.SearchProdukQuery{
+		Keyword:    query.Q,
+		IDCategory: query.IDCategory,
+		IDToko:     query.IDToko,
+		Exact:      query.Exact,
+		Page:       query.Page,
+		Limit:      query.Limit,
+	})
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return serviceError("common.get_failed", err)
 	}
 
-	// Ambil toko milik user login
-	var store models.Toko
-	if err := config.DB.Where("id_user = ?", authUser.ID).First(&store).Error; err != nil {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("You don't have a store", []string{"User belum memiliki toko"}))
-	}
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewProdukSearch(*result)))
+}
 
-	var req models.Produk
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid input", []string{err.Error()}))
+// GET /api/products/:id
+func (h *ProdukController) GetProductByID(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("product.invalid_id").WithDetails([]string{"product.invalid_id"})
 	}
 
-	req.IDToko = store.ID
-	req.Slug = strings.ToLower(strings.ReplaceAll(req.NamaProduk, " ", "-"))
-
-	if err := config.DB.Create(&req).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create product", []string{err.Error()}))
+	product, err := h.produk.Get(c.Request().Context(), id)
+	if err != nil {
+		return serviceError("product.not_found", err)
 	}
 
-	return c.JSON(http.StatusCreated, utils.SuccessResponse("Product created successfully", req))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewProduk(*product)))
 }
 
-// PUT /api/products/:id (pemilik toko)
-func UpdateProduct(c echo.Context) error {
-	authUser, _ := getAuthUser(c)
-	id, _ := strconv.Atoi(c.Param("id"))
+type CreateProductRequest struct {
+	NamaProduk    string  `json:"nama_produk" form:"nama_produk" validate:"required,max=150"`
+	HargaReseller int     `json:"harga_reseller" form:"harga_reseller" validate:"harga,ltefield=HargaKonsumen"`
+	HargaKonsumen int     `json:"harga_konsumen" form:"harga_konsumen" validate:"required,harga"`
+	Stok          int     `json:"stok" form:"stok" validate:"min=0"`
+	Deskripsi     *string `json:"deskripsi" form:"deskripsi" validate:"omitempty,max=5000"`
+	IDCategory    *uint64 `json:"id_category" form:"id_category" validate:"omitempty,min=1"`
+}
 
-	var product models.Produk
-	if err := config.DB.First(&product, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Product not found", []string{"Produk tidak ditemukan"}))
+// POST /api/products (pemilik toko)
+func (h *ProdukController) CreateProduct(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
 	}
 
-	// Cek apakah produk milik toko user
-	var store models.Toko
-	if err := config.DB.First(&store, product.IDToko).Error; err != nil {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Unauthorized", []string{"Toko tidak ditemukan"}))
-	}
-	if store.IDUser != authUser.ID {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Tidak dapat mengubah produk milik toko lain"}))
+	var req CreateProductRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
 	}
 
-	// Bind input sementara
-	var req struct {
-		NamaProduk    string  `json:"nama_produk" form:"nama_produk"`
-		HargaKonsumen float64 `json:"harga_konsumen" form:"harga_konsumen"`
-		HargaReseller float64 `json:"harga_reseller" form:"harga_reseller"`
-		Stok          int     `json:"stok" form:"stok"`
-		Deskripsi     string  `json:"deskripsi" form:"deskripsi"`
-		IDCategory    uint64  `json:"id_category" form:"id_category"`
-	}
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid input", []string{err.Error()}))
+	product, err := h.produk.Create(c.Request().Context(), *authUser, services.ProdukInput{
+		NamaProduk:    req.NamaProduk,
+		HargaReseller: req.HargaReseller,
+		HargaKonsumen: req.HargaKonsumen,
+		Stok:          req.Stok,
+		Deskripsi:     req.Deskripsi,
+		IDCategory:    req.IDCategory,
+	})
+	if err != nil {
+		return serviceError("product.create_failed", err)
 	}
 
-	// Hanya update field yang dikirim
-	updates := map[string]interface{}{}
-	if req.NamaProduk != "" {
-		updates["nama_produk"] = req.NamaProduk
-		updates["slug"] = strings.ToLower(strings.ReplaceAll(req.NamaProduk, " ", "-"))
-	}
-	if req.HargaKonsumen != 0 {
-		updates["harga_konsumen"] = req.HargaKonsumen
-	}
-	if req.HargaReseller != 0 {
-		updates["harga_reseller"] = req.HargaReseller
-	}
-	if req.Stok != 0 {
-		updates["stok"] = req.Stok
-	}
-	if req.Deskripsi != "" {
-		updates["deskripsi"] = req.Deskripsi
+	return c.JSON(http.StatusCreated, utils.SuccessResponse(i18n.T(c, "product.created"), dto.NewProduk(*product)))
+}
+
+// UpdateProductRequest field yang tidak dikirim tidak diubah
+type UpdateProductRequest struct {
+	NamaProduk    *string `json:"nama_produk" form:"nama_produk" validate:"omitnil,min=1,max=150"`
+	HargaKonsumen *int    `json:"harga_konsumen" form:"harga_konsumen" validate:"omitnil,harga"`
+	HargaReseller *int    `json:"harga_reseller" form:"harga_reseller" validate:"omitnil,harga"`
+	Stok          *int    `json:"stok" form:"stok" validate:"omitnil,min=0"`
+	Deskripsi     *string `json:"deskripsi" form:"deskripsi" validate:"omitnil,max=5000"`
+	IDCategory    *uint64 `json:"id_category" form:"id_category" validate:"omitnil,min=1"`
+}
+
+// PUT /api/products/:id (pemilik toko)
+func (h *ProdukController) UpdateProduct(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
 	}
-	if req.IDCategory != 0 {
-		updates["id_category"] = req.IDCategory
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("product.invalid_id").WithDetails([]string{"product.invalid_id"})
 	}
 
-	if len(updates) == 0 {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("No data to update", []string{"Tidak ada data yang diubah"}))
+	// field yang tidak dikirim tidak diubah
+	var req UpdateProductRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
 	}
 
-	if err := config.DB.Model(&product).Updates(updates).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update product", []string{err.Error()}))
+	_, err = h.produk.Update(c.Request().Context(), *authUser, id, services.UpdateProdukInput{
+		NamaProduk:    req.NamaProduk,
+		HargaKonsumen: req.HargaKonsumen,
+		HargaReseller: req.HargaReseller,
+		Stok:          req.Stok,
+		Deskripsi:     req.Deskripsi,
+		IDCategory:    req.IDCategory,
+	})
+	if err != nil {
+		return serviceError("product.update_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to UPDATE data", "Update produk succeed"))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.update_success"), i18n.T(c, "product.updated")))
 }
 
-
 // DELETE /api/products/:id (pemilik toko)
-func DeleteProduct(c echo.Context) error {
-	authUser, _ := getAuthUser(c)
-	id, _ := strconv.Atoi(c.Param("id"))
-
-	var product models.Produk
-	if err := config.DB.First(&product, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Product not found", []string{"Produk tidak ditemukan"}))
-	}
-
-	var store models.Toko
-	if err := config.DB.First(&store, product.IDToko).Error; err != nil {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Unauthorized", []string{"Toko tidak ditemukan"}))
+func (h *ProdukController) DeleteProduct(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
 	}
-	if store.IDUser != authUser.ID {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Tidak dapat menghapus produk milik toko lain"}))
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("product.invalid_id").WithDetails([]string{"product.invalid_id"})
 	}
 
-	if err := config.DB.Delete(&product).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete product", []string{err.Error()}))
+	if err := h.produk.Delete(c.Request().Context(), *authUser, id); err != nil {
+		return serviceError("product.delete_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Product deleted successfully", nil))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "product.deleted"), nil))
 }
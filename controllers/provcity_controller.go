@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-crud/apperror"
+	"go-crud/i18n"
 	"net/http"
 
 	"go-crud/utils"
@@ -9,34 +11,22 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-// Struct untuk mapping JSON dari API emsifa
-type Province struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
-
-type City struct {
-	ID         string `json:"id"`
-	ProvinceID string `json:"province_id"`
-	Name       string `json:"name"`
-}
-
 // ===================================================
 // 🔹 GET /provcity/listprovinces
 // ===================================================
 func GetListProvinces(c echo.Context) error {
-	resp, err := http.Get("https://www.emsifa.com/api-wilayah-indonesia/api/provinces.json")
+	resp, err := http.Get(utils.WilayahURL("provinces.json"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET provinces", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.provinces_failed").WithCause(err)
 	}
 	defer resp.Body.Close()
 
-	var provinces []Province
+	var provinces []utils.Province
 	if err := json.NewDecoder(resp.Body).Decode(&provinces); err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to parse provinces", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.provinces_failed").WithCause(err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET provinces", provinces))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "wilayah.provinces_success"), provinces))
 }
 
 // ===================================================
@@ -45,18 +35,18 @@ func GetListProvinces(c echo.Context) error {
 func GetListCities(c echo.Context) error {
 	provinceID := c.Param("province_id")
 
-	resp, err := http.Get("https://www.emsifa.com/api-wilayah-indonesia/api/regencies/" + provinceID + ".json")
+	resp, err := http.Get(utils.WilayahURL("regencies/" + provinceID + ".json"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET cities", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.cities_failed").WithCause(err)
 	}
 	defer resp.Body.Close()
 
-	var cities []City
+	var cities []utils.City
 	if err := json.NewDecoder(resp.Body).Decode(&cities); err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to parse cities", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.cities_failed").WithCause(err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET cities", cities))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "wilayah.cities_success"), cities))
 }
 
 // ===================================================
@@ -65,18 +55,18 @@ func GetListCities(c echo.Context) error {
 func GetDetailProvince(c echo.Context) error {
 	id := c.Param("id")
 
-	resp, err := http.Get("https://www.emsifa.com/api-wilayah-indonesia/api/province/" + id + ".json")
+	resp, err := http.Get(utils.WilayahURL("province/" + id + ".json"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET province detail", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.province_failed").WithCause(err)
 	}
 	defer resp.Body.Close()
 
-	var province Province
+	var province utils.Province
 	if err := json.NewDecoder(resp.Body).Decode(&province); err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to parse province detail", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.province_failed").WithCause(err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET province detail", province))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "wilayah.province_success"), province))
 }
 
 // ===================================================
@@ -85,16 +75,16 @@ func GetDetailProvince(c echo.Context) error {
 func GetDetailCity(c echo.Context) error {
 	id := c.Param("id")
 
-	resp, err := http.Get("https://www.emsifa.com/api-wilayah-indonesia/api/regency/" + id + ".json")
+	resp, err := http.Get(utils.WilayahURL("regency/" + id + ".json"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET city detail", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.city_failed").WithCause(err)
 	}
 	defer resp.Body.Close()
 
-	var city City
+	var city utils.City
 	if err := json.NewDecoder(resp.Body).Decode(&city); err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to parse city detail", []string{err.Error()}))
+		return apperror.BadGateway("wilayah.city_failed").WithCause(err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET city detail", city))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "wilayah.city_success"), city))
 }
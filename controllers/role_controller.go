@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"go-crud/apperror"
+	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/models"
+	"go-crud/rbac"
+	"go-crud/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GET /api/roles (permission role:assign)
+func GetRoles(c echo.Context) error {
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), rbac.Roles()))
+}
+
+// UpdateRolesRequest menggantikan seluruh role user
+type UpdateRolesRequest struct {
+	Roles []string `json:"roles" validate:"required"`
+}
+
+// PUT /api/users/:id/roles (permission role:assign)
+// Body: {"roles": ["seller", "buyer"]} — menggantikan seluruh role user
+func UpdateUserRoles(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("common.update_failed").WithDetails([]string{"user.invalid_id"})
+	}
+
+	var req UpdateRolesRequest
+	if err := bindAndValidate(c, "common.update_failed", &req); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	var roles []models.UserRole
+	for _, role := range req.Roles {
+		if !rbac.IsValidRole(role) {
+			return apperror.BadRequest("common.update_failed").WithDetails([]i18n.Message{i18n.M("role.unknown", role)})
+		}
+		if seen[role] {
+			continue
+		}
+		seen[role] = true
+		roles = append(roles, models.UserRole{IDUser: id, Role: role})
+	}
+
+	// jangan sampai admin mencabut role admin dirinya sendiri lalu terkunci
+	if id == authUser.ID && authUser.HasRole(models.RoleAdmin) && !seen[models.RoleAdmin] {
+		return apperror.BadRequest("common.update_failed").WithDetails([]string{"role.self_admin_revoke"})
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, id).Error; err != nil {
+		return apperror.NotFound("common.update_failed").WithDetails([]string{"user.not_found"})
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id_user = ?", user.ID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return nil
+		}
+		return tx.Create(&roles).Error
+	})
+	if err != nil {
+		return apperror.Internal("common.update_failed", err)
+	}
+
+	user.Roles = roles
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.update_success"), dto.UserRoles{
+		ID:          user.ID,
+		Roles:       user.RoleNames(),
+		Permissions: rbac.Permissions(user),
+	}))
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/i18n"
+	"go-crud/oidc"
+	"go-crud/validation"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// serviceError memberi judul message pada error dari services. Error bisnis
+// (apperror.Error) tetap membawa kode dan status-nya, error lain (database
+// dsb) jadi 500 yang detailnya hanya masuk log.
+func serviceError(message string, err error) error {
+	return apperror.Wrap(err, message)
+}
+
+// bindError untuk body / query request yang gagal dibaca. Pesan dari echo
+// dan encoding/json (bahasa Inggris, teknis) tidak ditampilkan; client
+// mendapat key katalog dan error aslinya disimpan sebagai cause.
+func bindError(message string, err error) error {
+	return apperror.BadRequest(message).WithDetails([]i18n.Message{bindErrorDetail(err)}).WithCause(err)
+}
+
+func bindErrorDetail(err error) i18n.Message {
+	// query, path param dan form
+	var be *echo.BindingError
+	if errors.As(err, &be) {
+		return i18n.M("common.invalid_field_type", be.Field)
+	}
+	// body JSON, misal string untuk field int
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) && te.Field != "" {
+		return i18n.M("common.invalid_field_type", te.Field)
+	}
+	var he *echo.HTTPError
+	if errors.As(err, &he) && he.Code == http.StatusUnsupportedMediaType {
+		return i18n.M("error.unsupported_media_type")
+	}
+	return i18n.M("common.invalid_format")
+}
+
+// bindAndValidate membaca body request ke req lalu menjalankan validasi tag
+// `validate`. Error yang dikembalikan langsung di-return oleh handler.
+func bindAndValidate(c echo.Context, message string, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		return bindError(message, err)
+	}
+	if err := c.Validate(req); err != nil {
+		var verrs validation.Errors
+		if errors.As(err, &verrs) {
+			return apperror.Validation(message, verrs)
+		}
+		return apperror.Internal(message, err)
+	}
+	return nil
+}
+
+// sentinelKeys key katalog i18n untuk error sentinel yang pesannya tampil
+// ke client
+var sentinelKeys = []struct {
+	err error
+	key string
+}{
+	{auth.ErrInvalidChallenge, "auth.invalid_challenge"},
+	{auth.ErrInvalidTwoFactorCode, "two_factor.invalid_code"},
+	{auth.ErrTwoFactorAlreadyEnabled, "two_factor.already_enabled"},
+	{auth.ErrTwoFactorNotEnabled, "two_factor.not_enabled"},
+	{auth.ErrTwoFactorNotSetup, "two_factor.not_setup"},
+	{auth.ErrAccountLocked, "auth.account_locked"},
+	{auth.ErrLoginBackoff, "auth.login_backoff"},
+	{auth.ErrInvalidRefreshToken, "auth.invalid_refresh_token"},
+	{auth.ErrRefreshTokenReused, "auth.refresh_token_reused"},
+	{auth.ErrInvalidOneTimeToken, "account.invalid_token"},
+	{auth.ErrSessionNotFound, "session.not_found"},
+	{auth.ErrInvalidOIDCState, "oidc.invalid_state"},
+	{oidc.ErrDisabled, "oidc.disabled"},
+	{oidc.ErrInvalidIDToken, "oidc.invalid_id_token"},
+	{errOIDCEmailMissing, "oidc.email_missing"},
+	{errOIDCEmailTaken, "oidc.email_taken"},
+	{errOIDCDomain, "oidc.domain_not_allowed"},
+	{errIdentityLinked, "oidc.identity_linked"},
+	{errIdentityNotFound, "oidc.identity_not_found"},
+}
+
+// errKey mengembalikan key katalog untuk err. Error yang bukan sentinel
+// yang dikenal tidak ditampilkan isinya.
+func errKey(err error) string {
+	for _, s := range sentinelKeys {
+		if errors.Is(err, s.err) {
+			return s.key
+		}
+	}
+	return "error.internal_error"
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// ===================================================
+// 📱 GET /api/sessions - daftar perangkat yang sedang login
+// ===================================================
+func GetMySessions(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := auth.ListSessions(authUser.ID)
+	if err != nil {
+		return apperror.Internal("session.list_failed", err)
+	}
+
+	currentID, _ := c.Get("sessionID").(uint64)
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewSessions(sessions, currentID)))
+}
+
+// ===================================================
+// 📱 DELETE /api/sessions/:id - keluarkan satu perangkat
+// ===================================================
+func DeleteMySession(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("common.delete_failed").WithDetails([]string{"session.invalid_id"})
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return auth.TerminateSession(tx, authUser.ID, id)
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			return apperror.NotFound("common.delete_failed").WithDetails([]string{errKey(err)})
+		}
+		return apperror.Internal("session.terminate_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "session.terminated"), nil))
+}
@@ -1,206 +1,178 @@
 package controllers
 
 import (
-	"go-crud/config"
+	"go-crud/apperror"
+	"go-crud/dto"
+	"go-crud/i18n"
 	"go-crud/models"
+	"go-crud/services"
 	"go-crud/utils"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
 
-// ========================== RESPONSE STRUCT ==========================
+// ========================== REQUEST STRUCT ==========================
 
-type SafeUserResponse struct {
-	ID    uint64 `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-}
-
-type TokoResponse struct {
-	ID          uint64            `json:"id"`
-	NamaToko    string            `json:"nama_toko"`
-	UrlFoto     string            `json:"url_foto"`
-	IDUser      uint64            `json:"user_id"`
-	User        *SafeUserResponse `json:"user,omitempty"`
+type UpdateTokoRequest struct {
+	NamaToko string `json:"nama_toko" form:"nama_toko" validate:"omitempty,max=255"`
+	UrlFoto  string `json:"url_foto" form:"url_foto" validate:"omitempty,url,max=255"`
 }
 
 // Ambil user dari context JWT
 func getAuthUser(c echo.Context) (*models.User, error) {
+	if err := checkAPIKeyRoute(c); err != nil {
+		return nil, err
+	}
 	authUserRaw := c.Get("authUser")
 	if authUserRaw == nil {
-		return nil, echo.NewHTTPError(http.StatusUnauthorized, "User tidak ditemukan dalam context (token tidak valid?)")
+		return nil, apperror.Unauthorized("common.unauthorized").WithDetails([]string{"common.user_not_in_context"})
 	}
 
 	authUser, ok := authUserRaw.(models.User)
 	if !ok {
-		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Gagal membaca data user dari context")
+		return nil, apperror.Internal("common.user_context_failed", nil)
 	}
 	return &authUser, nil
 }
 
-// ========================== HANDLER ===============================
-
-// GET /api/toko (Admin only)
-func GetAllToko(c echo.Context) error {
-	authUser, err := getAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Failed to GET data", []string{err.Error()}))
+// checkAPIKeyRoute menolak request API key ke route yang tidak memasang
+// RequirePermission atau AllowAPIKey, supaya route baru tertutup untuk API
+// key kecuali dibuka dengan sengaja
+func checkAPIKeyRoute(c echo.Context) error {
+	if c.Get("apiKey") != nil && c.Get("apiKeyAllowed") == nil {
+		return apperror.Forbidden("api_key.not_allowed")
 	}
+	return nil
+}
 
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Failed to GET data", []string{"Hanya admin yang dapat melihat semua toko"}))
-	}
+// ========================== HANDLER ===============================
 
-	var toko []models.Toko
-	if err := config.DB.Preload("User").Find(&toko).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to GET data", []string{err.Error()}))
-	}
+type TokoController struct {
+	toko   services.TokoService
+	limits services.PhotoLimits
+}
 
-	var result []TokoResponse
-	for _, s := range toko {
-		resp := TokoResponse{
-			ID:       s.ID,
-			NamaToko: s.NamaToko,
-			UrlFoto:  *s.UrlFoto,
-			IDUser:   s.IDUser,
-		}
-		if s.User.ID != 0 {
-			resp.User = &SafeUserResponse{
-				ID:    s.User.ID,
-				Name:  s.User.Nama,
-				Email: s.User.Email,
-			}
-		}
-		result = append(result, resp)
-	}
+func NewTokoController(toko services.TokoService, limits services.PhotoLimits) *TokoController {
+	return &TokoController{toko: toko, limits: limits}
+}
 
-	data := map[string]interface{}{
-		"page":  1,
-		"limit": 10,
-		"data":  result,
+// GET /api/toko (permission toko:read)
+func (h *TokoController) GetAllToko(c echo.Context) error {
+	toko, err := h.toko.List(c.Request().Context())
+	if err != nil {
+		return serviceError("common.get_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", data))
+	// daftar toko belum dipotong per halaman, semuanya dikirim di halaman 1
+	data := dto.NewPage(dto.NewTokoList(toko, true), 1, len(toko), int64(len(toko)))
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), data))
 }
 
 // GET /api/toko/my
-func GetMyToko(c echo.Context) error {
+func (h *TokoController) GetMyToko(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Failed to GET data", []string{err.Error()}))
-	}
-
-	var toko models.Toko
-	if err := config.DB.Preload("User").Where("id_user = ?", authUser.ID).First(&toko).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to GET data", []string{"Toko tidak ditemukan"}))
+		return err
 	}
 
-	resp := TokoResponse{
-		ID:       toko.ID,
-		NamaToko: toko.NamaToko,
-		UrlFoto:  *toko.UrlFoto,
-		IDUser:   toko.IDUser,
+	toko, err := h.toko.GetByUser(c.Request().Context(), authUser.ID)
+	if err != nil {
+		return serviceError("common.get_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", resp))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewToko(*toko, false)))
 }
 
 // GET /api/toko/:id
-func GetTokoByID(c echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+func (h *TokoController) GetTokoByID(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to GET data", []string{"ID toko tidak valid"}))
-	}
-
-	var toko models.Toko
-	if err := config.DB.Preload("User").First(&toko, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to GET data", []string{"Toko tidak ditemukan"}))
+		return apperror.BadRequest("common.get_failed").WithDetails([]string{"toko.invalid_id"})
 	}
 
-	resp := TokoResponse{
-		ID:       toko.ID,
-		NamaToko: toko.NamaToko,
-		UrlFoto:  *toko.UrlFoto,
-		IDUser:   toko.IDUser,
+	toko, err := h.toko.Get(c.Request().Context(), id)
+	if err != nil {
+		return serviceError("common.get_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", resp))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewToko(*toko, false)))
 }
 
 // PUT /api/toko/:id
-func UpdateToko(c echo.Context) error {
+func (h *TokoController) UpdateToko(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Failed to UPDATE data", []string{err.Error()}))
+		return err
 	}
 
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to UPDATE data", []string{"ID toko tidak valid"}))
+		return apperror.BadRequest("common.update_failed").WithDetails([]string{"toko.invalid_id"})
 	}
 
-	var toko models.Toko
-	if err := config.DB.First(&toko, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to UPDATE data", []string{"Toko tidak ditemukan"}))
+	var input UpdateTokoRequest
+	if err := bindAndValidate(c, "common.update_failed", &input); err != nil {
+		return err
 	}
 
-	if toko.IDUser != authUser.ID && !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Failed to UPDATE data", []string{"Tidak bisa mengubah toko milik orang lain"}))
+	_, err = h.toko.Update(c.Request().Context(), *authUser, id, services.UpdateTokoInput{
+		NamaToko: input.NamaToko,
+		UrlFoto:  input.UrlFoto,
+	})
+	if err != nil {
+		return serviceError("common.update_failed", err)
 	}
 
-	var input struct {
-		NamaToko    string `json:"nama_toko" form:"nama_toko"`
-		UrlFoto     string `json:"url_foto" form:"url_foto"`
-		Description string `json:"description" form:"description"`
-	}
-	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to UPDATE data", []string{"Input tidak valid"}))
-	}
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.update_success"), i18n.T(c, "toko.updated")))
+}
 
-	updates := map[string]interface{}{}
-	if input.NamaToko != "" {
-		updates["name"] = input.NamaToko
-	}
-	if input.UrlFoto != "" {
-		updates["url_foto"] = input.UrlFoto
-	}
-	if input.Description != "" {
-		updates["description"] = input.Description
-	}
+type UploadTokoPhotoRequest struct {
+	Foto *multipart.FileHeader `json:"foto" form:"foto" doc:"Satu file gambar (JPEG, PNG, WebP, GIF)"`
+}
 
-	if len(updates) == 0 {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to UPDATE data", []string{"Tidak ada data yang diubah"}))
+// PUT /api/toko/:id/photo (multipart/form-data)
+func (h *TokoController) UploadTokoPhoto(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
 	}
 
-	if err := config.DB.Model(&toko).Updates(updates).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to UPDATE data", []string{err.Error()}))
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("photo.upload_failed").WithDetails([]string{"toko.invalid_id"})
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to UPDATE data", "Update toko succeed"))
-}
-
-// DELETE /api/toko/:id (admin only - nonaktifkan toko)
-func DeleteToko(c echo.Context) error {
-	authUser, err := getAuthUser(c)
+	files, cleanup, err := parsePhotoForm(c, h.limits.MaxSize)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return err
+	}
+	defer cleanup()
+	if len(files) != 1 {
+		return apperror.BadRequest("photo.single_file")
 	}
 
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Hanya admin yang dapat menonaktifkan toko"}))
+	toko, err := h.toko.UploadPhoto(c.Request().Context(), *authUser, id, files[0])
+	if err != nil {
+		return serviceError("photo.upload_failed", err)
 	}
 
-	id, err := strconv.Atoi(c.Param("id"))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "toko.photo_updated"), dto.NewToko(*toko, false)))
+}
+
+// DELETE /api/toko/:id (permission toko:delete - nonaktifkan toko)
+func (h *TokoController) DeleteToko(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to DELETE data", []string{"ID toko tidak valid"}))
+		return apperror.BadRequest("common.delete_failed").WithDetails([]string{"toko.invalid_id"})
 	}
 
-	var toko models.Toko
-	if err := config.DB.First(&toko, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to DELETE data", []string{"Toko tidak ditemukan"}))
+	if err := h.toko.Deactivate(c.Request().Context(), id); err != nil {
+		return serviceError("common.delete_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to DELETE data", "Toko berhasil dinonaktifkan"))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.delete_success"), i18n.T(c, "toko.deactivated")))
 }
@@ -1,259 +1,103 @@
 package controllers
 
 import (
-	"go-crud/config"
-	"go-crud/models"
+	"go-crud/apperror"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/services"
 	"go-crud/utils"
 	"net/http"
 	"strconv"
-	"strings"
-	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 // ===================== HANDLERS ======================
 
-// POST /api/transactions
-func CreateTransaction(c echo.Context) error {
-	authUser, err := getAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
-	}
-
-	var req struct {
-		MethodBayar      string `json:"method_bayar"`
-		AlamatPengiriman uint64 `json:"alamat_pengiriman"`
-		DetailTrx        []struct {
-			IDProduk  uint64 `json:"id_produk"`
-			Kuantitas int    `json:"kuantitas"`
-		} `json:"detail_trx"`
-	}
+type TransactionController struct {
+	transactions services.TransactionService
+}
 
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid input", []string{"Gagal membaca input transaksi"}))
-	}
+func NewTransactionController(transactions services.TransactionService) *TransactionController {
+	return &TransactionController{transactions: transactions}
+}
 
-	if len(req.DetailTrx) == 0 {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid input", []string{"Tidak ada produk yang dibeli"}))
-	}
+// CreateTransactionRequest body POST /api/transactions
+type CreateTransactionRequest struct {
+	MethodBayar      string                  `json:"method_bayar" validate:"required,max=50"`
+	AlamatPengiriman uint64                  `json:"alamat_pengiriman" validate:"required"`
+	DetailTrx        []TransactionItemRequest `json:"detail_trx" validate:"required,min=1,dive"`
+}
 
-	// Mulai transaksi
-	tx := config.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+type TransactionItemRequest struct {
+	IDProduk  uint64  `json:"id_produk" validate:"required"`
+	IDVarian  *uint64 `json:"id_varian" validate:"omitnil,min=1" doc:"Wajib untuk produk bervarian"`
+	Kuantitas int     `json:"kuantitas" validate:"required,min=1,max=1000"`
+}
 
-	trx := models.Trx{
-		IDUser:           authUser.ID,
-		AlamatPengiriman: &req.AlamatPengiriman,
-		KodeInvoice:      "INV-" + strconv.FormatInt(time.Now().Unix(), 10),
-		MethodBayar:      &req.MethodBayar,
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
+// POST /api/transactions
+func (h *TransactionController) CreateTransaction(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
 	}
 
-	if err := tx.Create(&trx).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal membuat transaksi", []string{err.Error()}))
+	var req CreateTransactionRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
 	}
 
-	totalHarga := 0
-
-	for _, item := range req.DetailTrx {
-		if item.IDProduk == 0 || item.Kuantitas <= 0 {
-			tx.Rollback()
-			return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid input", []string{"Produk tidak valid"}))
-		}
-
-		var product models.Produk
-		if err := tx.First(&product, item.IDProduk).Error; err != nil {
-			tx.Rollback()
-			return c.JSON(http.StatusNotFound, utils.ErrorResponse("Produk tidak ditemukan", []string{err.Error()}))
-		}
-
-		// Cek stok
-		if product.Stok < item.Kuantitas {
-			tx.Rollback()
-			return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Stok tidak mencukupi", []string{product.NamaProduk}))
-		}
-
-		// Kurangi stok
-		product.Stok -= item.Kuantitas
-		if err := tx.Save(&product).Error; err != nil {
-			tx.Rollback()
-			return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal mengurangi stok", []string{err.Error()}))
-		}
-
-		// Hitung subtotal
-		subtotal := item.Kuantitas * product.HargaKonsumen
-		totalHarga += subtotal
-
-		// Simpan log produk
-		log := models.LogProduk{
-			IDProduk:      product.ID,
-			NamaProduk:    product.NamaProduk,
-			Slug:          strings.ToLower(strings.ReplaceAll(product.NamaProduk, " ", "-")),
-			HargaReseller: product.HargaReseller,
-			HargaKonsumen: product.HargaKonsumen,
-			Deskripsi:     product.Deskripsi,
-			IDToko:        product.IDToko,
-			IDCategory:    product.IDCategory,
-			CreatedAt:     time.Now(),
-			UpdatedAt:     time.Now(),
-		}
-		if err := tx.Create(&log).Error; err != nil {
-			tx.Rollback()
-			return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal menyimpan log produk", []string{err.Error()}))
-		}
-
-		// Simpan detail transaksi
-		detail := models.DetailTrx{
-			IDTrx:       trx.ID,
-			IDLogProduk: log.ID,
-			IDToko:      product.IDToko,
-			Kuantitas:   item.Kuantitas,
-			HargaTotal:  subtotal,
-		}
-		if err := tx.Create(&detail).Error; err != nil {
-			tx.Rollback()
-			return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal menyimpan detail transaksi", []string{err.Error()}))
-		}
+	input := services.CreateTransactionInput{
+		MethodBayar:      req.MethodBayar,
+		AlamatPengiriman: req.AlamatPengiriman,
 	}
-
-	// Simpan total harga ke transaksi
-	trx.HargaTotal = totalHarga
-	if err := tx.Save(&trx).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal menyimpan total harga", []string{err.Error()}))
+	for _, item := range req.DetailTrx {
+		input.Items = append(input.Items, services.TransactionItem{
+			IDProduk:  item.IDProduk,
+			IDVarian:  item.IDVarian,
+			Kuantitas: item.Kuantitas,
+		})
 	}
 
-	// Commit transaksi
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Commit gagal", []string{err.Error()}))
+	trx, err := h.transactions.Create(c.Request().Context(), *authUser, input)
+	if err != nil {
+		return serviceError("transaction.create_failed", err)
 	}
 
-	return c.JSON(http.StatusCreated, utils.SuccessResponse("Transaksi berhasil dibuat", map[string]interface{}{
-		"id":           trx.ID,
-		"kode_invoice": trx.KodeInvoice,
-		"harga_total":  trx.HargaTotal,
-		"method_bayar": req.MethodBayar,
+	return c.JSON(http.StatusCreated, utils.SuccessResponse(i18n.T(c, "transaction.created"), dto.TransactionCreated{
+		ID:          trx.ID,
+		KodeInvoice: trx.KodeInvoice,
+		HargaTotal:  trx.HargaTotal,
+		MethodBayar: req.MethodBayar,
 	}))
 }
 
-// GET /api/transactions (Admin only)
-func GetAllTransactions(c echo.Context) error {
-	authUser, err := getAuthUser(c)
+// GET /api/transactions (permission transaction:read)
+func (h *TransactionController) GetAllTransactions(c echo.Context) error {
+	trans, err := h.transactions.List(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return serviceError("transaction.get_failed", err)
 	}
 
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Hanya admin yang dapat melihat semua transaksi"}))
-	}
-
-	var trans []models.Trx
-	if err := config.DB.Preload("DetailTrx.LogProduk").
-		Order("created_at desc").
-		Find(&trans).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal mengambil data transaksi", []string{err.Error()}))
-	}
-
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", trans))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewTransactions(trans)))
 }
 
 // GET /api/transactions/:id
-func GetTransactionByID(c echo.Context) error {
+func (h *TransactionController) GetTransactionByID(c echo.Context) error {
 	authUser, err := getAuthUser(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", []string{err.Error()}))
+		return err
 	}
 
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID", []string{"ID transaksi tidak valid"}))
-	}
-
-	var trx models.Trx
-	if err := config.DB.
-		Preload("Alamat").
-		Preload("DetailTrx.LogProduk.Toko").
-		Preload("DetailTrx.LogProduk.Category").
-		Preload("DetailTrx.LogProduk.Photos").
-		First(&trx, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("Transaksi tidak ditemukan", []string{err.Error()}))
+		return apperror.BadRequest("common.invalid_id").WithDetails([]string{"transaction.invalid_id"})
 	}
 
-	// hanya pemilik transaksi atau admin yang boleh lihat
-	if trx.IDUser != authUser.ID && !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", []string{"Anda tidak memiliki akses ke transaksi ini"}))
-	}
-
-	// ===============================
-	// ✅ bentuk ulang sesuai contoh
-	// ===============================
-
-	response := map[string]interface{}{
-		"id":           trx.ID,
-		"harga_total":  trx.HargaTotal,
-		"kode_invoice": trx.KodeInvoice,
-		"method_bayar": trx.MethodBayar,
-		"alamat_kirim": map[string]interface{}{
-			"id":             trx.Alamat.ID,
-			"judul_alamat":   trx.Alamat.JudulAlamat,
-			"nama_penerima":  trx.Alamat.NamaPenerima,
-			"no_telp":        trx.Alamat.NoTelp,
-			"detail_alamat":  trx.Alamat.DetailAlamat,
-		},
-	}
-
-	var details []map[string]interface{}
-	for _, d := range trx.DetailTrx {
-		p := d.LogProduk
-
-		// ambil semua foto produk
-		var photos []map[string]interface{}
-		for _, f := range p.Photos {
-			photos = append(photos, map[string]interface{}{
-				"id":         f.ID,
-				"product_id": f.IDProduk,
-				"url":        f.URL,
-			})
-		}
-
-		details = append(details, map[string]interface{}{
-			"product": map[string]interface{}{
-				"id":              p.IDProduk,
-				"nama_produk":     p.NamaProduk,
-				"slug":            p.Slug,
-				"harga_reseler":   p.HargaReseller,
-				"harga_konsumen":  p.HargaKonsumen,
-				"deskripsi":       p.Deskripsi,
-				"toko": map[string]interface{}{
-					"nama_toko": p.Toko.NamaToko,
-					"url_foto":  p.Toko.UrlFoto,
-				},
-				"category": map[string]interface{}{
-					"id":            p.Category.ID,
-					"nama_category": p.Category.NamaCategory,
-				},
-				"photos": photos,
-			},
-			"toko": map[string]interface{}{
-				"id":         p.Toko.ID,
-				"nama_toko":  p.Toko.NamaToko,
-				"url_foto":   p.Toko.UrlFoto,
-			},
-			"kuantitas":   d.Kuantitas,
-			"harga_total": d.HargaTotal,
-		})
+	trx, err := h.transactions.Get(c.Request().Context(), *authUser, id)
+	if err != nil {
+		return serviceError("transaction.get_failed", err)
 	}
 
-	response["detail_trx"] = details
-
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET data", response))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewTransaction(*trx)))
 }
-
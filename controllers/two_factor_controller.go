@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/config"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// twoFactorError memetakan error 2FA dari package auth ke respons HTTP
+func twoFactorError(c echo.Context, err error, fallback string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidTwoFactorCode):
+		return apperror.Unauthorized(errKey(err)).WithDetails([]string{"invalid_2fa_code"})
+	case errors.Is(err, errTwoFactorWrongPassword):
+		return apperror.Unauthorized("two_factor.wrong_password").WithDetails([]string{"invalid_password"})
+	case errors.Is(err, auth.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, auth.ErrTwoFactorNotEnabled),
+		errors.Is(err, auth.ErrTwoFactorNotSetup):
+		return apperror.BadRequest(errKey(err))
+	default:
+		return apperror.Internal(fallback, err)
+	}
+}
+
+// errTwoFactorWrongPassword kata sandi salah saat menonaktifkan 2FA
+var errTwoFactorWrongPassword = errors.New("kata sandi salah")
+
+// verifyTwoFactorAction menjalankan fn (verifikasi kode + aksinya) dalam satu
+// transaksi untuk endpoint yang hanya butuh access token. Kode atau kata
+// sandi yang salah dihitung di counter backoff / lockout yang sama dengan
+// /login/2fa, supaya access token curian tidak bisa dipakai menebak kode.
+func verifyTwoFactorAction(c echo.Context, email, fallback string, fn func(tx *gorm.DB) error) error {
+	ctx := c.Request().Context()
+	if retryAfter, err := auth.CheckLoginAllowed(ctx, email); err != nil {
+		return loginThrottled(c, retryAfter, err)
+	}
+
+	err := config.DB.Transaction(fn)
+	if errors.Is(err, auth.ErrInvalidTwoFactorCode) || errors.Is(err, errTwoFactorWrongPassword) {
+		if err := auth.RecordLoginFailure(ctx, email); err != nil {
+			return apperror.Internal(fallback, err)
+		}
+	}
+	if err != nil {
+		return twoFactorError(c, err, fallback)
+	}
+	if err := auth.ResetLoginFailures(ctx, email); err != nil {
+		return apperror.Internal(fallback, err)
+	}
+	return nil
+}
+
+// ===================================================
+// 🔹 GET /api/2fa - status 2FA user login
+// ===================================================
+func GetTwoFactorStatus(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	data := dto.TwoFactorStatus{
+		Enabled:  authUser.TwoFactorEnabled(),
+		Required: auth.RequiresTwoFactor(*authUser),
+	}
+	if authUser.TwoFactorEnabled() {
+		remaining, err := auth.RemainingRecoveryCodes(authUser.ID)
+		if err != nil {
+			return apperror.Internal("two_factor.status_failed", err)
+		}
+		data.EnabledAt = authUser.TwoFactor.EnabledAt
+		data.RecoveryCodesRemaining = &remaining
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "two_factor.status"), data))
+}
+
+// ===================================================
+// 🔹 POST /api/2fa/setup - buat secret & URI QR code
+// ===================================================
+func SetupTwoFactor(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	secret, uri, err := auth.BeginTOTPSetup(config.DB, *authUser)
+	if err != nil {
+		return twoFactorError(c, err, "two_factor.setup_failed")
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "two_factor.setup_success"), dto.TwoFactorSetup{
+		Secret:     secret,
+		OtpauthURI: uri,
+	}))
+}
+
+// ===================================================
+// 🔹 POST /api/2fa/enable - konfirmasi kode pertama
+// ===================================================
+
+// TwoFactorCodeRequest dipakai /api/2fa/enable dan /api/2fa/recovery-codes
+type TwoFactorCodeRequest struct {
+	// kode TOTP 6 digit (atau recovery code untuk /recovery-codes)
+	Code string `json:"code" validate:"required"`
+}
+
+func EnableTwoFactor(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req TwoFactorCodeRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+
+	var codes []string
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		codes, err = auth.EnableTOTP(tx, authUser.ID, req.Code)
+		return err
+	})
+	if err != nil {
+		return twoFactorError(c, err, "two_factor.enable_failed")
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "two_factor.enabled"), dto.RecoveryCodes{RecoveryCodes: codes}))
+}
+
+// ===================================================
+// 🔹 POST /api/2fa/disable - butuh kata sandi + kode
+// ===================================================
+type DisableTwoFactorRequest struct {
+	KataSandi string `json:"kata_sandi" validate:"required"`
+	Code      string `json:"code" validate:"required"`
+}
+
+func DisableTwoFactor(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	if auth.RequiresTwoFactor(*authUser) {
+		return apperror.Forbidden("two_factor.required_for_role").WithDetails([]string{"two_factor_required"})
+	}
+
+	var req DisableTwoFactorRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+	err = verifyTwoFactorAction(c, authUser.Email, "two_factor.disable_failed", func(tx *gorm.DB) error {
+		if err := bcrypt.CompareHashAndPassword([]byte(authUser.KataSandi), []byte(req.KataSandi)); err != nil {
+			return errTwoFactorWrongPassword
+		}
+		if err := auth.VerifySecondFactor(tx, authUser.ID, req.Code); err != nil {
+			return err
+		}
+		return auth.DisableTOTP(tx, authUser.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "two_factor.disabled"), nil))
+}
+
+// ===================================================
+// 🔹 POST /api/2fa/recovery-codes - buat ulang recovery code
+// ===================================================
+func RegenerateRecoveryCodes(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req TwoFactorCodeRequest
+	if err := bindAndValidate(c, "common.invalid_request", &req); err != nil {
+		return err
+	}
+
+	var codes []string
+	err = verifyTwoFactorAction(c, authUser.Email, "two_factor.recovery_failed", func(tx *gorm.DB) error {
+		if err := auth.VerifySecondFactor(tx, authUser.ID, req.Code); err != nil {
+			return err
+		}
+		codes, err = auth.RegenerateRecoveryCodes(tx, authUser.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "two_factor.recovery_regenerated"), dto.RecoveryCodes{RecoveryCodes: codes}))
+}
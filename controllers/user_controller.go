@@ -1,209 +1,179 @@
 package controllers
 
 import (
-	"go-crud/config"
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/dto"
+	"go-crud/i18n"
 	"go-crud/models"
+	"go-crud/services"
 	"go-crud/utils"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
-	"golang.org/x/crypto/bcrypt"
 )
 
-// ===================================================
-// 🔹 GET /users (Hanya Admin)
-// ===================================================
-func GetAllUsers(c echo.Context) error {
-	authUser, ok := c.Get("authUser").(models.User)
-	if !ok {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User tidak ditemukan dalam context", []string{"unauthorized"}))
-	}
+type UserController struct {
+	users services.UserService
+}
 
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Akses ditolak. Hanya admin yang dapat melihat semua user.", []string{"forbidden"}))
-	}
+func NewUserController(users services.UserService) *UserController {
+	return &UserController{users: users}
+}
 
-	var users []models.User
-	if err := config.DB.Find(&users).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal mengambil data user", []string{err.Error()}))
+// userWithWilayah menyertakan nama provinsi & kota dari API wilayah
+func userWithWilayah(user models.User) dto.UserWithWilayah {
+	var provinsi *utils.Province
+	var kota *utils.City
+	if user.IDProvinsi != nil {
+		provinsi, _ = utils.GetProvinceByID(*user.IDProvinsi)
 	}
-
-	var enrichedUsers []map[string]interface{}
-	for _, user := range users {
-		user.KataSandi = ""
-
-		var provinsi, kota interface{}
-		if user.IDProvinsi != nil {
-			provinsi, _ = utils.GetProvinceByID(*user.IDProvinsi)
-		}
-		if user.IDKota != nil {
-			kota, _ = utils.GetCityByID(*user.IDKota)
-		}
-
-		enrichedUsers = append(enrichedUsers, map[string]interface{}{
-			"user":        user,
-			"id_provinsi": provinsi,
-			"id_kota":     kota,
-		})
+	if user.IDKota != nil {
+		kota, _ = utils.GetCityByID(*user.IDKota)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET all users", enrichedUsers))
+	return dto.NewUserWithWilayah(user, provinsi, kota)
 }
 
 // ===================================================
-// 🔹 GET /users/:id (User Lihat Profil Sendiri)
+// 🔹 GET /users (permission user:read)
 // ===================================================
-func GetUserByID(c echo.Context) error {
-	idParam := c.Param("id")
-
-	authUser, ok := c.Get("authUser").(models.User)
-	if !ok {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User tidak ditemukan dalam context", []string{"unauthorized"}))
-	}
-
-	var user models.User
-	if err := config.DB.First(&user, "id = ?", idParam).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("User tidak ditemukan", []string{"user_not_found"}))
+func (h *UserController) GetAllUsers(c echo.Context) error {
+	users, err := h.users.List(c.Request().Context())
+	if err != nil {
+		return serviceError("user.get_failed", err)
 	}
 
-	if !authUser.IsAdmin && authUser.ID != user.ID {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Tidak boleh melihat data user lain", []string{"forbidden"}))
+	enrichedUsers := make([]dto.UserWithWilayah, 0, len(users))
+	for _, user := range users {
+		enrichedUsers = append(enrichedUsers, userWithWilayah(user))
 	}
 
-	user.KataSandi = ""
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "user.list_success"), enrichedUsers))
+}
 
-	var provinsi, kota interface{}
-	if user.IDProvinsi != nil {
-		provinsi, _ = utils.GetProvinceByID(*user.IDProvinsi)
+// ===================================================
+// 🔹 GET /users/:id (User Lihat Profil Sendiri)
+// ===================================================
+func (h *UserController) GetUserByID(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
 	}
-	if user.IDKota != nil {
-		kota, _ = utils.GetCityByID(*user.IDKota)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.NotFound("user.not_found").WithDetails([]string{"user_not_found"})
 	}
 
-	data := map[string]interface{}{
-		"user":        user,
-		"id_provinsi": provinsi,
-		"id_kota":     kota,
+	user, err := h.users.Get(c.Request().Context(), *authUser, id)
+	if err != nil {
+		return serviceError("user.get_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("Succeed to GET user", data))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "user.get_success"), userWithWilayah(*user)))
 }
 
 // ===================================================
 // 🔹 PUT /users/:id (Update Data User)
 // ===================================================
-func UpdateUser(c echo.Context) error {
-	id := c.Param("id")
 
-	authUser, ok := c.Get("authUser").(models.User)
-	if !ok {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User tidak ditemukan dalam context", []string{"unauthorized"}))
-	}
-
-	var user models.User
-	if err := config.DB.First(&user, id).Error; err != nil {
-		return c.JSON(http.StatusNotFound, utils.ErrorResponse("User tidak ditemukan", []string{"user_not_found"}))
-	}
+// UpdateUserRequest semua field opsional, hanya yang dikirim yang diubah
+type UpdateUserRequest struct {
+	Nama         *string    `json:"nama" validate:"omitnil,min=1,max=100"`
+	KataSandi    *string    `json:"kata_sandi" validate:"omitnil,min=8,max=72"`
+	NoTelp       *string    `json:"no_telp" validate:"omitnil,phone_id"`
+	TanggalLahir *time.Time `json:"tanggal_lahir"`
+	JenisKelamin *string    `json:"jenis_kelamin" validate:"omitnil,oneof=Laki-laki Perempuan"`
+	Tentang      *string    `json:"tentang" validate:"omitnil,max=5000"`
+	Pekerjaan    *string    `json:"pekerjaan" validate:"omitnil,max=100"`
+	Email        *string    `json:"email" validate:"omitnil,email,max=100"`
+	IDProvinsi   *string    `json:"id_provinsi" validate:"omitnil,provinsi_id"`
+	IDKota       *string    `json:"id_kota" validate:"omitnil,kota_id=IDProvinsi"`
+	Bahasa       *string    `json:"bahasa" validate:"omitnil,oneof=id en"`
+}
 
-	if !authUser.IsAdmin && authUser.ID != user.ID {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Tidak bisa mengedit user lain", []string{"forbidden"}))
+func (h *UserController) UpdateUser(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
 	}
 
-	type UpdateUserRequest struct {
-		Nama          *string    `json:"nama"`
-		KataSandi     *string    `json:"kata_sandi"`
-		NoTelp        *string    `json:"no_telp"`
-		TanggalLahir  *time.Time `json:"tanggal_lahir"`
-		JenisKelamin  *string    `json:"jenis_kelamin"`
-		Tentang       *string    `json:"tentang"`
-		Pekerjaan     *string    `json:"pekerjaan"`
-		Email         *string    `json:"email"`
-		IDProvinsi    *string    `json:"id_provinsi"`
-		IDKota        *string    `json:"id_kota"`
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.NotFound("user.not_found").WithDetails([]string{"user_not_found"})
 	}
 
 	var req UpdateUserRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, utils.ErrorResponse("Input tidak valid", []string{err.Error()}))
-	}
-
-	if req.Nama != nil {
-		user.Nama = *req.Nama
-	}
-	if req.KataSandi != nil && *req.KataSandi != "" {
-		hashed, err := bcrypt.GenerateFromPassword([]byte(*req.KataSandi), bcrypt.DefaultCost)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal hash password", []string{err.Error()}))
-		}
-		user.KataSandi = string(hashed)
-	}
-	if req.NoTelp != nil {
-		user.NoTelp = req.NoTelp
-	}
-	if req.TanggalLahir != nil {
-		user.TanggalLahir = req.TanggalLahir
-	}
-	if req.JenisKelamin != nil {
-		user.JenisKelamin = req.JenisKelamin
-	}
-	if req.Tentang != nil {
-		user.Tentang = req.Tentang
-	}
-	if req.Pekerjaan != nil {
-		user.Pekerjaan = req.Pekerjaan
-	}
-	if req.Email != nil {
-		user.Email = *req.Email
-	}
-	if req.IDProvinsi != nil {
-		user.IDProvinsi = req.IDProvinsi
-	}
-	if req.IDKota != nil {
-		user.IDKota = req.IDKota
-	}
-
-	if err := config.DB.Save(&user).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal menyimpan perubahan", []string{err.Error()}))
-	}
-
-	user.KataSandi = ""
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
+	}
+
+	user, err := h.users.Update(c.Request().Context(), *authUser, id, services.UpdateUserInput{
+		Nama:         req.Nama,
+		KataSandi:    req.KataSandi,
+		NoTelp:       req.NoTelp,
+		TanggalLahir: req.TanggalLahir,
+		JenisKelamin: req.JenisKelamin,
+		Tentang:      req.Tentang,
+		Pekerjaan:    req.Pekerjaan,
+		Email:        req.Email,
+		IDProvinsi:   req.IDProvinsi,
+		IDKota:       req.IDKota,
+		Bahasa:       req.Bahasa,
+	})
+	if err != nil {
+		return serviceError("user.update_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "user.updated"), userWithWilayah(*user)))
+}
 
-	var provinsi, kota interface{}
-	if user.IDProvinsi != nil {
-		provinsi, _ = utils.GetProvinceByID(*user.IDProvinsi)
-	}
-	if user.IDKota != nil {
-		kota, _ = utils.GetCityByID(*user.IDKota)
+// ===================================================
+// 🔹 DELETE /users/:id (permission user:delete)
+// ===================================================
+func (h *UserController) DeleteUser(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("user.delete_failed").WithDetails([]string{"user.invalid_id"})
 	}
 
-	data := map[string]interface{}{
-		"user":        user,
-		"id_provinsi": provinsi,
-		"id_kota":     kota,
+	if err := h.users.Delete(c.Request().Context(), id); err != nil {
+		return serviceError("user.delete_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("User berhasil diperbarui", data))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "user.deleted"), nil))
 }
 
 // ===================================================
-// 🔹 DELETE /users/:id (Hanya Admin)
+// 🔓 POST /users/:id/unlock (permission user:write)
 // ===================================================
-func DeleteUser(c echo.Context) error {
-	id := c.Param("id")
-
-	authUser, ok := c.Get("authUser").(models.User)
-	if !ok {
-		return c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User tidak ditemukan dalam context", []string{"unauthorized"}))
+func (h *UserController) UnlockUser(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.NotFound("user.not_found").WithDetails([]string{"user_not_found"})
 	}
 
-	if !authUser.IsAdmin {
-		return c.JSON(http.StatusForbidden, utils.ErrorResponse("Hanya admin yang bisa menghapus user", []string{"forbidden"}))
+	ctx := c.Request().Context()
+	user, err := h.users.FindByID(ctx, id)
+	if err != nil {
+		return serviceError("user.not_found", err)
 	}
 
-	if err := config.DB.Delete(&models.User{}, id).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal menghapus user", []string{err.Error()}))
+	failures, lockedFor, err := auth.LockStatus(ctx, user.Email)
+	if err != nil {
+		return apperror.Internal("user.lock_status_failed", err)
+	}
+	if err := auth.UnlockAccount(ctx, user.Email); err != nil {
+		return apperror.Internal("user.unlock_failed", err)
 	}
 
-	return c.JSON(http.StatusOK, utils.SuccessResponse("User berhasil dihapus", nil))
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "user.unlocked"), dto.UnlockUser{
+		UserID:            user.ID,
+		WasLocked:         lockedFor > 0,
+		LockedSecondsLeft: int(lockedFor.Seconds()),
+		FailedAttempts:    failures,
+	}))
 }
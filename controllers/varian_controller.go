@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"go-crud/apperror"
+	"go-crud/dto"
+	"go-crud/i18n"
+	"go-crud/services"
+	"go-crud/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type VarianController struct {
+	varian services.VarianService
+}
+
+func NewVarianController(varian services.VarianService) *VarianController {
+	return &VarianController{varian: varian}
+}
+
+func parseVarianID(c echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(c.Param("id_varian"), 10, 64)
+	if err != nil {
+		return 0, apperror.BadRequest("variant.invalid_id").WithDetails([]string{"variant.invalid_id"})
+	}
+	return id, nil
+}
+
+// GET /api/products/:id/variants
+func (h *VarianController) GetVariants(c echo.Context) error {
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.varian.Get(c.Request().Context(), idProduk)
+	if err != nil {
+		return serviceError("common.get_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "common.get_success"), dto.NewProdukVarian(*result)))
+}
+
+type ReplaceVariantsRequest struct {
+	Opsi   []OpsiRequest   `json:"opsi" validate:"max=3,dive" doc:"Jenis opsi, misal Ukuran dan Warna; kosong bersama varian untuk menghapus semua varian"`
+	Varian []VarianRequest `json:"varian" validate:"max=100,dive" doc:"Kombinasi yang dijual, tidak harus semua kombinasi opsi"`
+}
+
+type OpsiRequest struct {
+	Nama  string   `json:"nama" validate:"required,max=50"`
+	Nilai []string `json:"nilai" validate:"required,min=1,max=50,dive,required,max=50"`
+}
+
+type VarianRequest struct {
+	Opsi          map[string]string `json:"opsi" validate:"required" doc:"Nama opsi -> nilai, satu nilai untuk setiap opsi, misal {\"Ukuran\": \"XL\"}"`
+	SKU           string            `json:"sku" validate:"required,max=64"`
+	HargaReseller int               `json:"harga_reseller" validate:"harga,ltefield=HargaKonsumen"`
+	HargaKonsumen int               `json:"harga_konsumen" validate:"required,harga"`
+	Stok          int               `json:"stok" validate:"min=0"`
+}
+
+// PUT /api/products/:id/variants (pemilik toko)
+func (h *VarianController) ReplaceVariants(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+
+	var req ReplaceVariantsRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
+	}
+
+	input := services.ReplaceVarianInput{}
+	for _, o := range req.Opsi {
+		input.Opsi = append(input.Opsi, services.OpsiInput{Nama: o.Nama, Nilai: o.Nilai})
+	}
+	for _, v := range req.Varian {
+		input.Varian = append(input.Varian, services.VarianInput{
+			Opsi:          v.Opsi,
+			SKU:           v.SKU,
+			HargaReseller: v.HargaReseller,
+			HargaKonsumen: v.HargaKonsumen,
+			Stok:          v.Stok,
+		})
+	}
+
+	result, err := h.varian.Replace(c.Request().Context(), *authUser, idProduk, input)
+	if err != nil {
+		return serviceError("variant.save_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "variant.saved"), dto.NewProdukVarian(*result)))
+}
+
+// UpdateVariantRequest field yang tidak dikirim tidak diubah
+type UpdateVariantRequest struct {
+	SKU           *string   `json:"sku" validate:"omitnil,min=1,max=64"`
+	HargaKonsumen *int      `json:"harga_konsumen" validate:"omitnil,harga"`
+	HargaReseller *int      `json:"harga_reseller" validate:"omitnil,harga"`
+	Stok          *int      `json:"stok" validate:"omitnil,min=0"`
+	IDFoto        *[]uint64 `json:"id_foto" validate:"omitnil,max=20,dive,min=1" doc:"Foto produk khusus varian ini; [] mengembalikan semua fotonya jadi foto umum"`
+}
+
+// PUT /api/products/:id/variants/:id_varian (pemilik toko)
+func (h *VarianController) UpdateVariant(c echo.Context) error {
+	authUser, err := getAuthUser(c)
+	if err != nil {
+		return err
+	}
+	idProduk, err := parseProdukID(c)
+	if err != nil {
+		return err
+	}
+	id, err := parseVarianID(c)
+	if err != nil {
+		return err
+	}
+
+	var req UpdateVariantRequest
+	if err := bindAndValidate(c, "common.invalid_input", &req); err != nil {
+		return err
+	}
+
+	result, err := h.varian.Update(c.Request().Context(), *authUser, idProduk, id, services.UpdateVarianInput{
+		SKU:           req.SKU,
+		HargaReseller: req.HargaReseller,
+		HargaKonsumen: req.HargaKonsumen,
+		Stok:          req.Stok,
+		IDFoto:        req.IDFoto,
+	})
+	if err != nil {
+		return serviceError("common.update_failed", err)
+	}
+
+	return c.JSON(http.StatusOK, utils.SuccessResponse(i18n.T(c, "variant.updated"), dto.NewProdukVarian(*result)))
+}
@@ -0,0 +1,116 @@
+package dto
+
+import (
+	"go-crud/models"
+	"time"
+)
+
+// ================================
+// 🔹 SESSION, API KEY, AKUN SSO
+// ================================
+
+type Session struct {
+	ID         uint64    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// true = session yang sedang dipakai request ini
+	Current bool `json:"current"`
+}
+
+func NewSessions(sessions []models.Session, currentID uint64) []Session {
+	return mapList(sessions, func(s models.Session) Session {
+		return Session{
+			ID:         s.ID,
+			Device:     s.Device,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.ID == currentID,
+		}
+	})
+}
+
+// APIKey data API key tanpa hash; key asli tidak pernah ikut
+type APIKey struct {
+	ID          uint64     `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	IDUser      uint64     `json:"id_user"`
+	IDToko      *uint64    `json:"id_toko"`
+	Permissions []string   `json:"permissions"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  *string    `json:"last_used_ip"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewAPIKey(key models.APIKey) APIKey {
+	return APIKey{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		IDUser:      key.IDUser,
+		IDToko:      key.IDToko,
+		Permissions: key.PermissionList(),
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		LastUsedIP:  key.LastUsedIP,
+		RevokedAt:   key.RevokedAt,
+		CreatedAt:   key.CreatedAt,
+	}
+}
+
+func NewAPIKeys(keys []models.APIKey) []APIKey {
+	return mapList(keys, NewAPIKey)
+}
+
+// CreatedAPIKey sama dengan APIKey ditambah key asli, hanya dikirim sekali
+// saat key dibuat
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Identity akun SSO yang tertaut ke user
+type Identity struct {
+	ID          uint64     `json:"id"`
+	Issuer      string     `json:"issuer"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewIdentity(i models.UserIdentity) Identity {
+	return Identity{
+		ID:          i.ID,
+		Issuer:      i.Issuer,
+		Subject:     i.Subject,
+		Email:       i.Email,
+		LastLoginAt: i.LastLoginAt,
+		CreatedAt:   i.CreatedAt,
+	}
+}
+
+func NewIdentities(identities []models.UserIdentity) []Identity {
+	return mapList(identities, NewIdentity)
+}
+
+// OIDCLinkPending state milik alur penautan akun: code + state harus dikirim
+// ulang ke POST /api/identities/callback dengan token user
+type OIDCLinkPending struct {
+	LinkPending bool   `json:"link_pending"`
+	Code        string `json:"code"`
+	State       string `json:"state"`
+}
+
+type AuthorizationURL struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"go-crud/models"
+	"go-crud/utils"
+	"time"
+)
+
+// ================================
+// 🔹 ALAMAT
+// ================================
+
+type Alamat struct {
+	ID           uint64    `json:"id"`
+	JudulAlamat  string    `json:"judul_alamat"`
+	NamaPenerima string    `json:"nama_penerima"`
+	NoTelp       string    `json:"no_telp"`
+	DetailAlamat string    `json:"detail_alamat"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func NewAlamat(a models.Alamat) Alamat {
+	return Alamat{
+		ID:           a.ID,
+		JudulAlamat:  a.JudulAlamat,
+		NamaPenerima: a.NamaPenerima,
+		NoTelp:       a.NoTelp,
+		DetailAlamat: a.DetailAlamat,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+func NewAlamatList(alamat []models.Alamat) []Alamat {
+	return mapList(alamat, NewAlamat)
+}
+
+// AlamatDetail alamat beserta provinsi & kota pemiliknya dari API wilayah
+type AlamatDetail struct {
+	Alamat   Alamat          `json:"alamat"`
+	Provinsi *utils.Province `json:"provinsi"`
+	Kota     *utils.City     `json:"kota"`
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"go-crud/auth"
+	"go-crud/models"
+	"time"
+)
+
+// ================================
+// 🔹 AUTH
+// ================================
+
+// Register tanpa id user: respons untuk email yang sudah terdaftar harus
+// sama persis
+type Register struct {
+	Email string `json:"email"`
+}
+
+// Login token + data user. Kalau 2FA aktif yang dikirim TwoFactorChallenge,
+// token baru didapat dari /login/2fa.
+type Login struct {
+	ID               uint64     `json:"id"`
+	Nama             string     `json:"nama"`
+	NoTelp           *string    `json:"no_telp"`
+	TanggalLahir     *time.Time `json:"tanggal_lahir"`
+	Tentang          *string    `json:"tentang"`
+	Pekerjaan        *string    `json:"pekerjaan"`
+	Email            string     `json:"email"`
+	IDProvinsi       *string    `json:"id_provinsi"`
+	IDKota           *string    `json:"id_kota"`
+	IsAdmin          bool       `json:"is_admin"`
+	Roles            []string   `json:"roles"`
+	Token            string     `json:"token"`
+	RefreshToken     string     `json:"refresh_token"`
+	ExpiresIn        int64      `json:"expires_in"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	// true = akun wajib 2FA tapi belum setup, API hanya bisa dipakai untuk /api/2fa
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required"`
+}
+
+// NewLogin user harus sudah di-Preload Roles dan TwoFactor
+func NewLogin(u models.User, tokens auth.TokenPair) Login {
+	return Login{
+		ID:                     u.ID,
+		Nama:                   u.Nama,
+		NoTelp:                 u.NoTelp,
+		TanggalLahir:           u.TanggalLahir,
+		Tentang:                u.Tentang,
+		Pekerjaan:              u.Pekerjaan,
+		Email:                  u.Email,
+		IDProvinsi:             u.IDProvinsi,
+		IDKota:                 u.IDKota,
+		IsAdmin:                u.HasRole(models.RoleAdmin),
+		Roles:                  u.RoleNames(),
+		Token:                  tokens.AccessToken,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresIn:              tokens.ExpiresIn,
+		TwoFactorEnabled:       u.TwoFactorEnabled(),
+		TwoFactorSetupRequired: !u.TwoFactorEnabled() && auth.RequiresTwoFactor(u),
+	}
+}
+
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int64  `json:"expires_in"`
+}
+
+// ================================
+// 🔹 2FA
+// ================================
+
+type TwoFactorStatus struct {
+	Enabled  bool `json:"enabled"`
+	Required bool `json:"required"`
+	// dua field berikut hanya ada kalau 2FA aktif
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining *int64     `json:"recovery_codes_remaining,omitempty"`
+}
+
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodes hanya ditampilkan sekali saat dibuat
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,40 @@
+// Package dto berisi bentuk JSON respons API beserta mapper dari model.
+// Handler tidak mengirim model GORM atau map secara langsung, supaya bentuk
+// respons stabil, terdokumentasi di /openapi.json dan field rahasia seperti
+// kata sandi tidak pernah ikut terkirim.
+package dto
+
+// mapList memetakan slice model ke slice DTO. Hasilnya tidak pernah nil,
+// jadi daftar kosong tetap dikirim sebagai [] bukan null.
+func mapList[M any, D any](items []M, fn func(M) D) []D {
+	result := make([]D, 0, len(items))
+	for _, item := range items {
+		result = append(result, fn(item))
+	}
+	return result
+}
+
+// ================================
+// 🔹 PAGINATION
+// ================================
+
+// Page envelope semua daftar berhalaman. Total jumlah seluruh baris yang
+// cocok filter, bukan hanya yang ada di halaman ini.
+type Page[T any] struct {
+	Data       []T   `json:"data"`
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+func NewPage[T any](data []T, page, limit int, total int64) Page[T] {
+	p := Page[T]{Data: data, Page: page, Limit: limit, Total: total}
+	if p.Data == nil {
+		p.Data = []T{}
+	}
+	if limit > 0 {
+		p.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	return p
+}
@@ -0,0 +1,237 @@
+package dto
+
+import (
+	"cmp"
+	"go-crud/models"
+	"go-crud/services"
+	"time"
+)
+
+// ================================
+// 🔹 PRODUK & KATEGORI
+// ================================
+
+type Category struct {
+	ID           uint64    `json:"id"`
+	NamaCategory string    `json:"nama_category"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func NewCategory(c models.Category) Category {
+	return Category{
+		ID:           c.ID,
+		NamaCategory: c.NamaCategory,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+func NewCategories(categories []models.Category) []Category {
+	return mapList(categories, NewCategory)
+}
+
+func newCategoryRef(c *models.Category) *Category {
+	if c == nil || c.ID == 0 {
+		return nil
+	}
+	category := NewCategory(*c)
+	return &category
+}
+
+type FotoProduk struct {
+	ID          uint64 `json:"id"`
+	IDProduk    uint64 `json:"id_produk"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Ukuran      int64  `json:"ukuran"`
+	Urutan      int    `json:"urutan"`
+	Utama       bool   `json:"utama"`
+	// null untuk foto umum produk
+	IDVarian *uint64 `json:"id_varian"`
+	// varian thumbnail dan ukuran web; berisi url asli selama status_varian
+	// (pending, ready, failed) belum ready
+	URLThumb     string `json:"url_thumb"`
+	URLWeb       string `json:"url_web"`
+	StatusVarian string `json:"status_varian"`
+}
+
+func NewFotoProduk(f models.FotoProduk) FotoProduk {
+	return FotoProduk{
+		ID:           f.ID,
+		IDProduk:     f.IDProduk,
+		URL:          f.URL,
+		ContentType:  f.ContentType,
+		Ukuran:       f.Ukuran,
+		Urutan:       f.Urutan,
+		Utama:        f.Utama,
+		IDVarian:     f.IDVarian,
+		URLThumb:     cmp.Or(f.URLThumb, f.URL),
+		URLWeb:       cmp.Or(f.URLWeb, f.URL),
+		StatusVarian: f.StatusVarian,
+	}
+}
+
+func NewFotoProdukList(fotos []models.FotoProduk) []FotoProduk {
+	return mapList(fotos, NewFotoProduk)
+}
+
+type Produk struct {
+	ID            uint64    `json:"id"`
+	NamaProduk    string    `json:"nama_produk"`
+	Slug          string    `json:"slug"`
+	HargaReseller int       `json:"harga_reseller"`
+	HargaKonsumen int       `json:"harga_konsumen"`
+	Stok          int       `json:"stok"`
+	Deskripsi     *string   `json:"deskripsi"`
+	IDToko        uint64    `json:"id_toko"`
+	IDCategory    *uint64   `json:"id_category"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// relasi, null kalau tidak di-Preload / sudah dihapus
+	Toko       *Toko        `json:"toko"`
+	Category   *Category    `json:"category"`
+	FotoProduk []FotoProduk `json:"foto_produk"`
+	// kosong untuk produk tanpa varian
+	Opsi   []OpsiProduk   `json:"opsi"`
+	Varian []VarianProduk `json:"varian"`
+}
+
+func NewProduk(p models.Produk) Produk {
+	return Produk{
+		ID:            p.ID,
+		NamaProduk:    p.NamaProduk,
+		Slug:          p.Slug,
+		HargaReseller: p.HargaReseller,
+		HargaKonsumen: p.HargaKonsumen,
+		Stok:          p.Stok,
+		Deskripsi:     p.Deskripsi,
+		IDToko:        p.IDToko,
+		IDCategory:    p.IDCategory,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+		Toko:          newTokoRef(p.Toko),
+		Category:      newCategoryRef(p.Category),
+		FotoProduk:    mapList(p.FotoProduk, NewFotoProduk),
+		Opsi:          mapList(p.Opsi, NewOpsiProduk),
+		Varian:        NewVarianList(p.Opsi, p.Varian),
+	}
+}
+
+func NewProdukList(products []models.Produk) []Produk {
+	return mapList(products, NewProduk)
+}
+
+// ================================
+// 🔹 VARIAN PRODUK
+// ================================
+
+type NilaiOpsi struct {
+	ID    uint64 `json:"id"`
+	Nilai string `json:"nilai"`
+}
+
+type OpsiProduk struct {
+	ID    uint64      `json:"id"`
+	Nama  string      `json:"nama"`
+	Nilai []NilaiOpsi `json:"nilai"`
+}
+
+func NewOpsiProduk(o models.OpsiProduk) OpsiProduk {
+	return OpsiProduk{
+		ID:   o.ID,
+		Nama: o.Nama,
+		Nilai: mapList(o.Nilai, func(n models.NilaiOpsi) NilaiOpsi {
+			return NilaiOpsi{ID: n.ID, Nilai: n.Nilai}
+		}),
+	}
+}
+
+type VarianProduk struct {
+	ID  uint64 `json:"id"`
+	SKU string `json:"sku"`
+	// Nama label kombinasi, misal "Ukuran: XL, Warna: Merah"
+	Nama string `json:"nama"`
+	// Opsi nama opsi -> nilai
+	Opsi          map[string]string `json:"opsi"`
+	HargaReseller int               `json:"harga_reseller"`
+	HargaKonsumen int               `json:"harga_konsumen"`
+	Stok          int               `json:"stok"`
+}
+
+// NewVarianList opsi dipakai untuk menerjemahkan ID nilai setiap varian ke
+// nama opsi dan nilainya
+func NewVarianList(opsi []models.OpsiProduk, varian []models.VarianProduk) []VarianProduk {
+	type pair struct{ opsi, nilai string }
+	byID := map[uint64]pair{}
+	for _, o := range opsi {
+		for _, n := range o.Nilai {
+			byID[n.ID] = pair{o.Nama, n.Nilai}
+		}
+	}
+	return mapList(varian, func(v models.VarianProduk) VarianProduk {
+		resp := VarianProduk{
+			ID:            v.ID,
+			SKU:           v.SKU,
+			Nama:          v.Nama,
+			Opsi:          map[string]string{},
+			HargaReseller: v.HargaReseller,
+			HargaKonsumen: v.HargaKonsumen,
+			Stok:          v.Stok,
+		}
+		for _, n := range v.Nilai {
+			if p, ok := byID[n.IDNilai]; ok {
+				resp.Opsi[p.opsi] = p.nilai
+			}
+		}
+		return resp
+	})
+}
+
+// ProdukVarian semua opsi dan varian satu produk
+type ProdukVarian struct {
+	Opsi   []OpsiProduk   `json:"opsi"`
+	Varian []VarianProduk `json:"varian"`
+}
+
+func NewProdukVarian(pv services.ProdukVarian) ProdukVarian {
+	return ProdukVarian{
+		Opsi:   mapList(pv.Opsi, NewOpsiProduk),
+		Varian: NewVarianList(pv.Opsi, pv.Varian),
+	}
+}
+
+// ================================
+// 🔹 PENCARIAN PRODUK
+// ================================
+
+type ProdukFacet struct {
+	ID    uint64 `json:"id"`
+	Nama  string `json:"nama"`
+	Count int    `json:"count"`
+}
+
+type ProdukFacets struct {
+	Category []ProdukFacet `json:"category"`
+	Toko     []ProdukFacet `json:"toko"`
+}
+
+// ProdukSearch halaman hasil pencarian (urut relevansi) beserta jumlah hasil
+// per kategori dan toko dari seluruh hasil, bukan hanya halaman ini
+type ProdukSearch struct {
+	Page[Produk]
+	Facets ProdukFacets `json:"facets"`
+}
+
+func NewProdukSearch(result services.ProdukSearchPage) ProdukSearch {
+	facet := func(f services.ProdukFacet) ProdukFacet {
+		return ProdukFacet{ID: f.ID, Nama: f.Nama, Count: f.Count}
+	}
+	return ProdukSearch{
+		Page: NewPage(NewProdukList(result.Items), result.Page, result.Limit, result.Total),
+		Facets: ProdukFacets{
+			Category: mapList(result.Categories, facet),
+			Toko:     mapList(result.Toko, facet),
+		},
+	}
+}
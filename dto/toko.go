@@ -0,0 +1,56 @@
+package dto
+
+import "go-crud/models"
+
+// ================================
+// 🔹 TOKO
+// ================================
+
+type Toko struct {
+	ID       uint64 `json:"id"`
+	NamaToko string `json:"nama_toko"`
+	// kosong kalau toko belum punya foto
+	UrlFoto string `json:"url_foto"`
+	// thumbnail foto upload, sama dengan url_foto kalau belum ada
+	UrlFotoThumb string `json:"url_foto_thumb"`
+	// status varian foto upload (pending, ready, failed); kosong kalau foto
+	// berupa URL luar
+	FotoStatus string    `json:"foto_status"`
+	IDUser     uint64    `json:"user_id"`
+	User       *SafeUser `json:"user,omitempty"`
+}
+
+// NewToko user pemilik hanya disertakan kalau withUser dan User sudah di-Preload
+func NewToko(t models.Toko, withUser bool) Toko {
+	resp := Toko{
+		ID:         t.ID,
+		NamaToko:   t.NamaToko,
+		FotoStatus: t.FotoStatus,
+		IDUser:     t.IDUser,
+	}
+	if t.UrlFoto != nil {
+		resp.UrlFoto = *t.UrlFoto
+		resp.UrlFotoThumb = *t.UrlFoto
+	}
+	if t.UrlFotoThumb != nil {
+		resp.UrlFotoThumb = *t.UrlFotoThumb
+	}
+	if withUser && t.User != nil && t.User.ID != 0 {
+		user := NewSafeUser(*t.User)
+		resp.User = &user
+	}
+	return resp
+}
+
+func NewTokoList(toko []models.Toko, withUser bool) []Toko {
+	return mapList(toko, func(t models.Toko) Toko { return NewToko(t, withUser) })
+}
+
+// newTokoRef toko relasi yang bisa nil (belum di-Preload / sudah dihapus)
+func newTokoRef(t *models.Toko) *Toko {
+	if t == nil || t.ID == 0 {
+		return nil
+	}
+	toko := NewToko(*t, false)
+	return &toko
+}
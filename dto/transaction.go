@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"go-crud/models"
+	"time"
+)
+
+// ================================
+// 🔹 TRANSAKSI
+// ================================
+
+type TransactionCreated struct {
+	ID          uint64 `json:"id"`
+	KodeInvoice string `json:"kode_invoice"`
+	HargaTotal  int    `json:"harga_total"`
+	MethodBayar string `json:"method_bayar"`
+}
+
+// Transaction dipakai daftar dan detail transaksi. Relasi yang tidak
+// di-Preload (misal di daftar) bernilai null.
+type Transaction struct {
+	ID          uint64    `json:"id"`
+	IDUser      uint64    `json:"id_user"`
+	KodeInvoice string    `json:"kode_invoice"`
+	HargaTotal  int       `json:"harga_total"`
+	MethodBayar *string   `json:"method_bayar"`
+	CreatedAt   time.Time `json:"created_at"`
+	// null kalau alamat sudah dihapus pemiliknya
+	AlamatKirim *Alamat           `json:"alamat_kirim"`
+	DetailTrx   []TransactionItem `json:"detail_trx"`
+}
+
+type TransactionItem struct {
+	Product    ProdukSnapshot `json:"product"`
+	Toko       *Toko          `json:"toko"`
+	Kuantitas  int            `json:"kuantitas"`
+	HargaTotal int            `json:"harga_total"`
+}
+
+// ProdukSnapshot data produk saat transaksi dibuat (LogProduk), bukan produk
+// terkini. ID tetap id produk aslinya.
+type ProdukSnapshot struct {
+	ID            uint64       `json:"id"`
+	NamaProduk    string       `json:"nama_produk"`
+	Slug          string       `json:"slug"`
+	HargaReseller int          `json:"harga_reseller"`
+	HargaKonsumen int          `json:"harga_konsumen"`
+	Deskripsi     *string      `json:"deskripsi"`
+	Toko          *Toko        `json:"toko"`
+	Category      *Category    `json:"category"`
+	Photos        []FotoProduk `json:"photos"`
+	// null kalau produk dibeli tanpa varian
+	Varian *VarianSnapshot `json:"varian"`
+}
+
+// VarianSnapshot varian yang dibeli; harga di ProdukSnapshot sudah harga varian
+type VarianSnapshot struct {
+	ID   uint64 `json:"id"`
+	SKU  string `json:"sku"`
+	Nama string `json:"nama"`
+}
+
+func NewTransaction(trx models.Trx) Transaction {
+	resp := Transaction{
+		ID:          trx.ID,
+		IDUser:      trx.IDUser,
+		KodeInvoice: trx.KodeInvoice,
+		HargaTotal:  trx.HargaTotal,
+		MethodBayar: trx.MethodBayar,
+		CreatedAt:   trx.CreatedAt,
+		DetailTrx:   []TransactionItem{},
+	}
+	if trx.Alamat != nil {
+		alamat := NewAlamat(*trx.Alamat)
+		resp.AlamatKirim = &alamat
+	}
+
+	for _, d := range trx.DetailTrx {
+		p := d.LogProduk
+		if p == nil {
+			continue
+		}
+		// produk tanpa kategori / toko yang sudah dihapus tidak boleh bikin panic
+		toko := newTokoRef(p.Toko)
+		resp.DetailTrx = append(resp.DetailTrx, TransactionItem{
+			Product: ProdukSnapshot{
+				ID:            p.IDProduk,
+				NamaProduk:    p.NamaProduk,
+				Slug:          p.Slug,
+				HargaReseller: p.HargaReseller,
+				HargaKonsumen: p.HargaKonsumen,
+				Deskripsi:     p.Deskripsi,
+				Toko:          toko,
+				Category:      newCategoryRef(p.Category),
+				Photos:        mapList(snapshotPhotos(*p), NewFotoProduk),
+				Varian:        newVarianSnapshot(*p),
+			},
+			Toko:       toko,
+			Kuantitas:  d.Kuantitas,
+			HargaTotal: d.HargaTotal,
+		})
+	}
+	return resp
+}
+
+func newVarianSnapshot(p models.LogProduk) *VarianSnapshot {
+	if p.IDVarian == nil {
+		return nil
+	}
+	return &VarianSnapshot{ID: *p.IDVarian, SKU: p.SKU, Nama: p.NamaVarian}
+}
+
+// snapshotPhotos foto khusus varian yang dibeli kalau ada, selain itu semua
+// foto produk
+func snapshotPhotos(p models.LogProduk) []models.FotoProduk {
+	if p.IDVarian == nil {
+		return p.Photos
+	}
+	var photos []models.FotoProduk
+	for _, f := range p.Photos {
+		if f.IDVarian != nil && *f.IDVarian == *p.IDVarian {
+			photos = append(photos, f)
+		}
+	}
+	if len(photos) == 0 {
+		return p.Photos
+	}
+	return photos
+}
+
+func NewTransactions(trans []models.Trx) []Transaction {
+	return mapList(trans, NewTransaction)
+}
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"go-crud/models"
+	"go-crud/utils"
+	"time"
+)
+
+// ================================
+// 🔹 USER
+// ================================
+
+// User data user tanpa kata sandi, token version maupun data 2FA
+type User struct {
+	ID              uint64     `json:"id"`
+	Nama            string     `json:"nama"`
+	NoTelp          *string    `json:"no_telp"`
+	TanggalLahir    *time.Time `json:"tanggal_lahir"`
+	JenisKelamin    *string    `json:"jenis_kelamin"`
+	Tentang         *string    `json:"tentang"`
+	Pekerjaan       *string    `json:"pekerjaan"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	IDProvinsi      *string    `json:"id_provinsi"`
+	IDKota          *string    `json:"id_kota"`
+	Bahasa          *string    `json:"bahasa"`
+	// kosong kalau Roles tidak di-Preload
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewUser(u models.User) User {
+	return User{
+		ID:              u.ID,
+		Nama:            u.Nama,
+		NoTelp:          u.NoTelp,
+		TanggalLahir:    u.TanggalLahir,
+		JenisKelamin:    u.JenisKelamin,
+		Tentang:         u.Tentang,
+		Pekerjaan:       u.Pekerjaan,
+		Email:           u.Email,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		IDProvinsi:      u.IDProvinsi,
+		IDKota:          u.IDKota,
+		Bahasa:          u.Bahasa,
+		Roles:           u.RoleNames(),
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
+
+// UserWithWilayah data user beserta nama provinsi & kota dari API wilayah
+// (null kalau kode kosong / API wilayah tidak bisa dihubungi)
+type UserWithWilayah struct {
+	User       User            `json:"user"`
+	IDProvinsi *utils.Province `json:"id_provinsi"`
+	IDKota     *utils.City     `json:"id_kota"`
+}
+
+func NewUserWithWilayah(u models.User, provinsi *utils.Province, kota *utils.City) UserWithWilayah {
+	return UserWithWilayah{User: NewUser(u), IDProvinsi: provinsi, IDKota: kota}
+}
+
+// SafeUser ringkasan pemilik yang boleh ditampilkan bersama data toko
+type SafeUser struct {
+	ID    uint64 `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func NewSafeUser(u models.User) SafeUser {
+	return SafeUser{ID: u.ID, Name: u.Nama, Email: u.Email}
+}
+
+// Profile respons GET /api/profile
+type Profile struct {
+	User User `json:"user"`
+}
+
+// UserRoles respons PUT /api/users/:id/roles
+type UserRoles struct {
+	ID          uint64   `json:"id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// UnlockUser respons POST /api/users/:id/unlock
+type UnlockUser struct {
+	UserID            uint64 `json:"user_id"`
+	WasLocked         bool   `json:"was_locked"`
+	LockedSecondsLeft int    `json:"locked_seconds_left"`
+	FailedAttempts    int64  `json:"failed_attempts"`
+}
@@ -0,0 +1,46 @@
+package i18n
+
+import (
+	"go-crud/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+// FromContext menentukan bahasa respons untuk request ini:
+//  1. preferensi user yang login (kolom users.bahasa)
+//  2. header Accept-Language
+//  3. Default (bahasa Indonesia)
+func FromContext(c echo.Context) Lang {
+	if user, ok := c.Get("authUser").(models.User); ok && user.Bahasa != nil {
+		if lang, ok := Parse(*user.Bahasa); ok {
+			return lang
+		}
+	}
+	return FromAcceptLanguage(c.Request().Header.Get(headerAcceptLanguage))
+}
+
+// T menerjemahkan key ke bahasa request
+func T(c echo.Context, key string, args ...interface{}) string {
+	return Translate(FromContext(c), key, args...)
+}
+
+// Middleware mengisi header Content-Language sesuai bahasa respons. Bahasa
+// baru ditentukan saat respons ditulis, jadi preferensi user yang di-attach
+// middleware auth sesudahnya ikut terbaca.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			res.Header().Add(echo.HeaderVary, headerAcceptLanguage)
+			res.Before(func() {
+				res.Header().Set(headerContentLanguage, string(FromContext(c)))
+			})
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// ================================
+// 🔹 BAHASA
+// ================================
+
+// Lang kode bahasa respons API (ISO 639-1)
+type Lang string
+
+const (
+	ID Lang = "id"
+	EN Lang = "en"
+
+	// Default dipakai kalau client tidak meminta bahasa yang didukung
+	Default = ID
+)
+
+// urutan penting: bahasa pertama jadi fallback matcher
+var (
+	supported = []Lang{ID, EN}
+	matcher   = language.NewMatcher([]language.Tag{language.Indonesian, language.English})
+)
+
+var catalogs = map[Lang]map[string]string{
+	ID: messagesID,
+	EN: messagesEN,
+}
+
+// Parse mengubah string (misal dari kolom users.bahasa) menjadi Lang.
+// ok false kalau bahasanya tidak didukung.
+func Parse(s string) (Lang, bool) {
+	for _, lang := range supported {
+		if string(lang) == s {
+			return lang, true
+		}
+	}
+	return Default, false
+}
+
+// Supported daftar kode bahasa yang didukung, untuk validasi input
+func Supported() []string {
+	out := make([]string, len(supported))
+	for i, lang := range supported {
+		out[i] = string(lang)
+	}
+	return out
+}
+
+// FromAcceptLanguage memilih bahasa terbaik dari header Accept-Language,
+// misal "en-US,en;q=0.9,id;q=0.8" -> EN. Header kosong/tidak cocok -> Default.
+func FromAcceptLanguage(header string) Lang {
+	if header == "" {
+		return Default
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// ================================
+// 🔹 TERJEMAHAN
+// ================================
+
+// Translate mengambil pesan key dalam bahasa lang. Kalau key tidak ada di
+// bahasa itu dipakai bahasa Default, kalau tetap tidak ada key dikembalikan
+// apa adanya (berguna untuk kode mesin seperti "invalid_credentials" dan
+// teks dari luar seperti pesan error IdP). args diisi ke format fmt.
+func Translate(lang Lang, key string, args ...interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Message adalah key katalog beserta argumennya, diterjemahkan belakangan
+// saat bahasa client sudah diketahui
+type Message struct {
+	Key  string
+	Args []interface{}
+}
+
+func M(key string, args ...interface{}) Message {
+	return Message{Key: key, Args: args}
+}
+
+func (m Message) In(lang Lang) string {
+	return Translate(lang, m.Key, m.Args...)
+}
+
+// Missing mengembalikan key yang ada di satu katalog tapi tidak di katalog
+// lain, per bahasa. Dipanggil saat start supaya terjemahan yang terlewat
+// kelihatan di log.
+func Missing() map[Lang][]string {
+	all := map[string]bool{}
+	for _, catalog := range catalogs {
+		for key := range catalog {
+			all[key] = true
+		}
+	}
+
+	missing := map[Lang][]string{}
+	for lang, catalog := range catalogs {
+		for key := range all {
+			if _, ok := catalog[key]; !ok {
+				missing[lang] = append(missing[lang], key)
+			}
+		}
+		sort.Strings(missing[lang])
+	}
+	return missing
+}
+
+// CheckCatalogs menulis ke log key yang belum diterjemahkan
+func CheckCatalogs() {
+	for lang, keys := range Missing() {
+		if len(keys) > 0 {
+			log.Printf("⚠️  i18n: %d pesan belum ada terjemahan %s: %v", len(keys), lang, keys)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package i18n
+
+// messagesEN katalog bahasa Inggris. Key yang tidak ada di sini jatuh ke
+// messagesID.
+var messagesEN = map[string]string{
+	// error
+	"error.rate_limited":           "Too many requests, try again later",
+	"error.bad_request":            "Bad request",
+	"error.unauthorized":           "Unauthorized",
+	"error.forbidden":              "Forbidden",
+	"error.not_found":              "Not found",
+	"error.conflict":               "Conflict",
+	"error.validation_failed":      "Invalid input",
+	"error.out_of_stock":           "Out of stock",
+	"error.too_many_requests":      "Too many requests",
+	"error.internal_error":         "Internal server error",
+	"error.upstream_error":         "Upstream service error",
+	"error.payload_too_large":      "Payload too large",
+	"error.unsupported_media_type": "Unsupported media type",
+
+	// validation
+	"validation.required":       "is required",
+	"validation.email":          "must be a valid email address",
+	"validation.url":            "must be a valid URL",
+	"validation.oneof":          "must be one of: %s",
+	"validation.min":            "must be at least %s",
+	"validation.min_chars":      "must be at least %s characters",
+	"validation.min_items":      "must contain at least %s items",
+	"validation.max":            "must be at most %s",
+	"validation.max_chars":      "must be at most %s characters",
+	"validation.max_items":      "must contain at most %s items",
+	"validation.len":            "must be exactly %s",
+	"validation.len_chars":      "must be exactly %s characters",
+	"validation.len_items":      "must contain exactly %s items",
+	"validation.ltefield":       "must not be greater than %s",
+	"validation.phone_id":       "invalid phone number, use 08xx or +628xx without spaces",
+	"validation.provinsi_id":    "province id must be 2 digits",
+	"validation.kota_id":        "city id must be 4 digits",
+	"validation.kota_id_prefix": "city id must be 4 digits and start with %s",
+	"validation.harga":          "price must be between 0 and %d",
+	"validation.invalid":        "is invalid (%s)",
+
+	// common
+	"common.get_success":         "Data retrieved successfully",
+	"common.get_failed":          "Failed to retrieve data",
+	"common.create_success":      "Data created successfully",
+	"common.create_failed":       "Failed to create data",
+	"common.update_success":      "Data updated successfully",
+	"common.update_failed":       "Failed to update data",
+	"common.delete_success":      "Data deleted successfully",
+	"common.delete_failed":       "Failed to delete data",
+	"common.invalid_input":       "Invalid input",
+	"common.invalid_request":     "Invalid request",
+	"common.invalid_format":      "Malformed request body",
+	"common.invalid_field_type":  "%s has the wrong type",
+	"common.invalid_id":          "Invalid ID",
+	"common.nothing_changed":     "Nothing to update",
+	"common.unauthorized":        "Unauthorized",
+	"common.user_not_in_context": "User not found in request context (invalid token?)",
+	"common.user_context_failed": "Failed to read user from request context",
+
+	// home
+	"home.welcome": "Welcome to the Home Page!",
+
+	// auth
+	"auth.hash_failed":           "Failed to hash password",
+	"auth.register_failed":       "Failed to save user",
+	"auth.register_success":      "Registration successful, check your email to verify your account",
+	"auth.login_failed":          "Failed to process login",
+	"auth.invalid_credentials":   "Incorrect email or password",
+	"auth.challenge_failed":      "Failed to create 2FA challenge",
+	"auth.enter_2fa_code":        "Enter your 2FA code",
+	"auth.verify_2fa_failed":     "Failed to verify 2FA code",
+	"auth.token_failed":          "Failed to create token",
+	"auth.login_success":         "Login successful",
+	"auth.refresh_failed":        "Failed to refresh token",
+	"auth.refresh_success":       "Token refreshed",
+	"auth.logout_failed":         "Failed to log out",
+	"auth.logout_all_success":    "Logged out of all sessions",
+	"auth.logout_success":        "Logged out",
+	"auth.token_valid":           "Token is valid",
+	"auth.token_missing":         "Token not found",
+	"auth.token_invalid":         "Invalid token",
+	"auth.not_access_token":      "Token is not an access token",
+	"auth.invalid_claims":        "Invalid token claims",
+	"auth.user_id_claim_missing": "user_id claim not found",
+	"auth.token_check_failed":    "Failed to check token status",
+	"auth.token_revoked":         "Token has been logged out, please log in again",
+	"auth.token_expired":         "Token is no longer valid, please log in again",
+	"auth.user_not_in_db":        "User not found in database",
+	"auth.token_or_user_invalid": "Invalid token or user not found",
+	"auth.retry_after":           "try again in %d seconds",
+	"auth.invalid_api_key":       "API key is invalid, revoked or expired",
+	"auth.invalid_challenge":     "Challenge token is invalid or expired",
+	"auth.account_locked":        "Account is temporarily locked after too many failed login attempts",
+	"auth.login_backoff":         "Too many login attempts, wait a moment and try again",
+	"auth.invalid_refresh_token": "Refresh token is invalid or expired",
+	"auth.refresh_token_reused":  "Refresh token was already used, all related sessions have been revoked",
+
+	// account
+	"account.already_verified":    "Email is already verified",
+	"account.verify_token_failed": "Failed to create verification token",
+	"account.verify_sent":         "Verification email sent",
+	"account.verify_failed":       "Failed to verify email",
+	"account.verify_success":      "Email verified",
+	"account.reset_sent":          "If the email is registered, a password reset link will be sent",
+	"account.reset_failed":        "Failed to reset password",
+	"account.reset_success":       "Password reset, please log in again",
+	"account.invalid_token":       "Token is invalid, already used or expired",
+
+	// two_factor
+	"two_factor.required":             "This account must enable 2FA first via /api/2fa/setup",
+	"two_factor.setup_failed":         "Failed to create 2FA secret",
+	"two_factor.setup_success":        "Scan the QR code, then confirm with a code from your authenticator app",
+	"two_factor.status_failed":        "Failed to get 2FA status",
+	"two_factor.status":               "2FA status",
+	"two_factor.enable_failed":        "Failed to enable 2FA",
+	"two_factor.recovery_failed":      "Failed to create recovery codes",
+	"two_factor.wrong_password":       "Incorrect password",
+	"two_factor.disable_failed":       "Failed to disable 2FA",
+	"two_factor.enabled":              "2FA enabled, keep your recovery codes somewhere safe",
+	"two_factor.disabled":             "2FA disabled",
+	"two_factor.required_for_role":    "2FA is required for this account's role",
+	"two_factor.recovery_regenerated": "New recovery codes created, old codes are no longer valid",
+	"two_factor.invalid_code":         "2FA code is incorrect or already used",
+	"two_factor.already_enabled":      "2FA is already enabled",
+	"two_factor.not_enabled":          "2FA is not enabled",
+	"two_factor.not_setup":            "Run 2FA setup first",
+
+	// session
+	"session.check_failed":     "Failed to check session",
+	"session.invalid_id":       "Invalid session ID",
+	"session.terminate_failed": "Failed to end session",
+	"session.list_failed":      "Failed to get sessions",
+	"session.terminated":       "Session ended",
+	"session.not_found":        "Session not found",
+	"session.ended":            "Session has ended, please log in again",
+
+	// api_key
+	"api_key.check_failed":            "Failed to check API key",
+	"api_key.toko_forbidden":          "Store API keys cannot access this endpoint",
+	"api_key.not_allowed":             "This endpoint cannot be accessed with an API key",
+	"api_key.toko_owner_only":         "Only the store owner can create store API keys",
+	"api_key.create_failed":           "Failed to create API key",
+	"api_key.save_failed":             "Failed to save API key",
+	"api_key.created":                 "API key created, store it now because it will not be shown again",
+	"api_key.list_failed":             "Failed to get API keys",
+	"api_key.not_found":               "API key not found",
+	"api_key.revoke_failed":           "Failed to revoke API key",
+	"api_key.revoked":                 "API key revoked",
+	"api_key.permission_not_for_toko": "Permission %s cannot be used by store API keys",
+
+	// rbac
+	"rbac.permission_required":  "Access denied, permission %s is required",
+	"rbac.unknown_permission":   "Unknown permission: %s",
+	"rbac.permission_not_owned": "You do not have permission %s",
+
+	// role
+	"role.self_admin_revoke": "Cannot revoke your own admin role",
+	"role.unknown":           "Unknown role: %s",
+
+	// oidc
+	"oidc.continue":           "Continue logging in on the SSO page",
+	"oidc.link_continue":      "Send code and state to /api/identities/callback to link the SSO account",
+	"oidc.cancelled":          "SSO login cancelled",
+	"oidc.failed":             "SSO login failed",
+	"oidc.linked":             "SSO account linked",
+	"oidc.list_failed":        "Failed to get SSO accounts",
+	"oidc.unlinked":           "SSO account unlinked",
+	"oidc.provider_failed":    "Could not complete login with the identity provider",
+	"oidc.invalid_state":      "SSO login session is invalid or expired, please try again",
+	"oidc.disabled":           "SSO login is not enabled",
+	"oidc.invalid_id_token":   "Invalid id_token from the IdP",
+	"oidc.email_missing":      "The IdP did not send an email, make sure the email scope is allowed",
+	"oidc.email_taken":        "Email is already registered, log in with your password and link the SSO account from your profile",
+	"oidc.domain_not_allowed": "This email domain is not allowed to log in via SSO",
+	"oidc.identity_linked":    "This SSO account is already linked to another user",
+	"oidc.identity_not_found": "SSO account not found",
+
+	// user
+	"user.list_success":       "Users retrieved successfully",
+	"user.get_success":        "User retrieved successfully",
+	"user.get_failed":         "Failed to get user",
+	"user.not_found":          "User not found",
+	"user.invalid_id":         "Invalid user ID",
+	"user.update_failed":      "Failed to save changes",
+	"user.updated":            "User updated",
+	"user.delete_failed":      "Failed to delete user",
+	"user.deleted":            "User deleted",
+	"user.lock_status_failed": "Failed to read account lock status",
+	"user.unlock_failed":      "Failed to unlock account",
+	"user.unlocked":           "Account unlocked",
+	"user.edit_forbidden":     "Cannot edit another user",
+	"user.view_forbidden":     "Cannot view another user's data",
+	"user.invalid_gender":     "jenis_kelamin must be 'Laki-laki' or 'Perempuan'",
+
+	// toko
+	"toko.invalid_id":     "Invalid store ID",
+	"toko.not_found":      "Store not found",
+	"toko.updated":        "Store updated",
+	"toko.deactivated":    "Store deactivated",
+	"toko.edit_forbidden": "Cannot modify another user's store",
+	"toko.photo_updated":  "Store photo uploaded, optimized versions are being processed",
+
+	// alamat
+	"alamat.not_found":        "Address not found",
+	"alamat.deleted":          "Address deleted",
+	"alamat.view_forbidden":   "Cannot view another user's address",
+	"alamat.edit_forbidden":   "Cannot modify another user's address",
+	"alamat.delete_forbidden": "Cannot delete another user's address",
+
+	// category
+	"category.invalid_id":    "Invalid category ID",
+	"category.not_found":     "Category not found",
+	"category.created":       "Category created successfully",
+	"category.create_failed": "Failed to create category",
+	"category.updated":       "Category updated successfully",
+	"category.update_failed": "Failed to update category",
+	"category.deleted":       "Category deleted successfully",
+	"category.delete_failed": "Failed to delete category",
+
+	// product
+	"product.invalid_id":              "Invalid product ID",
+	"product.not_found":               "Product not found",
+	"product.created":                 "Product created successfully",
+	"product.create_failed":           "Failed to create product",
+	"product.updated":                 "Product updated",
+	"product.update_failed":           "Failed to update product",
+	"product.deleted":                 "Product deleted successfully",
+	"product.delete_failed":           "Failed to delete product",
+	"product.no_toko":                 "User does not have a store yet",
+	"product.edit_forbidden":          "Cannot modify another store's product",
+	"product.delete_forbidden":        "Cannot delete another store's product",
+	"product.reseller_price_too_high": "harga_reseller must not be greater than harga_konsumen",
+	"product.invalid_price_range":     "harga_min must not be greater than harga_max",
+	"product.has_variants":            "Stock and prices of a product with variants are changed per variant",
+
+	// photo
+	"photo.invalid_id":        "Invalid photo ID",
+	"photo.not_found":         "Photo not found",
+	"photo.uploaded":          "Photos uploaded successfully",
+	"photo.upload_failed":     "Failed to upload photos",
+	"photo.no_file":           "No file in the foto field",
+	"photo.single_file":       "Send exactly one file in the foto field",
+	"photo.too_large":         "%s is larger than the %s limit",
+	"photo.request_too_large": "Upload is too large",
+	"photo.unsupported_type":  "%s has an unsupported format (%s), use JPEG, PNG, WebP or GIF",
+	"photo.limit_reached":     "A product can have at most %d photos",
+	"photo.order_mismatch":    "ids must list every product photo exactly once",
+	"photo.reordered":         "Photo order updated",
+	"photo.primary_set":       "Primary photo updated",
+	"photo.deleted":           "Photo deleted successfully",
+	"photo.delete_failed":     "Failed to delete photo",
+	"photo.edit_forbidden":    "Cannot modify another store's product photos",
+
+	// variant
+	"variant.invalid_id":            "Invalid variant ID",
+	"variant.not_found":             "Variant not found",
+	"variant.saved":                 "Product variants saved",
+	"variant.save_failed":           "Failed to save product variants",
+	"variant.updated":               "Variant updated",
+	"variant.options_required":      "Variants need at least one option",
+	"variant.variants_required":     "Options need at least one variant",
+	"variant.duplicate_option":      "Option %s is listed more than once",
+	"variant.duplicate_value":       "Value %s is listed more than once in option %s",
+	"variant.invalid_combination":   "Variant %s must pick exactly one value for every option",
+	"variant.unknown_value":         "Value %s does not exist in option %s",
+	"variant.duplicate_combination": "Combination %s is listed more than once",
+	"variant.duplicate_sku":         "SKU %s is already in use",
+	"variant.repeated_sku":          "SKU %s is used by more than one variant",
+
+	// transaction
+	"transaction.invalid_id":        "Invalid transaction ID",
+	"transaction.create_failed":     "Failed to create transaction",
+	"transaction.created":           "Transaction created",
+	"transaction.get_failed":        "Failed to get transaction",
+	"transaction.not_found":         "Transaction not found",
+	"transaction.forbidden":         "You do not have access to this transaction",
+	"transaction.empty":             "No products purchased",
+	"transaction.invalid_product":   "Invalid product",
+	"transaction.out_of_stock":      "Insufficient stock: %s",
+	"transaction.variant_required":  "Choose a variant for product %s",
+	"transaction.variant_not_found": "Variant not found for product %s",
+
+	// wilayah
+	"wilayah.provinces_success": "Provinces retrieved successfully",
+	"wilayah.provinces_failed":  "Failed to get provinces",
+	"wilayah.cities_success":    "Cities retrieved successfully",
+	"wilayah.cities_failed":     "Failed to get cities",
+	"wilayah.province_success":  "Province retrieved successfully",
+	"wilayah.province_failed":   "Failed to get province",
+	"wilayah.city_success":      "City retrieved successfully",
+	"wilayah.city_failed":       "Failed to get city",
+}
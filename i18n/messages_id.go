@@ -0,0 +1,294 @@
+package i18n
+
+// messagesID katalog bahasa Indonesia (bahasa default). Pesan yang punya
+// argumen memakai format fmt (%s, %d).
+var messagesID = map[string]string{
+	// error
+	"error.rate_limited":           "Terlalu banyak request, coba lagi nanti",
+	"error.bad_request":            "Permintaan tidak valid",
+	"error.unauthorized":           "Tidak terautentikasi",
+	"error.forbidden":              "Akses ditolak",
+	"error.not_found":              "Data tidak ditemukan",
+	"error.conflict":               "Data bentrok dengan data yang sudah ada",
+	"error.validation_failed":      "Input tidak valid",
+	"error.out_of_stock":           "Stok tidak mencukupi",
+	"error.too_many_requests":      "Terlalu banyak request",
+	"error.internal_error":         "Terjadi kesalahan pada server",
+	"error.upstream_error":         "Layanan eksternal sedang bermasalah",
+	"error.payload_too_large":      "Ukuran data terlalu besar",
+	"error.unsupported_media_type": "Format file tidak didukung",
+
+	// validation
+	"validation.required":       "wajib diisi",
+	"validation.email":          "format email tidak valid",
+	"validation.url":            "URL tidak valid",
+	"validation.oneof":          "harus salah satu dari: %s",
+	"validation.min":            "minimal %s",
+	"validation.min_chars":      "minimal %s karakter",
+	"validation.min_items":      "minimal %s item",
+	"validation.max":            "maksimal %s",
+	"validation.max_chars":      "maksimal %s karakter",
+	"validation.max_items":      "maksimal %s item",
+	"validation.len":            "harus tepat %s",
+	"validation.len_chars":      "harus tepat %s karakter",
+	"validation.len_items":      "harus tepat %s item",
+	"validation.ltefield":       "tidak boleh lebih besar dari %s",
+	"validation.phone_id":       "nomor telepon tidak valid, gunakan format 08xx atau +628xx tanpa spasi",
+	"validation.provinsi_id":    "id provinsi harus 2 digit angka",
+	"validation.kota_id":        "id kota harus 4 digit angka",
+	"validation.kota_id_prefix": "id kota harus 4 digit angka dan diawali %s",
+	"validation.harga":          "harga harus antara 0 dan %d",
+	"validation.invalid":        "tidak valid (%s)",
+
+	// common
+	"common.get_success":         "Berhasil mengambil data",
+	"common.get_failed":          "Gagal mengambil data",
+	"common.create_success":      "Berhasil menyimpan data",
+	"common.create_failed":       "Gagal menyimpan data",
+	"common.update_success":      "Berhasil memperbarui data",
+	"common.update_failed":       "Gagal memperbarui data",
+	"common.delete_success":      "Berhasil menghapus data",
+	"common.delete_failed":       "Gagal menghapus data",
+	"common.invalid_input":       "Input tidak valid",
+	"common.invalid_request":     "Request tidak valid",
+	"common.invalid_format":      "Format request tidak valid",
+	"common.invalid_field_type":  "Tipe data %s salah",
+	"common.invalid_id":          "ID tidak valid",
+	"common.nothing_changed":     "Tidak ada data yang diubah",
+	"common.unauthorized":        "Tidak terautentikasi",
+	"common.user_not_in_context": "User tidak ditemukan dalam context (token tidak valid?)",
+	"common.user_context_failed": "Gagal membaca data user dari context",
+
+	// home
+	"home.welcome": "Selamat datang di halaman utama!",
+
+	// auth
+	"auth.hash_failed":           "Gagal hash password",
+	"auth.register_failed":       "Gagal menyimpan data pengguna",
+	"auth.register_success":      "Register berhasil, cek email untuk verifikasi akun",
+	"auth.login_failed":          "Gagal memproses login",
+	"auth.invalid_credentials":   "Email atau password salah",
+	"auth.challenge_failed":      "Gagal membuat challenge 2FA",
+	"auth.enter_2fa_code":        "Masukkan kode 2FA",
+	"auth.verify_2fa_failed":     "Gagal verifikasi kode 2FA",
+	"auth.token_failed":          "Gagal membuat token",
+	"auth.login_success":         "Login berhasil",
+	"auth.refresh_failed":        "Gagal memperbarui token",
+	"auth.refresh_success":       "Token berhasil diperbarui",
+	"auth.logout_failed":         "Gagal logout",
+	"auth.logout_all_success":    "Berhasil logout dari semua sesi",
+	"auth.logout_success":        "Logout berhasil",
+	"auth.token_valid":           "Token valid",
+	"auth.token_missing":         "Token tidak ditemukan",
+	"auth.token_invalid":         "Token tidak valid",
+	"auth.not_access_token":      "Token bukan access token",
+	"auth.invalid_claims":        "Klaim token tidak valid",
+	"auth.user_id_claim_missing": "Klaim user_id tidak ditemukan",
+	"auth.token_check_failed":    "Gagal memeriksa status token",
+	"auth.token_revoked":         "Token sudah logout, silakan login ulang",
+	"auth.token_expired":         "Token sudah tidak berlaku, silakan login ulang",
+	"auth.user_not_in_db":        "User tidak ditemukan di database",
+	"auth.token_or_user_invalid": "Token tidak valid atau user tidak ditemukan",
+	"auth.retry_after":           "coba lagi dalam %d detik",
+	"auth.invalid_api_key":       "API key tidak valid, sudah dicabut, atau sudah kedaluwarsa",
+	"auth.invalid_challenge":     "challenge token tidak valid atau sudah kedaluwarsa",
+	"auth.account_locked":        "akun dikunci sementara karena terlalu banyak percobaan login gagal",
+	"auth.login_backoff":         "terlalu banyak percobaan login, tunggu sebentar lalu coba lagi",
+	"auth.invalid_refresh_token": "refresh token tidak valid atau sudah kedaluwarsa",
+	"auth.refresh_token_reused":  "refresh token sudah pernah dipakai, semua sesi terkait dicabut",
+
+	// account
+	"account.already_verified":    "Email sudah terverifikasi",
+	"account.verify_token_failed": "Gagal membuat token verifikasi",
+	"account.verify_sent":         "Email verifikasi sudah dikirim",
+	"account.verify_failed":       "Gagal verifikasi email",
+	"account.verify_success":      "Email berhasil diverifikasi",
+	"account.reset_sent":          "Jika email terdaftar, link reset kata sandi akan dikirim",
+	"account.reset_failed":        "Gagal reset kata sandi",
+	"account.reset_success":       "Kata sandi berhasil direset, silakan login kembali",
+	"account.invalid_token":       "token tidak valid, sudah dipakai, atau sudah kedaluwarsa",
+
+	// two_factor
+	"two_factor.required":             "Akun ini wajib mengaktifkan 2FA terlebih dahulu lewat /api/2fa/setup",
+	"two_factor.setup_failed":         "Gagal membuat secret 2FA",
+	"two_factor.setup_success":        "Scan QR code lalu konfirmasi dengan kode dari aplikasi authenticator",
+	"two_factor.status_failed":        "Gagal mengambil status 2FA",
+	"two_factor.status":               "Status 2FA",
+	"two_factor.enable_failed":        "Gagal mengaktifkan 2FA",
+	"two_factor.recovery_failed":      "Gagal membuat recovery code",
+	"two_factor.wrong_password":       "Password salah",
+	"two_factor.disable_failed":       "Gagal menonaktifkan 2FA",
+	"two_factor.enabled":              "2FA aktif, simpan recovery code di tempat aman",
+	"two_factor.disabled":             "2FA berhasil dinonaktifkan",
+	"two_factor.required_for_role":    "2FA wajib untuk role akun ini",
+	"two_factor.recovery_regenerated": "Recovery code baru dibuat, kode lama tidak berlaku lagi",
+	"two_factor.invalid_code":         "kode 2FA salah atau sudah dipakai",
+	"two_factor.already_enabled":      "2FA sudah aktif",
+	"two_factor.not_enabled":          "2FA belum aktif",
+	"two_factor.not_setup":            "jalankan setup 2FA terlebih dahulu",
+
+	// session
+	"session.check_failed":     "Gagal memeriksa session",
+	"session.invalid_id":       "ID session tidak valid",
+	"session.terminate_failed": "Gagal mengakhiri session",
+	"session.list_failed":      "Gagal mengambil data session",
+	"session.terminated":       "Session berhasil diakhiri",
+	"session.not_found":        "session tidak ditemukan",
+	"session.ended":            "session sudah diakhiri, silakan login ulang",
+
+	// api_key
+	"api_key.check_failed":            "Gagal memeriksa API key",
+	"api_key.toko_forbidden":          "API key toko tidak bisa mengakses endpoint ini",
+	"api_key.not_allowed":             "Endpoint ini tidak bisa diakses dengan API key",
+	"api_key.toko_owner_only":         "Hanya pemilik toko yang bisa membuat API key toko",
+	"api_key.create_failed":           "Gagal membuat API key",
+	"api_key.save_failed":             "Gagal menyimpan API key",
+	"api_key.created":                 "API key dibuat, simpan key ini karena tidak akan ditampilkan lagi",
+	"api_key.list_failed":             "Gagal mengambil API key",
+	"api_key.not_found":               "API key tidak ditemukan",
+	"api_key.revoke_failed":           "Gagal mencabut API key",
+	"api_key.revoked":                 "API key berhasil dicabut",
+	"api_key.permission_not_for_toko": "Permission %s tidak bisa dipakai API key toko",
+
+	// rbac
+	"rbac.permission_required":  "Akses ditolak, butuh permission %s",
+	"rbac.unknown_permission":   "Permission tidak dikenal: %s",
+	"rbac.permission_not_owned": "Kamu tidak punya permission %s",
+
+	// role
+	"role.self_admin_revoke": "Tidak bisa mencabut role admin milik sendiri",
+	"role.unknown":           "Role tidak dikenal: %s",
+
+	// oidc
+	"oidc.continue":           "Lanjutkan login di halaman SSO",
+	"oidc.link_continue":      "Kirim code dan state ke /api/identities/callback untuk menautkan akun SSO",
+	"oidc.cancelled":          "Login SSO dibatalkan",
+	"oidc.failed":             "Login SSO gagal",
+	"oidc.linked":             "Akun SSO berhasil ditautkan",
+	"oidc.list_failed":        "Gagal mengambil data akun SSO",
+	"oidc.unlinked":           "Tautan akun SSO dilepas",
+	"oidc.provider_failed":    "Tidak bisa memproses login dengan identity provider",
+	"oidc.invalid_state":      "sesi login SSO tidak valid atau sudah kedaluwarsa, silakan ulangi",
+	"oidc.disabled":           "login SSO tidak diaktifkan",
+	"oidc.invalid_id_token":   "id_token dari IdP tidak valid",
+	"oidc.email_missing":      "IdP tidak mengirim email, pastikan scope email diizinkan",
+	"oidc.email_taken":        "email sudah terdaftar, login dengan kata sandi lalu tautkan akun SSO dari menu profil",
+	"oidc.domain_not_allowed": "domain email ini tidak diizinkan login lewat SSO",
+	"oidc.identity_linked":    "akun SSO ini sudah ditautkan ke pengguna lain",
+	"oidc.identity_not_found": "akun SSO tidak ditemukan",
+
+	// user
+	"user.list_success":       "Berhasil mengambil semua user",
+	"user.get_success":        "Berhasil mengambil user",
+	"user.get_failed":         "Gagal mengambil data user",
+	"user.not_found":          "User tidak ditemukan",
+	"user.invalid_id":         "ID user tidak valid",
+	"user.update_failed":      "Gagal menyimpan perubahan",
+	"user.updated":            "User berhasil diperbarui",
+	"user.delete_failed":      "Gagal menghapus user",
+	"user.deleted":            "User berhasil dihapus",
+	"user.lock_status_failed": "Gagal membaca status kunci akun",
+	"user.unlock_failed":      "Gagal membuka kunci akun",
+	"user.unlocked":           "Kunci akun berhasil dibuka",
+	"user.edit_forbidden":     "Tidak bisa mengedit user lain",
+	"user.view_forbidden":     "Tidak boleh melihat data user lain",
+	"user.invalid_gender":     "jenis_kelamin harus 'Laki-laki' atau 'Perempuan'",
+
+	// toko
+	"toko.invalid_id":     "ID toko tidak valid",
+	"toko.not_found":      "Toko tidak ditemukan",
+	"toko.updated":        "Toko berhasil diperbarui",
+	"toko.deactivated":    "Toko berhasil dinonaktifkan",
+	"toko.edit_forbidden": "Tidak bisa mengubah toko milik orang lain",
+	"toko.photo_updated":  "Foto toko berhasil diupload, versi optimal sedang diproses",
+
+	// alamat
+	"alamat.not_found":        "Alamat tidak ditemukan",
+	"alamat.deleted":          "Alamat berhasil dihapus",
+	"alamat.view_forbidden":   "Tidak dapat melihat alamat orang lain",
+	"alamat.edit_forbidden":   "Tidak dapat mengubah alamat orang lain",
+	"alamat.delete_forbidden": "Tidak dapat menghapus alamat orang lain",
+
+	// category
+	"category.invalid_id":    "ID kategori tidak valid",
+	"category.not_found":     "Kategori tidak ditemukan",
+	"category.created":       "Kategori berhasil dibuat",
+	"category.create_failed": "Gagal membuat kategori",
+	"category.updated":       "Kategori berhasil diperbarui",
+	"category.update_failed": "Gagal memperbarui kategori",
+	"category.deleted":       "Kategori berhasil dihapus",
+	"category.delete_failed": "Gagal menghapus kategori",
+
+	// product
+	"product.invalid_id":              "ID produk tidak valid",
+	"product.not_found":               "Produk tidak ditemukan",
+	"product.created":                 "Produk berhasil dibuat",
+	"product.create_failed":           "Gagal membuat produk",
+	"product.updated":                 "Produk berhasil diperbarui",
+	"product.update_failed":           "Gagal memperbarui produk",
+	"product.deleted":                 "Produk berhasil dihapus",
+	"product.delete_failed":           "Gagal menghapus produk",
+	"product.no_toko":                 "User belum memiliki toko",
+	"product.edit_forbidden":          "Tidak dapat mengubah produk milik toko lain",
+	"product.delete_forbidden":        "Tidak dapat menghapus produk milik toko lain",
+	"product.reseller_price_too_high": "harga_reseller tidak boleh lebih besar dari harga_konsumen",
+	"product.invalid_price_range":     "harga_min tidak boleh lebih besar dari harga_max",
+	"product.has_variants":            "Stok dan harga produk bervarian diubah per varian",
+
+	// photo
+	"photo.invalid_id":        "ID foto tidak valid",
+	"photo.not_found":         "Foto tidak ditemukan",
+	"photo.uploaded":          "Foto berhasil diunggah",
+	"photo.upload_failed":     "Gagal mengunggah foto",
+	"photo.no_file":           "Tidak ada file di field foto",
+	"photo.single_file":       "Kirim tepat satu file di field foto",
+	"photo.too_large":         "Ukuran %s melebihi batas %s",
+	"photo.request_too_large": "Total ukuran upload terlalu besar",
+	"photo.unsupported_type":  "Format %s tidak didukung (%s), gunakan JPEG, PNG, WebP atau GIF",
+	"photo.limit_reached":     "Maksimal %d foto per produk",
+	"photo.order_mismatch":    "ids harus berisi semua foto produk tepat satu kali",
+	"photo.reordered":         "Urutan foto diperbarui",
+	"photo.primary_set":       "Foto utama diperbarui",
+	"photo.deleted":           "Foto berhasil dihapus",
+	"photo.delete_failed":     "Gagal menghapus foto",
+	"photo.edit_forbidden":    "Tidak dapat mengubah foto produk milik toko lain",
+
+	// variant
+	"variant.invalid_id":            "ID varian tidak valid",
+	"variant.not_found":             "Varian tidak ditemukan",
+	"variant.saved":                 "Varian produk berhasil disimpan",
+	"variant.save_failed":           "Gagal menyimpan varian produk",
+	"variant.updated":               "Varian berhasil diperbarui",
+	"variant.options_required":      "Varian butuh minimal satu opsi",
+	"variant.variants_required":     "Opsi butuh minimal satu varian",
+	"variant.duplicate_option":      "Opsi %s ditulis lebih dari sekali",
+	"variant.duplicate_value":       "Nilai %s ditulis lebih dari sekali di opsi %s",
+	"variant.invalid_combination":   "Varian %s harus memilih tepat satu nilai untuk setiap opsi",
+	"variant.unknown_value":         "Nilai %s tidak ada di opsi %s",
+	"variant.duplicate_combination": "Kombinasi %s ditulis lebih dari sekali",
+	"variant.duplicate_sku":         "SKU %s sudah dipakai",
+	"variant.repeated_sku":          "SKU %s dipakai lebih dari satu varian",
+
+	// transaction
+	"transaction.invalid_id":        "ID transaksi tidak valid",
+	"transaction.create_failed":     "Gagal membuat transaksi",
+	"transaction.created":           "Transaksi berhasil dibuat",
+	"transaction.get_failed":        "Gagal mengambil data transaksi",
+	"transaction.not_found":         "Transaksi tidak ditemukan",
+	"transaction.forbidden":         "Anda tidak memiliki akses ke transaksi ini",
+	"transaction.empty":             "Tidak ada produk yang dibeli",
+	"transaction.invalid_product":   "Produk tidak valid",
+	"transaction.out_of_stock":      "Stok tidak mencukupi: %s",
+	"transaction.variant_required":  "Pilih varian untuk produk %s",
+	"transaction.variant_not_found": "Varian tidak ditemukan untuk produk %s",
+
+	// wilayah
+	"wilayah.provinces_success": "Berhasil mengambil daftar provinsi",
+	"wilayah.provinces_failed":  "Gagal mengambil daftar provinsi",
+	"wilayah.cities_success":    "Berhasil mengambil daftar kota",
+	"wilayah.cities_failed":     "Gagal mengambil daftar kota",
+	"wilayah.province_success":  "Berhasil mengambil detail provinsi",
+	"wilayah.province_failed":   "Gagal mengambil detail provinsi",
+	"wilayah.city_success":      "Berhasil mengambil detail kota",
+	"wilayah.city_failed":       "Gagal mengambil detail kota",
+}
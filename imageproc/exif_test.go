@@ -0,0 +1,179 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// exifSegment segmen APP1 Exif berisi IFD0 dengan tag Orientation. Tag lain
+// ditaruh sebelumnya supaya pencarian tag ikut teruji.
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+2*12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 2)
+	// 0x010f Make, tipe ASCII, isinya tidak dibaca
+	order.PutUint16(tiff[10:], 0x010f)
+	order.PutUint16(tiff[12:], 2)
+	order.PutUint32(tiff[14:], 4)
+	copy(tiff[18:], "abc\x00")
+	// 0x0112 Orientation, tipe SHORT, 1 nilai
+	order.PutUint16(tiff[22:], 0x0112)
+	order.PutUint16(tiff[24:], 3)
+	order.PutUint32(tiff[26:], 1)
+	order.PutUint16(tiff[30:], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+// withExif menyisipkan APP0 JFIF lalu segmen Exif tepat setelah SOI
+func withExif(jpg []byte, segment []byte) []byte {
+	app0 := []byte{0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0, 1, 1, 0, 0, 1, 0, 1, 0, 0}
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, app0...)
+	out = append(out, segment...)
+	return append(out, jpg[2:]...)
+}
+
+func encodeJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	jpg := encodeJPEG(t, 8, 8)
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for o := 1; o <= 8; o++ {
+			if got := jpegOrientation(withExif(jpg, exifSegment(order, uint16(o)))); got != o {
+				t.Errorf("%v orientasi %d terbaca %d", order, o, got)
+			}
+		}
+	}
+
+	// semua kasus yang tidak bisa dibaca jatuh ke 1
+	truncated := withExif(jpg, exifSegment(binary.BigEndian, 6))
+	cases := map[string][]byte{
+		"tanpa exif":        jpg,
+		"bukan jpeg":        []byte("\x89PNG\r\n\x1a\n"),
+		"kosong":            nil,
+		"nilai di luar 1-8": withExif(jpg, exifSegment(binary.LittleEndian, 9)),
+		"terpotong":         truncated[:30],
+	}
+	for name, data := range cases {
+		if got := jpegOrientation(data); got != 1 {
+			t.Errorf("%s: terbaca %d, seharusnya 1", name, got)
+		}
+	}
+}
+
+// grid piksel sebagai id, dipakai untuk menyusun hasil yang diharapkan dari
+// dua operasi dasar saja (cermin horizontal dan putar 90° searah jarum jam)
+type grid [][]uint8
+
+func (g grid) flipH() grid {
+	out := make(grid, len(g))
+	for y, row := range g {
+		out[y] = make([]uint8, len(row))
+		for x := range row {
+			out[y][x] = row[len(row)-1-x]
+		}
+	}
+	return out
+}
+
+func (g grid) rotCW() grid {
+	h, w := len(g), len(g[0])
+	out := make(grid, w)
+	for y := range out {
+		out[y] = make([]uint8, h)
+		for x := range out[y] {
+			out[y][x] = g[h-1-x][y]
+		}
+	}
+	return out
+}
+
+func TestOrient(t *testing.T) {
+	// 3x2 supaya setiap putaran dan cermin menghasilkan susunan berbeda
+	src := grid{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+	// definisi EXIF: transformasi yang membuat gambar tegak kembali
+	want := map[int]grid{
+		1: src,
+		2: src.flipH(),
+		3: src.rotCW().rotCW(),
+		4: src.rotCW().rotCW().flipH(),
+		5: src.rotCW().flipH(), // transpose
+		6: src.rotCW(),
+		7: src.flipH().rotCW(), // transverse
+		8: src.rotCW().rotCW().rotCW(),
+	}
+	// sanity check fixture: transpose memindahkan (x,y) ke (y,x)
+	if want[5][2][1] != src[1][2] {
+		t.Fatalf("fixture transpose salah")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y, row := range src {
+		for x, v := range row {
+			img.SetRGBA(x, y, color.RGBA{v, v, v, 0xff})
+		}
+	}
+	for o := 1; o <= 8; o++ {
+		got := orient(img, o)
+		exp := want[o]
+		if got.Rect.Dx() != len(exp[0]) || got.Rect.Dy() != len(exp) {
+			t.Errorf("orientasi %d: ukuran %v, seharusnya %dx%d", o, got.Rect.Size(), len(exp[0]), len(exp))
+			continue
+		}
+		for y, row := range exp {
+			for x, v := range row {
+				if p := got.RGBAAt(x, y).R; p != v {
+					t.Errorf("orientasi %d: piksel (%d,%d) = %d, seharusnya %d", o, x, y, p, v)
+				}
+			}
+		}
+	}
+}
+
+// Process menukar lebar dan tinggi untuk orientasi 5-8 dan tetap membatasi
+// sisi terpanjang setelah diputar
+func TestProcessAppliesOrientation(t *testing.T) {
+	jpg := encodeJPEG(t, 40, 20)
+	p := Processor{Specs: []Spec{{Name: VariantThumb, MaxSize: 10}, {Name: VariantWeb, MaxSize: 100}}, Quality: 90}
+	for o := 1; o <= 8; o++ {
+		outs, err := p.Process(bytes.NewReader(withExif(jpg, exifSegment(binary.BigEndian, uint16(o)))))
+		if err != nil {
+			t.Fatalf("orientasi %d: %v", o, err)
+		}
+		wantThumb, wantWeb := image.Pt(10, 5), image.Pt(40, 20)
+		if o >= 5 {
+			wantThumb, wantWeb = image.Pt(5, 10), image.Pt(20, 40)
+		}
+		if got := image.Pt(outs[0].Width, outs[0].Height); got != wantThumb {
+			t.Errorf("orientasi %d thumb: %v, seharusnya %v", o, got, wantThumb)
+		}
+		if got := image.Pt(outs[1].Width, outs[1].Height); got != wantWeb {
+			t.Errorf("orientasi %d web: %v, seharusnya %v", o, got, wantWeb)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package imageproc membuat varian foto (thumbnail dan ukuran web) dari file
+// upload: orientasi EXIF diterapkan, metadata dibuang karena gambar di-encode
+// ulang, lalu disimpan sebagai JPEG atau WebP lossless untuk gambar
+// transparan. Prosesnya berjalan di background lewat Queue.
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go-crud/config"
+	"image"
+	"image/jpeg"
+	"io"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// nama varian
+const (
+	VariantThumb = "thumb"
+	VariantWeb   = "web"
+)
+
+// maxPixels batas jumlah piksel gambar asli, supaya file kecil berisi gambar
+// raksasa (decompression bomb) tidak menghabiskan memori
+const maxPixels = 50_000_000
+
+// ErrInvalidImage file bukan gambar yang bisa dibaca; memproses ulang tidak
+// akan berhasil
+var ErrInvalidImage = errors.New("imageproc: gambar tidak bisa dibaca")
+
+// Spec satu varian: sisi terpanjang paling besar MaxSize piksel
+type Spec struct {
+	Name    string
+	MaxSize int
+}
+
+// Output hasil satu varian
+type Output struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	// Ext ekstensi untuk key storage, termasuk titik
+	Ext           string
+	Width, Height int
+}
+
+type Processor struct {
+	Specs []Spec
+	// Quality kualitas JPEG 1-100
+	Quality int
+}
+
+func NewProcessor(cfg config.ImageConfig) Processor {
+	return Processor{
+		Specs: []Spec{
+			{Name: VariantThumb, MaxSize: cfg.ThumbSize},
+			{Name: VariantWeb, MaxSize: cfg.WebSize},
+		},
+		Quality: cfg.Quality,
+	}
+}
+
+// Process membaca gambar dari r dan membuat semua varian Specs
+func (p Processor) Process(r io.Reader) ([]Output, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: ukuran %dx%d", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	orientation := jpegOrientation(data)
+
+	outputs := make([]Output, 0, len(p.Specs))
+	for _, spec := range p.Specs {
+		img := orient(resize(src, spec.MaxSize, orientation), orientation)
+		out, err := p.encode(img)
+		if err != nil {
+			return nil, err
+		}
+		out.Name = spec.Name
+		outputs = append(outputs, out)
+	}
+	return outputs, nil
+}
+
+// resize mengecilkan src supaya sisi terpanjang setelah diputar sesuai
+// orientation paling besar maxSize. Gambar kecil tidak diperbesar. Diskalakan
+// sebelum diputar supaya pemutaran hanya menyentuh gambar yang sudah kecil.
+func resize(src image.Image, maxSize, orientation int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if longest := max(w, h); longest > maxSize {
+		// pembulatan, minimal 1 piksel
+		w = max(1, (w*maxSize+longest/2)/longest)
+		h = max(1, (h*maxSize+longest/2)/longest)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w == b.Dx() && h == b.Dy() {
+		draw.Draw(dst, dst.Rect, src, b.Min, draw.Src)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Rect, src, b, draw.Src, nil)
+	}
+	return dst
+}
+
+func (p Processor) encode(img *image.RGBA) (Output, error) {
+	var buf bytes.Buffer
+	out := Output{Width: img.Rect.Dx(), Height: img.Rect.Dy()}
+	if img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.Quality}); err != nil {
+			return out, err
+		}
+		out.ContentType, out.Ext = "image/jpeg", ".jpg"
+	} else {
+		// JPEG tidak punya alpha
+		if err := EncodeWebP(&buf, img); err != nil {
+			return out, err
+		}
+		out.ContentType, out.Ext = "image/webp", ".webp"
+	}
+	out.Data = buf.Bytes()
+	return out, nil
+}
@@ -0,0 +1,261 @@
+package imageproc
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// ================================
+// 🔹 PREFIX CODE (HUFFMAN) VP8L
+// ================================
+
+// prefixCode kode kanonik untuk satu alfabet. bits sudah dibalik karena
+// bitstream VP8L dibaca dari bit terendah.
+type prefixCode struct {
+	lengths []uint8
+	bits    []uint16
+}
+
+// write menulis kode symbol; simbol tunggal panjangnya 0 bit
+func (c *prefixCode) write(w *bitWriter, symbol int) {
+	w.write(uint32(c.bits[symbol]), uint(c.lengths[symbol]))
+}
+
+// codeLengths panjang kode Huffman tiap simbol, paling panjang maxLen.
+// Kalau pohonnya terlalu dalam, hitungan kecil dinaikkan bertahap supaya
+// distribusinya lebih rata (cara yang sama dipakai libwebp).
+func codeLengths(counts []int, maxLen int) []uint8 {
+	lengths := make([]uint8, len(counts))
+	for minCount := 1; ; minCount *= 2 {
+		adjusted := make([]int, len(counts))
+		for i, c := range counts {
+			if c > 0 {
+				adjusted[i] = max(c, minCount)
+			}
+		}
+		if huffmanDepths(adjusted, lengths) <= maxLen {
+			return lengths
+		}
+	}
+}
+
+type huffNode struct {
+	count       int
+	symbol      int // -1 untuk node gabungan
+	left, right *huffNode
+}
+
+type huffHeap []*huffNode
+
+func (h huffHeap) Len() int { return len(h) }
+func (h huffHeap) Less(i, j int) bool {
+	// symbol sebagai tie-break supaya hasilnya deterministik
+	return h[i].count < h[j].count || (h[i].count == h[j].count && h[i].symbol < h[j].symbol)
+}
+func (h huffHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *huffHeap) Push(x any)   { *h = append(*h, x.(*huffNode)) }
+func (h *huffHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// huffmanDepths mengisi lengths dari pohon Huffman biasa dan mengembalikan
+// kedalaman maksimumnya
+func huffmanDepths(counts []int, lengths []uint8) int {
+	clear(lengths)
+	h := huffHeap{}
+	for i, c := range counts {
+		if c > 0 {
+			h = append(h, &huffNode{count: c, symbol: i})
+		}
+	}
+	if len(h) == 1 {
+		lengths[h[0].symbol] = 1
+		return 1
+	}
+	heap.Init(&h)
+	for h.Len() > 1 {
+		a := heap.Pop(&h).(*huffNode)
+		b := heap.Pop(&h).(*huffNode)
+		heap.Push(&h, &huffNode{count: a.count + b.count, symbol: -1, left: a, right: b})
+	}
+
+	deepest := 0
+	var walk func(n *huffNode, depth int)
+	walk = func(n *huffNode, depth int) {
+		if n == nil {
+			return
+		}
+		if n.symbol >= 0 {
+			lengths[n.symbol] = uint8(depth)
+			deepest = max(deepest, depth)
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	if h.Len() == 1 {
+		walk(h[0], 0)
+	}
+	return deepest
+}
+
+// canonical membentuk kode kanonik (seperti DEFLATE) dari panjang kode
+func canonical(lengths []uint8) prefixCode {
+	var blCount [16]int
+	for _, l := range lengths {
+		if l > 0 {
+			blCount[l]++
+		}
+	}
+	var next [16]int
+	code := 0
+	for l := 1; l < 16; l++ {
+		code = (code + blCount[l-1]) << 1
+		next[l] = code
+	}
+
+	c := prefixCode{lengths: lengths, bits: make([]uint16, len(lengths))}
+	for sym, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c.bits[sym] = reverseBits(uint16(next[l]), l)
+		next[l]++
+	}
+	return c
+}
+
+func reverseBits(v uint16, n uint8) uint16 {
+	var r uint16
+	for i := uint8(0); i < n; i++ {
+		r = r<<1 | v&1
+		v >>= 1
+	}
+	return r
+}
+
+// urutan panjang kode untuk code length code, dari spesifikasi VP8L
+var codeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// lengthToken satu simbol code length code (0-18) beserta extra bit-nya
+type lengthToken struct {
+	symbol     int
+	extra      uint32
+	extraWidth uint
+}
+
+// tokenize menyandikan panjang kode dengan run-length: 16 mengulang panjang
+// sebelumnya 3-6 kali, 17 dan 18 menulis 3-10 dan 11-138 nol
+func tokenize(lengths []uint8) []lengthToken {
+	var tokens []lengthToken
+	for i := 0; i < len(lengths); {
+		l := lengths[i]
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == l {
+			run++
+		}
+		i += run
+
+		if l == 0 {
+			for run >= 11 {
+				n := min(run, 138)
+				tokens = append(tokens, lengthToken{18, uint32(n - 11), 7})
+				run -= n
+			}
+			if run >= 3 {
+				tokens = append(tokens, lengthToken{17, uint32(run - 3), 3})
+				run = 0
+			}
+		} else {
+			tokens = append(tokens, lengthToken{symbol: int(l)})
+			run--
+			for run >= 3 {
+				n := min(run, 6)
+				tokens = append(tokens, lengthToken{16, uint32(n - 3), 2})
+				run -= n
+			}
+		}
+		for ; run > 0; run-- {
+			tokens = append(tokens, lengthToken{symbol: int(l)})
+		}
+	}
+	return tokens
+}
+
+// writePrefixCode membangun kode dari histogram counts, menulis definisinya
+// ke w lalu mengembalikannya untuk menulis simbol
+func writePrefixCode(w *bitWriter, counts []int) prefixCode {
+	var used []int
+	for sym, c := range counts {
+		if c > 0 {
+			used = append(used, sym)
+		}
+	}
+	if len(used) == 0 {
+		// alfabet tidak terpakai tetap harus punya kode
+		used = []int{0}
+	}
+
+	// simple code: 1-2 simbol < 256, simbol tunggal ditulis 0 bit
+	if len(used) <= 2 && used[len(used)-1] < 256 {
+		w.write(1, 1)
+		w.write(uint32(len(used)-1), 1)
+		if used[0] <= 1 {
+			w.write(0, 1)
+			w.write(uint32(used[0]), 1)
+		} else {
+			w.write(1, 1)
+			w.write(uint32(used[0]), 8)
+		}
+		lengths := make([]uint8, len(counts))
+		if len(used) == 2 {
+			w.write(uint32(used[1]), 8)
+			lengths[used[0]], lengths[used[1]] = 1, 1
+		}
+		return canonical(lengths)
+	}
+
+	lengths := codeLengths(counts, 15)
+	if len(used) == 1 {
+		// satu simbol di kode normal juga dibaca 0 bit oleh decoder
+		lengths[used[0]] = 1
+	}
+
+	tokens := tokenize(lengths)
+	var tokenCounts [19]int
+	for _, t := range tokens {
+		tokenCounts[t.symbol]++
+	}
+	clLengths := codeLengths(tokenCounts[:], 7)
+	if i := slices.Index(tokenCounts[:], len(tokens)); i >= 0 {
+		// satu simbol dibaca 0 bit oleh decoder, padahal di sini panjangnya
+		// 1; tambah simbol kedua supaya pohonnya lengkap
+		clLengths[(i+1)%len(clLengths)] = 1
+	}
+	clCode := canonical(clLengths)
+
+	n := len(codeLengthOrder)
+	for n > 4 && clLengths[codeLengthOrder[n-1]] == 0 {
+		n--
+	}
+	w.write(0, 1)
+	w.write(uint32(n-4), 4)
+	for _, sym := range codeLengthOrder[:n] {
+		w.write(uint32(clLengths[sym]), 3)
+	}
+	// max_symbol tidak dipakai: semua panjang kode ditulis
+	w.write(0, 1)
+	for _, t := range tokens {
+		clCode.write(w, t.symbol)
+		w.write(t.extra, t.extraWidth)
+	}
+
+	code := canonical(lengths)
+	if len(used) == 1 {
+		code.lengths[used[0]] = 0
+	}
+	return code
+}
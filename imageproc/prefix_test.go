@@ -0,0 +1,45 @@
+package imageproc
+
+import "testing"
+
+// Panjang kode tidak boleh melewati maxLen dan harus membentuk kode prefix
+// yang lengkap (jumlah 2^-len tepat 1), kalau tidak decoder menolaknya
+func TestCodeLengthsLimit(t *testing.T) {
+	// deret Fibonacci membuat pohon Huffman sedalam mungkin
+	fib := make([]int, 40)
+	fib[0], fib[1] = 1, 1
+	for i := 2; i < len(fib); i++ {
+		fib[i] = fib[i-1] + fib[i-2]
+	}
+	cases := []struct {
+		name   string
+		counts []int
+		maxLen int
+	}{
+		{"fibonacci", fib, 15},
+		{"fibonacci batas kecil", fib[:20], 7},
+		{"rata", []int{5, 5, 5, 5, 5, 5, 5, 5}, 15},
+		{"dengan nol", []int{0, 9, 0, 1, 1, 0, 100}, 15},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lengths := codeLengths(tc.counts, tc.maxLen)
+			var kraft float64
+			for i, l := range lengths {
+				if tc.counts[i] == 0 {
+					if l != 0 {
+						t.Errorf("simbol %d tidak dipakai tapi panjangnya %d", i, l)
+					}
+					continue
+				}
+				if l == 0 || int(l) > tc.maxLen {
+					t.Errorf("simbol %d panjangnya %d, batas %d", i, l, tc.maxLen)
+				}
+				kraft += 1 / float64(uint64(1)<<l)
+			}
+			if kraft != 1 {
+				t.Errorf("jumlah Kraft %v, seharusnya 1", kraft)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package imageproc
+
+import (
+	"context"
+	"go-crud/config"
+	"go-crud/lifecycle"
+	"log"
+	"sync"
+	"time"
+)
+
+// ================================
+// 🔹 ANTREAN PEMROSESAN
+// ================================
+
+// jenis foto yang diproses
+const (
+	JobFotoProduk = "foto_produk"
+	JobToko       = "toko"
+)
+
+// Job satu foto yang perlu dibuatkan varian
+type Job struct {
+	Kind string
+	ID   uint64
+}
+
+// Handler memproses Job. Diimplementasikan services.ImageService yang
+// membaca file asli dan menyimpan varian ke database.
+type Handler interface {
+	Process(ctx context.Context, job Job) error
+	// Pending foto yang belum diproses, paling banyak limit
+	Pending(ctx context.Context, limit int) ([]Job, error)
+}
+
+// status job di Queue
+const (
+	jobQueued = iota + 1
+	jobRunning
+	// di-enqueue lagi saat sedang diproses (misal foto toko diganti),
+	// diantrekan ulang setelah selesai
+	jobRerun
+)
+
+// Queue antrean job di memori. Antrean tidak disimpan: job yang hilang karena
+// antrean penuh atau server berhenti diambil lagi dari database oleh sweep.
+type Queue struct {
+	jobs  chan Job
+	mu    sync.Mutex
+	state map[Job]int
+}
+
+func NewQueue(size int) *Queue {
+	return &Queue{jobs: make(chan Job, size), state: map[Job]int{}}
+}
+
+// Default dipakai service, di-set oleh Init saat server start
+var Default = NewQueue(256)
+
+func Init(cfg config.ImageConfig) {
+	Default = NewQueue(cfg.QueueSize)
+}
+
+// Enqueue tidak pernah menunggu. Job yang sudah ada di antrean tidak
+// ditambahkan lagi.
+func (q *Queue) Enqueue(job Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	switch q.state[job] {
+	case jobQueued, jobRerun:
+		return
+	case jobRunning:
+		q.state[job] = jobRerun
+		return
+	}
+	select {
+	case q.jobs <- job:
+		q.state[job] = jobQueued
+	default:
+		log.Printf("antrean foto penuh, %s #%d diproses oleh sweep berikutnya", job.Kind, job.ID)
+	}
+}
+
+// Start menjalankan workers worker dan sweep yang mencari foto pending di
+// database setiap interval (juga sekali saat start)
+func (q *Queue) Start(workers int, interval time.Duration, h Handler) {
+	for i := 0; i < workers; i++ {
+		lifecycle.Go("image-worker", func(ctx context.Context) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-q.jobs:
+					q.run(ctx, h, job)
+				}
+			}
+		})
+	}
+
+	lifecycle.Go("image-sweep", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			q.sweep(ctx, h)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+func (q *Queue) run(ctx context.Context, h Handler, job Job) {
+	q.mu.Lock()
+	q.state[job] = jobRunning
+	q.mu.Unlock()
+
+	if err := h.Process(ctx, job); err != nil && ctx.Err() == nil {
+		log.Printf("gagal memproses foto %s #%d: %v", job.Kind, job.ID, err)
+	}
+
+	q.mu.Lock()
+	rerun := q.state[job] == jobRerun
+	delete(q.state, job)
+	q.mu.Unlock()
+	if rerun {
+		q.Enqueue(job)
+	}
+}
+
+func (q *Queue) sweep(ctx context.Context, h Handler) {
+	jobs, err := h.Pending(ctx, cap(q.jobs))
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("gagal mencari foto yang belum diproses: %v", err)
+		}
+		return
+	}
+	for _, job := range jobs {
+		q.Enqueue(job)
+	}
+}
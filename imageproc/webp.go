@@ -0,0 +1,352 @@
+package imageproc
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+	"math/bits"
+)
+
+// ================================
+// 🔹 ENCODER WEBP LOSSLESS (VP8L)
+// ================================
+// Dipakai untuk gambar transparan, yang tidak bisa disimpan sebagai JPEG.
+// Encoder dibuat sederhana: transform subtract-green dan predictor (mode
+// dipilih per blok), backward reference hanya ke piksel kiri dan atas, tanpa
+// color cache. Hasilnya lebih besar dari encoder libwebp tetapi tetap jauh
+// lebih kecil dari PNG untuk logo dan gambar dengan area datar.
+
+// batas dimensi VP8L (14 bit)
+const webpMaxSize = 1 << 14
+
+// blok predictor 16x16 piksel
+const predictorBits = 4
+
+// mode predictor yang dicoba per blok: kiri, atas, Select
+var predictorModes = []int{1, 2, 11}
+
+// EncodeWebP menulis img sebagai WebP lossless
+func EncodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > webpMaxSize || height > webpMaxSize {
+		return errors.New("imageproc: ukuran gambar di luar batas WebP")
+	}
+
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || nrgba.Rect.Min != (image.Point{}) {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(nrgba, nrgba.Rect, img, b.Min, draw.Src)
+	}
+	argb := make([]uint32, width*height)
+	alphaUsed := uint32(0)
+	for y := 0; y < height; y++ {
+		row := nrgba.Pix[y*nrgba.Stride:]
+		for x := 0; x < width; x++ {
+			p := row[x*4 : x*4+4]
+			argb[y*width+x] = uint32(p[3])<<24 | uint32(p[0])<<16 | uint32(p[1])<<8 | uint32(p[2])
+			if p[3] != 0xff {
+				alphaUsed = 1
+			}
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8) // signature VP8L
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	bw.write(alphaUsed, 1)
+	bw.write(0, 3) // versi
+
+	// transform ditulis sesuai urutan penerapannya di encoder; decoder
+	// membaliknya dari yang terakhir
+	bw.write(1, 1)
+	bw.write(2, 2) // SUBTRACT_GREEN
+	subtractGreen(argb)
+
+	bw.write(1, 1)
+	bw.write(0, 2) // PREDICTOR
+	bw.write(predictorBits-2, 3)
+	modes, tilesX := choosePredictors(argb, width, height)
+	writeEntropyImage(bw, modes, tilesX, false)
+	argb = predictResiduals(argb, width, height, modes, tilesX)
+
+	bw.write(0, 1) // tidak ada transform lagi
+	writeEntropyImage(bw, argb, width, true)
+	bw.flush()
+
+	return writeRIFF(w, bw.buf)
+}
+
+func writeRIFF(w io.Writer, data []byte) error {
+	pad := len(data) & 1
+	header := make([]byte, 0, 20)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(4+8+len(data)+pad))
+	header = append(header, "WEBPVP8L"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(data)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if pad == 1 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// bitWriter menulis bit mulai dari bit terendah tiap byte
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (w *bitWriter) write(v uint32, n uint) {
+	w.acc |= uint64(v) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+func (w *bitWriter) flush() {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nbits = 0, 0
+	}
+}
+
+// ================================
+// 🔹 TRANSFORM
+// ================================
+
+func subtractGreen(argb []uint32) {
+	for i, p := range argb {
+		g := (p >> 8) & 0xff
+		r := ((p >> 16) - g) & 0xff
+		b := (p - g) & 0xff
+		argb[i] = p&0xff00ff00 | r<<16 | b
+	}
+}
+
+// subPixels a - b per channel (mod 256)
+func subPixels(a, b uint32) uint32 {
+	alphaGreen := 0x00ff00ff + (a & 0xff00ff00) - (b & 0xff00ff00)
+	redBlue := 0xff00ff00 + (a & 0x00ff00ff) - (b & 0x00ff00ff)
+	return alphaGreen&0xff00ff00 | redBlue&0x00ff00ff
+}
+
+// predict prediksi piksel (x, y) dari piksel asli. Baris pertama dan kolom
+// pertama punya aturan tetap, tidak tergantung mode.
+func predict(argb []uint32, width, x, y, mode int) uint32 {
+	i := y*width + x
+	switch {
+	case x == 0 && y == 0:
+		return 0xff000000
+	case y == 0:
+		return argb[i-1]
+	case x == 0:
+		return argb[i-width]
+	}
+	left, top := argb[i-1], argb[i-width]
+	switch mode {
+	case 1:
+		return left
+	case 2:
+		return top
+	default: // 11: Select
+		topLeft := argb[i-width-1]
+		// |prediksi - kiri| = |atas - kiri atas| dan sebaliknya
+		if channelDistance(top, topLeft) < channelDistance(left, topLeft) {
+			return left
+		}
+		return top
+	}
+}
+
+func channelDistance(a, b uint32) int {
+	d := 0
+	for shift := 0; shift < 32; shift += 8 {
+		d += abs(int(a>>shift&0xff) - int(b>>shift&0xff))
+	}
+	return d
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// residualCost perkiraan biaya residual: makin dekat ke 0 makin murah
+func residualCost(r uint32) int {
+	cost := 0
+	for shift := 0; shift < 32; shift += 8 {
+		c := int(r >> shift & 0xff)
+		cost += min(c, 256-c)
+	}
+	return cost
+}
+
+// choosePredictors memilih mode dengan residual terkecil untuk tiap blok.
+// Mode disimpan di channel hijau sub-image.
+func choosePredictors(argb []uint32, width, height int) ([]uint32, int) {
+	size := 1 << predictorBits
+	tilesX := (width + size - 1) / size
+	tilesY := (height + size - 1) / size
+	modes := make([]uint32, tilesX*tilesY)
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			best, bestCost := predictorModes[0], -1
+			for _, mode := range predictorModes {
+				cost := 0
+				for y := ty * size; y < min((ty+1)*size, height); y++ {
+					for x := tx * size; x < min((tx+1)*size, width); x++ {
+						cost += residualCost(subPixels(argb[y*width+x], predict(argb, width, x, y, mode)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+			modes[ty*tilesX+tx] = 0xff000000 | uint32(best)<<8
+		}
+	}
+	return modes, tilesX
+}
+
+func predictResiduals(argb []uint32, width, height int, modes []uint32, tilesX int) []uint32 {
+	residuals := make([]uint32, len(argb))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			mode := int(modes[(y>>predictorBits)*tilesX+x>>predictorBits] >> 8 & 0xff)
+			residuals[y*width+x] = subPixels(argb[y*width+x], predict(argb, width, x, y, mode))
+		}
+	}
+	return residuals
+}
+
+// ================================
+// 🔹 ENTROPY CODING
+// ================================
+
+const (
+	maxCopyLength    = 4096
+	minCopyLength    = 3
+	lengthPrefixes   = 24
+	distancePrefixes = 40
+	// kode jarak 1 dan 2 di tabel jarak VP8L: piksel atas dan piksel kiri
+	distanceTop  = 1
+	distanceLeft = 2
+)
+
+// token literal (length 0) atau salinan length piksel dari distance
+type token struct {
+	argb     uint32
+	length   int
+	distance int
+}
+
+// prefixEncode nilai >= 1 menjadi kode prefix dan extra bit LZ77 VP8L
+func prefixEncode(v int) (code int, extraBits uint, extra uint32) {
+	if v <= 4 {
+		return v - 1, 0, 0
+	}
+	d := v - 1
+	highest := bits.Len(uint(d)) - 1
+	second := (d >> (highest - 1)) & 1
+	extraBits = uint(highest - 1)
+	return 2*highest + second, extraBits, uint32(d) & (1<<extraBits - 1)
+}
+
+func backwardRefs(argb []uint32, width int) []token {
+	tokens := make([]token, 0, len(argb)/2)
+	for i := 0; i < len(argb); {
+		length, distance := 0, 0
+		if i >= 1 {
+			k := 0
+			for i+k < len(argb) && k < maxCopyLength && argb[i+k] == argb[i-1] {
+				k++
+			}
+			length, distance = k, distanceLeft
+		}
+		if i >= width {
+			k := 0
+			for i+k < len(argb) && k < maxCopyLength && argb[i+k] == argb[i+k-width] {
+				k++
+			}
+			if k > length {
+				length, distance = k, distanceTop
+			}
+		}
+		if length >= minCopyLength {
+			tokens = append(tokens, token{length: length, distance: distance})
+			i += length
+			continue
+		}
+		tokens = append(tokens, token{argb: argb[i]})
+		i++
+	}
+	return tokens
+}
+
+// writeEntropyImage menulis gambar ARGB dengan lima prefix code (hijau +
+// panjang salinan, merah, biru, alpha, jarak). Sub-image (mode predictor)
+// tidak punya bit meta prefix code.
+func writeEntropyImage(bw *bitWriter, argb []uint32, width int, main bool) {
+	bw.write(0, 1) // tanpa color cache
+	if main {
+		bw.write(0, 1) // satu grup prefix code untuk seluruh gambar
+	}
+
+	tokens := backwardRefs(argb, width)
+	green := make([]int, 256+lengthPrefixes)
+	red := make([]int, 256)
+	blue := make([]int, 256)
+	alpha := make([]int, 256)
+	dist := make([]int, distancePrefixes)
+	for _, t := range tokens {
+		if t.length > 0 {
+			code, _, _ := prefixEncode(t.length)
+			green[256+code]++
+			code, _, _ = prefixEncode(t.distance)
+			dist[code]++
+			continue
+		}
+		green[t.argb>>8&0xff]++
+		red[t.argb>>16&0xff]++
+		blue[t.argb&0xff]++
+		alpha[t.argb>>24]++
+	}
+
+	greenCode := writePrefixCode(bw, green)
+	redCode := writePrefixCode(bw, red)
+	blueCode := writePrefixCode(bw, blue)
+	alphaCode := writePrefixCode(bw, alpha)
+	distCode := writePrefixCode(bw, dist)
+
+	for _, t := range tokens {
+		if t.length > 0 {
+			code, n, extra := prefixEncode(t.length)
+			greenCode.write(bw, 256+code)
+			bw.write(extra, n)
+			code, n, extra = prefixEncode(t.distance)
+			distCode.write(bw, code)
+			bw.write(extra, n)
+			continue
+		}
+		greenCode.write(bw, int(t.argb>>8&0xff))
+		redCode.write(bw, int(t.argb>>16&0xff))
+		blueCode.write(bw, int(t.argb&0xff))
+		alphaCode.write(bw, int(t.argb>>24))
+	}
+}
@@ -0,0 +1,133 @@
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+// Hasil EncodeWebP harus bisa dibaca decoder x/image/webp dan pikselnya
+// sama persis dengan input (lossless, termasuk RGB piksel transparan)
+func TestEncodeWebPRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	noise := func(w, h int, alpha bool) *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		rng.Read(img.Pix)
+		if !alpha {
+			for i := 3; i < len(img.Pix); i += 4 {
+				img.Pix[i] = 0xff
+			}
+		}
+		return img
+	}
+	gradient := func(w, h int) *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				img.SetNRGBA(x, y, color.NRGBA{uint8(x * 7), uint8(y * 5), uint8(x + y), uint8(255 - x)})
+			}
+		}
+		return img
+	}
+	flat := func(w, h int, c color.NRGBA) *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(img, img.Rect, image.NewUniform(c), image.Point{}, draw.Src)
+		return img
+	}
+	// beberapa warna saja dengan frekuensi sangat timpang, supaya pohon
+	// Huffman-nya dalam dan batas panjang kode ikut teruji
+	skewed := func(w, h int) *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for i := 0; i < w*h; i++ {
+			v := uint8(0)
+			for v < 30 && rng.Intn(2) == 0 {
+				v++
+			}
+			copy(img.Pix[i*4:], []uint8{v, v * 3, v * 5, 0xff})
+		}
+		return img
+	}
+
+	cases := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{"1x1", noise(1, 1, true)},
+		{"opaque 16x16", noise(16, 16, false)},
+		{"noise lintas blok 17x33", noise(17, 33, true)},
+		{"noise opaque 65x3", noise(65, 3, false)},
+		{"gradien alpha 40x23", gradient(40, 23)},
+		{"flat opaque", flat(31, 9, color.NRGBA{10, 200, 30, 0xff})},
+		{"flat transparan", flat(5, 50, color.NRGBA{10, 200, 30, 0})},
+		{"frekuensi timpang", skewed(128, 96)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeWebP(&buf, tc.img); err != nil {
+				t.Fatalf("EncodeWebP: %v", err)
+			}
+			decoded, err := webp.Decode(&buf)
+			if err != nil {
+				t.Fatalf("webp.Decode: %v", err)
+			}
+			if decoded.Bounds() != tc.img.Rect {
+				t.Fatalf("ukuran %v, seharusnya %v", decoded.Bounds(), tc.img.Rect)
+			}
+			// VP8L selalu didecode ke NRGBA, jadi RGB piksel transparan ikut terbandingkan
+			got, ok := decoded.(*image.NRGBA)
+			if !ok {
+				t.Fatalf("decoder mengembalikan %T, seharusnya *image.NRGBA", decoded)
+			}
+			for y := 0; y < tc.img.Rect.Dy(); y++ {
+				for x := 0; x < tc.img.Rect.Dx(); x++ {
+					if g, w := got.NRGBAAt(x, y), tc.img.NRGBAAt(x, y); g != w {
+						t.Fatalf("piksel (%d,%d) = %v, seharusnya %v", x, y, g, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Input non-NRGBA dikonversi dulu, hasilnya tetap sama dengan warna aslinya
+func TestEncodeWebPConvertsRGBA(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(0, 0, color.NRGBA{200, 100, 50, 0x80})
+	src.Set(2, 1, color.NRGBA{1, 2, 3, 0xff})
+
+	var buf bytes.Buffer
+	if err := EncodeWebP(&buf, src); err != nil {
+		t.Fatalf("EncodeWebP: %v", err)
+	}
+	decoded, err := webp.Decode(&buf)
+	if err != nil {
+		t.Fatalf("webp.Decode: %v", err)
+	}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			want := color.NRGBAModel.Convert(src.At(x, y))
+			if got := color.NRGBAModel.Convert(decoded.At(x, y)); got != want {
+				t.Errorf("piksel (%d,%d) = %v, seharusnya %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestEncodeWebPRejectsSize(t *testing.T) {
+	for _, r := range []image.Rectangle{
+		image.Rect(0, 0, 0, 10),
+		image.Rect(0, 0, 16385, 1),
+		image.Rect(0, 0, 1, 16385),
+	} {
+		if err := EncodeWebP(&bytes.Buffer{}, image.NewNRGBA(r)); err == nil {
+			t.Errorf("ukuran %v seharusnya ditolak", r.Size())
+		}
+	}
+}
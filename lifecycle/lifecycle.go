@@ -0,0 +1,85 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// ================================
+// ♻️ Lifecycle Aplikasi
+// ================================
+// Worker background dijalankan lewat Go() dan dihentikan saat Shutdown().
+// Resource lain (koneksi database, cache, dsb) didaftarkan lewat OnShutdown()
+// dan ditutup dengan urutan terbalik dari pendaftarannya.
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+var (
+	mu      sync.Mutex
+	hooks   []hook
+	workers sync.WaitGroup
+
+	baseCtx, cancelWorkers = context.WithCancel(context.Background())
+)
+
+// OnShutdown mendaftarkan fungsi yang dipanggil saat aplikasi berhenti
+func OnShutdown(name string, stop func(ctx context.Context) error) {
+	mu.Lock()
+	defer mu.Unlock()
+	hooks = append(hooks, hook{name: name, stop: stop})
+}
+
+// Go menjalankan worker background. ctx akan dibatalkan saat Shutdown dan
+// Shutdown menunggu sampai worker selesai (atau batas waktu habis).
+func Go(name string, worker func(ctx context.Context)) {
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("worker %s panic: %v", name, r)
+			}
+		}()
+		worker(baseCtx)
+	}()
+}
+
+// Context dibatalkan ketika Shutdown dimulai
+func Context() context.Context {
+	return baseCtx
+}
+
+// Shutdown menghentikan worker lalu menjalankan hook dari yang terakhir didaftarkan
+func Shutdown(ctx context.Context) error {
+	var errs []error
+
+	cancelWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("menunggu worker background: %v", ctx.Err()))
+	}
+
+	mu.Lock()
+	list := hooks
+	hooks = nil
+	mu.Unlock()
+
+	for i := len(list) - 1; i >= 0; i-- {
+		if err := list[i].stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", list[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"go-crud/config"
+	"go-crud/lifecycle"
+	"log"
+	"time"
+)
+
+// Message adalah email teks sederhana
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer diimplementasikan oleh SMTPMailer (produksi), FileMailer dan LogMailer (offline)
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Default dipakai controller, di-set oleh Init saat server start
+var Default Mailer = LogMailer{}
+
+// New membuat Mailer sesuai mail.driver
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg), nil
+	case "file":
+		return NewFileMailer(cfg.FileDir, cfg.From)
+	case "log":
+		return LogMailer{From: cfg.From}, nil
+	default:
+		return nil, fmt.Errorf("mail driver tidak dikenal: %s", cfg.Driver)
+	}
+}
+
+func Init(cfg config.MailConfig) error {
+	m, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	Default = m
+	return nil
+}
+
+// SendAsync mengirim email di background supaya request tidak menunggu SMTP.
+// Worker didaftarkan ke lifecycle sehingga email yang sedang dikirim tetap
+// diselesaikan saat server shutdown.
+func SendAsync(msg Message) {
+	lifecycle.Go("mailer", func(_ context.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := Default.Send(ctx, msg); err != nil {
+			log.Printf("gagal mengirim email ke %s: %v", msg.To, err)
+		}
+	})
+}
+
+// ================================
+// 📝 LogMailer
+// ================================
+
+// LogMailer hanya menulis email ke log, cocok untuk development
+type LogMailer struct {
+	From string
+}
+
+func (m LogMailer) Send(_ context.Context, msg Message) error {
+	log.Printf("📧 [mail] from=%s to=%s subject=%q\n%s", m.From, msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer menyimpan email di memori, dipakai test untuk membaca email
+// (dan token di dalamnya) tanpa SMTP atau file
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (m *MemoryMailer) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent salinan email yang sudah dikirim, urut waktu kirim
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"fmt"
+	"go-crud/config"
+	"net/url"
+	"strings"
+)
+
+func link(path, token string) string {
+	return strings.TrimRight(config.App.Mail.LinkBaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// VerificationEmail berisi link verifikasi email setelah register
+func VerificationEmail(to, nama, token string) Message {
+	return Message{
+		To:      to,
+		Subject: "Verifikasi email akun kamu",
+		Body: fmt.Sprintf(`Halo %s,
+
+Terima kasih sudah mendaftar. Klik link berikut untuk memverifikasi email kamu:
+
+%s
+
+Link berlaku selama %s. Abaikan email ini kalau kamu tidak merasa mendaftar.
+`, nama, link("/verify-email", token), config.App.Mail.VerifyTokenTTL),
+	}
+}
+
+// ResetPasswordEmail berisi link untuk mengganti kata sandi
+func ResetPasswordEmail(to, nama, token string) Message {
+	return Message{
+		To:      to,
+		Subject: "Reset kata sandi",
+		Body: fmt.Sprintf(`Halo %s,
+
+Kami menerima permintaan reset kata sandi untuk akun kamu. Klik link berikut:
+
+%s
+
+Link berlaku selama %s dan hanya bisa dipakai sekali. Abaikan email ini kalau
+kamu tidak meminta reset kata sandi.
+`, nama, link("/reset-password", token), config.App.Mail.ResetTokenTTL),
+	}
+}
+
+// AccountExistsEmail dikirim kalau email yang sudah terdaftar dipakai
+// register lagi; respons register-nya tetap sama supaya email terdaftar tidak
+// bisa dicek lewat endpoint itu
+func AccountExistsEmail(to, nama string) Message {
+	return Message{
+		To:      to,
+		Subject: "Kamu sudah punya akun",
+		Body: fmt.Sprintf(`Halo %s,
+
+Ada yang mencoba mendaftar dengan email ini, padahal kamu sudah punya akun.
+Silakan login seperti biasa. Kalau lupa kata sandi, reset lewat:
+
+%s
+
+Abaikan email ini kalau kamu tidak merasa mendaftar.
+`, nama, strings.TrimRight(config.App.Mail.LinkBaseURL, "/")+"/forgot-password"),
+	}
+}
@@ -1,25 +1,149 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"go-crud/apperror"
+	"go-crud/auth"
 	"go-crud/config"
+	"go-crud/i18n"
+	"go-crud/imageproc"
+	"go-crud/lifecycle"
+	"go-crud/mailer"
+	"go-crud/oidc"
+	"go-crud/ratelimit"
+	"go-crud/repositories"
 	"go-crud/routes"
+	"go-crud/search"
+	"go-crud/services"
+	"go-crud/storage"
+	"go-crud/utils"
+	"go-crud/validation"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 func main() {
-	// 🔹 1. Koneksi ke database
-	config.ConnectDatabase()
-	fmt.Println("✅ Berhasil konek ke database")
+	// 🔹 0. Load & validasi konfigurasi
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if err != nil {
+		log.Fatal("Gagal load konfigurasi: ", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Konfigurasi tidak valid:\n", err)
+	}
+	utils.SetWilayahBaseURL(cfg.External.WilayahBaseURL)
+	if err := mailer.Init(cfg.Mail); err != nil {
+		log.Fatal("Gagal menyiapkan mailer: ", err)
+	}
+	if err := storage.Init(cfg.Storage); err != nil {
+		log.Fatal("Gagal menyiapkan storage: ", err)
+	}
+	imageproc.Init(cfg.Image)
+	if err := ratelimit.Init(cfg.RateLimit); err != nil {
+		log.Fatal("Gagal menyiapkan rate limit store: ", err)
+	}
+	oidc.Init(cfg.OIDC)
+
+	// 🔹 1. Koneksi ke database (ditutup paling akhir saat shutdown)
+	config.ConnectDatabase(cfg.Database)
+	lifecycle.OnShutdown("database", func(ctx context.Context) error {
+		return config.CloseDatabase()
+	})
+
+	// signing key JWT: pastikan ada key aktif, lalu cek rotasi tiap menit
+	if err := auth.InitKeys(); err != nil {
+		log.Fatal("Gagal menyiapkan signing key JWT: ", err)
+	}
+	auth.StartKeyRotation(time.Minute)
+
+	// bersihkan token kedaluwarsa tiap jam
+	auth.StartCleanup(time.Hour)
+
+	// index pencarian produk dibangun sebelum server menerima request
+	started := time.Now()
+	count, err := search.RebuildProducts(config.DB)
+	if err != nil {
+		log.Fatal("Gagal membangun index pencarian produk: ", err)
+	}
+	fmt.Printf("✅ Index pencarian: %d produk (%v)\n", count, time.Since(started).Round(time.Millisecond))
+	search.StartProductRefresh(cfg.Search.RefreshInterval)
+
+	// varian foto (thumbnail, ukuran web) dibuat di background; foto yang
+	// belum diproses saat server mati diambil lagi oleh sweep
+	repos := repositories.New(config.DB)
+	images := services.NewImageService(repos.FotoProduk, repos.Toko, storage.Default, imageproc.NewProcessor(cfg.Image))
+	imageproc.Default.Start(cfg.Image.Workers, cfg.Image.SweepInterval, images)
 
 	// 🔹 2. Buat instance Echo
 	e := echo.New()
+	// validasi request (tag `validate`) lewat c.Validate
+	e.Validator = validation.New()
+	// semua error yang di-return handler/middleware dibentuk di satu tempat
+	e.HTTPErrorHandler = apperror.NewHTTPErrorHandler(cfg.Server.ProblemJSON)
+	// bahasa pesan respons: preferensi user, Accept-Language, lalu bahasa Indonesia
+	i18n.CheckCatalogs()
+	e.Use(i18n.Middleware())
+	// StartTLS memakai e.TLSServer, bukan e.Server
+	for _, srv := range []*http.Server{e.Server, e.TLSServer} {
+		srv.ReadTimeout = cfg.Server.ReadTimeout
+		srv.ReadHeaderTimeout = cfg.Server.ReadHeaderTimeout
+		srv.WriteTimeout = cfg.Server.WriteTimeout
+		srv.IdleTimeout = cfg.Server.IdleTimeout
+	}
+	// IP client dipakai rate limit, header X-Forwarded-For hanya dipercaya di belakang proxy
+	if cfg.Server.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
 
 	// 🔹 3. Load semua route dari folder routes
 	routes.InitRoutes(e)
+	// route yang belum / tidak lagi ada di dokumentasi OpenAPI (routes/openapi.go)
+	if missing, stale := routes.Spec().Check(e.Routes()); len(missing) > 0 || len(stale) > 0 {
+		log.Printf("⚠️  openapi: route belum didokumentasikan: %v, tidak ada di router: %v", missing, stale)
+	}
 
 	// 🔹 4. Jalankan server
-	e.Logger.Fatal(e.Start(":8080"))
-}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		var err error
+		if cfg.Server.TLSEnabled() {
+			err = e.StartTLS(cfg.Server.Addr, cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+		} else {
+			err = e.Start(cfg.Server.Addr)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			e.Logger.Error(err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
 
+	// 🔹 5. Graceful shutdown: tunggu request yang sedang berjalan (termasuk
+	// transaksi database di CreateTransaction) selesai, lalu hentikan worker
+	// dan tutup database
+	fmt.Println("⏳ Mematikan server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error("Gagal menunggu request selesai: ", err)
+	}
+	if err := lifecycle.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error("Gagal menutup resource: ", err)
+	}
+	fmt.Println("👋 Server berhenti")
+}
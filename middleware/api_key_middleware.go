@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Route yang boleh diakses API key milik toko (service account toko)
+var tokoKeyPaths = []string{"/api/profile", "/api/products", "/api/toko", "/api/categories"}
+
+// apiKeyFromRequest membaca API key dari header X-API-Key atau
+// Authorization: Bearer gck_...
+func apiKeyFromRequest(c echo.Context) string {
+	if key := c.Request().Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok && auth.IsAPIKey(token) {
+		return token
+	}
+	return ""
+}
+
+// attachAPIKeyUser dipanggil AttachUser untuk request dengan API key
+func attachAPIKeyUser(c echo.Context, raw string, next echo.HandlerFunc) error {
+	key, user, err := auth.AuthenticateAPIKey(raw, c.RealIP())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			return apperror.Unauthorized("auth.invalid_api_key")
+		}
+		return apperror.Internal("api_key.check_failed", err)
+	}
+
+	if key.IDToko != nil && !hasPathPrefix(c.Path(), tokoKeyPaths) {
+		return apperror.Forbidden("api_key.toko_forbidden")
+	}
+
+	// user tetap dipasang untuk middleware berikutnya, tapi handler hanya
+	// memberikannya ke route yang memasang RequirePermission atau AllowAPIKey
+	// (lihat controllers.getAuthUser)
+	c.Set("authUser", *user)
+	c.Set("apiKey", *key)
+	return next(c)
+}
+
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowAPIKey dipasang di route tanpa RequirePermission yang tetap boleh
+// dipakai API key (misal /api/profile). Route lain menolak API key.
+func AllowAPIKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("apiKeyAllowed", true)
+			return next(c)
+		}
+	}
+}
+
+// DenyAPIKey dipasang di route yang hanya boleh dipakai manusia yang login
+// (ubah kata sandi, 2FA, kelola API key, dst)
+func DenyAPIKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Get("apiKey") != nil || apiKeyFromRequest(c) != "" {
+				return apperror.Forbidden("api_key.not_allowed")
+			}
+			return next(c)
+		}
+	}
+}
@@ -1,29 +1,32 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/auth"
 	"go-crud/config"
 	"go-crud/models"
-	"net/http"
-	"os"
 
 	"github.com/golang-jwt/jwt/v5"
 	jwtMiddleware "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 )
 
-var JWTSecret = []byte(os.Getenv("JWT_SECRET"))
-
 func UseJWT() echo.MiddlewareFunc {
 	return jwtMiddleware.WithConfig(jwtMiddleware.Config{
-		SigningKey: JWTSecret,
-		NewClaimsFunc: func(c echo.Context) jwt.Claims {
-			return jwt.MapClaims{}
+		// tanda tangan RS256/EdDSA diverifikasi dengan key manager (header kid)
+		ParseTokenFunc: func(c echo.Context, raw string) (interface{}, error) {
+			return auth.ParseToken(raw)
+		},
+		// request dengan API key diautentikasi di AttachUser
+		Skipper: func(c echo.Context) bool {
+			return apiKeyFromRequest(c) != ""
 		},
 		ErrorHandler: func(c echo.Context, err error) error {
-			return c.JSON(http.StatusUnauthorized, echo.Map{
-				"error": fmt.Sprintf("Token tidak valid: %v", err),
-			})
+			if errors.Is(err, jwtMiddleware.ErrJWTMissing) {
+				return apperror.Unauthorized("auth.token_missing")
+			}
+			return apperror.Unauthorized("auth.token_invalid").WithCause(err)
 		},
 	})
 }
@@ -32,32 +35,59 @@ func UseJWT() echo.MiddlewareFunc {
 func AttachUser() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if raw := apiKeyFromRequest(c); raw != "" {
+				return attachAPIKeyUser(c, raw, next)
+			}
+
 			userToken, ok := c.Get("user").(*jwt.Token)
 			if !ok || userToken == nil {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"message": "Token tidak valid atau user tidak ditemukan",
-				})
+				return apperror.Unauthorized("auth.token_or_user_invalid")
 			}
 
 			claims, ok := userToken.Claims.(jwt.MapClaims)
 			if !ok {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"message": "Klaim token tidak valid",
-				})
+				return apperror.Unauthorized("auth.invalid_claims")
+			}
+
+			// challenge token 2FA tidak boleh dipakai sebagai access token
+			if typ, _ := claims["typ"].(string); typ != "" {
+				return apperror.Unauthorized("auth.not_access_token")
 			}
 
 			userIDFloat, ok := claims["user_id"].(float64)
 			if !ok {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"message": "Klaim user_id tidak ditemukan",
-				})
+				return apperror.Unauthorized("auth.user_id_claim_missing")
 			}
 
 			var user models.User
-			if err := config.DB.First(&user, uint(userIDFloat)).Error; err != nil {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"message": "User tidak ditemukan di database",
-				})
+			if err := config.DB.Preload("Roles").Preload("TwoFactor").First(&user, uint(userIDFloat)).Error; err != nil {
+				return apperror.Unauthorized("auth.user_not_in_db")
+			}
+
+			// token dari sebelum logout semua sesi / ganti kata sandi sudah tidak berlaku
+			tokenVersion, _ := claims["tv"].(float64)
+			if int(tokenVersion) != user.TokenVersion {
+				return apperror.Unauthorized("auth.token_expired")
+			}
+
+			jti, _ := claims["jti"].(string)
+			revoked, err := auth.IsAccessTokenRevoked(jti)
+			if err != nil {
+				return apperror.Internal("auth.token_check_failed", err)
+			}
+			if revoked {
+				return apperror.Unauthorized("auth.token_revoked")
+			}
+
+			// session yang sudah diakhiri (logout / dihapus dari daftar perangkat) ditolak
+			if sid, ok := claims["sid"].(float64); ok {
+				if err := auth.CheckSession(uint64(sid), c.RealIP()); err != nil {
+					if errors.Is(err, auth.ErrSessionTerminated) {
+						return apperror.Unauthorized("session.ended")
+					}
+					return apperror.Internal("session.check_failed", err)
+				}
+				c.Set("sessionID", uint64(sid))
 			}
 
 			// simpan user ke context
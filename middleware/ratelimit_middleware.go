@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"go-crud/apperror"
+	"go-crud/ratelimit"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitByIP membatasi jumlah request per IP untuk satu grup endpoint
+// (name), misal "login" atau "register". Header X-RateLimit-* selalu dikirim,
+// Retry-After ditambahkan saat request ditolak.
+func RateLimitByIP(name string, limit int, window time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := "ip:" + name + ":" + c.RealIP()
+			res, err := ratelimit.Allow(c.Request().Context(), key, limit, window)
+			if err != nil {
+				// store bermasalah (misal Redis mati): jangan blokir semua user, cukup dicatat
+				log.Printf("rate limit %s gagal: %v", name, err)
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("X-RateLimit-Reset", strconv.Itoa(ratelimit.RetryAfterSeconds(res.ResetIn)))
+
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(res.ResetIn)))
+				return apperror.TooManyRequests("error.rate_limited")
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"go-crud/apperror"
+	"go-crud/models"
+	"go-crud/rbac"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequirePermission dipasang setelah AttachUser, menolak request kalau role
+// user tidak punya permission yang diminta
+func RequirePermission(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("authUser").(models.User)
+			if !ok {
+				return apperror.Unauthorized("auth.token_or_user_invalid")
+			}
+
+			if !rbac.Can(user, permission) {
+				return apperror.Forbidden("rbac.permission_required").WithArgs(permission)
+			}
+			// scope API key sudah dicek Can, route ini boleh dipakai API key
+			c.Set("apiKeyAllowed", true)
+			return next(c)
+		}
+	}
+}
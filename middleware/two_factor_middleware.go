@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"go-crud/apperror"
+	"go-crud/auth"
+	"go-crud/models"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireTwoFactorSetup dipasang setelah AttachUser. Akun dengan role yang
+// wajib 2FA (misal admin) tapi belum mengaktifkannya hanya boleh mengakses
+// route setup 2FA dan profile sampai 2FA aktif.
+func RequireTwoFactorSetup() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("authUser").(models.User)
+			if !ok {
+				return apperror.Unauthorized("auth.token_or_user_invalid")
+			}
+
+			if user.TwoFactorEnabled() || !auth.RequiresTwoFactor(user) {
+				return next(c)
+			}
+			path := c.Path()
+			if strings.HasPrefix(path, "/api/2fa") || path == "/api/profile" {
+				return next(c)
+			}
+
+			return apperror.Forbidden("two_factor.required")
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Snapshot skema awal (sama dengan hasil AutoMigrate lama di cmd/migrate).
+// Sengaja disalin, bukan memakai package models, supaya migrasi ini tidak
+// ikut berubah ketika model berubah di kemudian hari.
+
+type userV1 struct {
+	ID           uint64  `gorm:"primaryKey;autoIncrement"`
+	Nama         string  `gorm:"type:varchar(100);not null"`
+	KataSandi    string  `gorm:"type:varchar(255);not null"`
+	NoTelp       *string `gorm:"type:varchar(20);unique"`
+	TanggalLahir *time.Time
+	JenisKelamin *string   `gorm:"type:varchar(20);check:chk_users_jenis_kelamin,jenis_kelamin IN ('Laki-laki','Perempuan')"`
+	Tentang      *string   `gorm:"type:text"`
+	Pekerjaan    *string   `gorm:"type:varchar(100)"`
+	Email        string    `gorm:"type:varchar(100);unique;not null"`
+	IDProvinsi   *string   `gorm:"type:varchar(10)"`
+	IDKota       *string   `gorm:"type:varchar(10)"`
+	IsAdmin      bool      `gorm:"not null;default:false"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (userV1) TableName() string { return "users" }
+
+type tokoV1 struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
+	NamaToko  string `gorm:"not null"`
+	UrlFoto   *string
+	IDUser    uint64    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser"`
+}
+
+func (tokoV1) TableName() string { return "tokos" }
+
+type alamatV1 struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement"`
+	IDUser       uint64    `gorm:"not null;index"`
+	JudulAlamat  string    `gorm:"type:varchar(100);not null"`
+	NamaPenerima string    `gorm:"type:varchar(100);not null"`
+	NoTelp       string    `gorm:"type:varchar(20);not null"`
+	DetailAlamat string    `gorm:"type:text;not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (alamatV1) TableName() string { return "alamats" }
+
+type categoryV1 struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement"`
+	NamaCategory string    `gorm:"type:varchar(100);not null;unique"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (categoryV1) TableName() string { return "categories" }
+
+type produkV1 struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	NamaProduk    string    `gorm:"type:varchar(150);not null;index"`
+	Slug          string    `gorm:"type:varchar(200);unique;not null"`
+	HargaReseller int       `gorm:"not null;default:0"`
+	HargaKonsumen int       `gorm:"not null;default:0"`
+	Stok          int       `gorm:"not null;default:0"`
+	Deskripsi     *string   `gorm:"type:text"`
+	IDToko        uint64    `gorm:"not null;index"`
+	IDCategory    *uint64   `gorm:"index"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+
+	Toko     *tokoV1     `gorm:"foreignKey:IDToko"`
+	Category *categoryV1 `gorm:"foreignKey:IDCategory;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+}
+
+func (produkV1) TableName() string { return "produks" }
+
+type fotoProdukV1 struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	IDProduk  uint64    `gorm:"not null;index"`
+	URL       string    `gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	Produk *produkV1 `gorm:"foreignKey:IDProduk"`
+}
+
+func (fotoProdukV1) TableName() string { return "foto_produks" }
+
+type logProdukV1 struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	IDProduk      uint64    `gorm:"not null;index"`
+	NamaProduk    string    `gorm:"type:varchar(150);not null"`
+	Slug          string    `gorm:"type:varchar(200);not null"`
+	HargaReseller int       `gorm:"not null;default:0"`
+	HargaKonsumen int       `gorm:"not null;default:0"`
+	Deskripsi     *string   `gorm:"type:text"`
+	IDToko        uint64    `gorm:"not null;index"`
+	IDCategory    *uint64   `gorm:"index"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+
+	Toko     *tokoV1     `gorm:"foreignKey:IDToko"`
+	Category *categoryV1 `gorm:"foreignKey:IDCategory"`
+}
+
+func (logProdukV1) TableName() string { return "log_produks" }
+
+type trxV1 struct {
+	ID               uint64    `gorm:"primaryKey;autoIncrement"`
+	IDUser           uint64    `gorm:"not null"`
+	AlamatPengiriman *uint64   `gorm:"index"`
+	HargaTotal       int       `gorm:"not null;default:0"`
+	KodeInvoice      string    `gorm:"type:varchar(50);unique;not null"`
+	MethodBayar      *string   `gorm:"type:varchar(50)"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+
+	User   *userV1   `gorm:"foreignKey:IDUser"`
+	Alamat *alamatV1 `gorm:"foreignKey:AlamatPengiriman"`
+}
+
+func (trxV1) TableName() string { return "trxes" }
+
+type detailTrxV1 struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	IDTrx       uint64    `gorm:"not null;index"`
+	IDLogProduk uint64    `gorm:"not null;index"`
+	IDToko      uint64    `gorm:"not null;index"`
+	Kuantitas   int       `gorm:"not null;default:1"`
+	HargaTotal  int       `gorm:"not null;default:0"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	Trx       *trxV1       `gorm:"foreignKey:IDTrx"`
+	LogProduk *logProdukV1 `gorm:"foreignKey:IDLogProduk"`
+	Toko      *tokoV1      `gorm:"foreignKey:IDToko"`
+}
+
+func (detailTrxV1) TableName() string { return "detail_trxes" }
+
+// urutan sesuai dependensi foreign key
+var initialTables = []interface{}{
+	&userV1{},
+	&tokoV1{},
+	&alamatV1{},
+	&categoryV1{},
+	&produkV1{},
+	&fotoProdukV1{},
+	&logProdukV1{},
+	&trxV1{},
+	&detailTrxV1{},
+}
+
+func init() {
+	Register(Migration{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			// database lama yang dibuat lewat AutoMigrate cukup ditandai sudah jalan
+			for _, table := range initialTables {
+				if tx.Migrator().HasTable(table) {
+					continue
+				}
+				if err := tx.Migrator().CreateTable(table); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for i := len(initialTables) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(initialTables[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userTokenVersionV2 struct {
+	TokenVersion int `gorm:"not null;default:0"`
+}
+
+func (userTokenVersionV2) TableName() string { return "users" }
+
+type refreshTokenV2 struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	IDUser     uint64    `gorm:"not null;index"`
+	TokenHash  string    `gorm:"type:varchar(64);not null;unique"`
+	FamilyID   string    `gorm:"type:varchar(64);not null;index"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+	RevokedAt  *time.Time
+	ReplacedBy *uint64
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (refreshTokenV2) TableName() string { return "refresh_tokens" }
+
+type revokedTokenV2 struct {
+	JTI       string    `gorm:"type:varchar(64);primaryKey"`
+	IDUser    uint64    `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (revokedTokenV2) TableName() string { return "revoked_tokens" }
+
+func init() {
+	Register(Migration{
+		Version: 2,
+		Name:    "auth_tokens",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&userTokenVersionV2{}, "TokenVersion"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&refreshTokenV2{}, &revokedTokenV2{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&revokedTokenV2{}, &refreshTokenV2{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&userTokenVersionV2{}, "TokenVersion")
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userRoleV3 struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	IDUser    uint64    `gorm:"not null;uniqueIndex:idx_user_roles_user_role"`
+	Role      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_user_roles_user_role"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (userRoleV3) TableName() string { return "user_roles" }
+
+type userIsAdminV3 struct {
+	IsAdmin bool `gorm:"not null;default:false"`
+}
+
+func (userIsAdminV3) TableName() string { return "users" }
+
+// Kolom users.is_admin diganti tabel user_roles. Data lama di-backfill:
+// is_admin = true -> admin, selain itu buyer + seller (semua punya toko).
+func init() {
+	Register(Migration{
+		Version: 3,
+		Name:    "user_roles",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&userRoleV3{}); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			backfill := []struct {
+				role    string
+				isAdmin bool
+			}{
+				{"admin", true},
+				{"buyer", false},
+				{"seller", false},
+			}
+			for _, b := range backfill {
+				err := tx.Exec(
+					"INSERT INTO user_roles (id_user, role, created_at) SELECT id, ?, ? FROM users WHERE is_admin = ?",
+					b.role, now, b.isAdmin,
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return tx.Migrator().DropColumn(&userIsAdminV3{}, "IsAdmin")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&userIsAdminV3{}, "IsAdmin"); err != nil {
+				return err
+			}
+			err := tx.Exec(
+				"UPDATE users SET is_admin = ? WHERE id IN (SELECT id_user FROM user_roles WHERE role = ?)",
+				true, "admin",
+			).Error
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&userRoleV3{})
+		},
+	})
+}
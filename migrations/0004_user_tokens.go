@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userEmailVerifiedV4 struct {
+	EmailVerifiedAt *time.Time
+}
+
+func (userEmailVerifiedV4) TableName() string { return "users" }
+
+type userTokenV4 struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	IDUser    uint64    `gorm:"not null;index"`
+	Purpose   string    `gorm:"type:varchar(30);not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;unique"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (userTokenV4) TableName() string { return "user_tokens" }
+
+func init() {
+	Register(Migration{
+		Version: 4,
+		Name:    "user_tokens",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&userEmailVerifiedV4{}, "EmailVerifiedAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&userTokenV4{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&userTokenV4{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&userEmailVerifiedV4{}, "EmailVerifiedAt")
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userTwoFactorV5 struct {
+	IDUser       uint64 `gorm:"primaryKey;autoIncrement:false"`
+	Secret       string `gorm:"type:varchar(64);not null"`
+	EnabledAt    *time.Time
+	LastUsedStep int64     `gorm:"not null;default:0"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (userTwoFactorV5) TableName() string { return "user_two_factors" }
+
+type recoveryCodeV5 struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
+	IDUser    uint64 `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null;unique"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (recoveryCodeV5) TableName() string { return "recovery_codes" }
+
+func init() {
+	Register(Migration{
+		Version: 5,
+		Name:    "two_factor",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&userTwoFactorV5{}, &recoveryCodeV5{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&recoveryCodeV5{}, &userTwoFactorV5{})
+		},
+	})
+}
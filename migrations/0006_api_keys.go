@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyV6 struct {
+	ID          uint64  `gorm:"primaryKey;autoIncrement"`
+	IDUser      uint64  `gorm:"not null;index"`
+	IDToko      *uint64 `gorm:"index"`
+	Name        string  `gorm:"type:varchar(100);not null"`
+	Prefix      string  `gorm:"type:varchar(20);not null;unique"`
+	KeyHash     string  `gorm:"type:varchar(64);not null"`
+	Permissions string  `gorm:"type:text;not null"`
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	LastUsedIP  *string `gorm:"type:varchar(45)"`
+	RevokedAt   *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Toko *tokoV1 `gorm:"foreignKey:IDToko;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (apiKeyV6) TableName() string { return "api_keys" }
+
+func init() {
+	Register(Migration{
+		Version: 6,
+		Name:    "api_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&apiKeyV6{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&apiKeyV6{})
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type signingKeyV7 struct {
+	KID         string    `gorm:"primaryKey;type:varchar(64)"`
+	Algorithm   string    `gorm:"type:varchar(10);not null"`
+	PrivateKey  string    `gorm:"type:text;not null"`
+	PublicKey   string    `gorm:"type:text;not null"`
+	ActivatesAt time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (signingKeyV7) TableName() string { return "signing_keys" }
+
+// JWT ditandatangani key asimetris (RS256/EdDSA) yang dirotasi, menggantikan JWT_SECRET
+func init() {
+	Register(Migration{
+		Version: 7,
+		Name:    "signing_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&signingKeyV7{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&signingKeyV7{})
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type sessionV8 struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	IDUser     uint64    `gorm:"not null;index"`
+	FamilyID   string    `gorm:"type:varchar(64);not null;unique"`
+	Device     string    `gorm:"type:varchar(100);not null"`
+	UserAgent  string    `gorm:"type:varchar(500)"`
+	IP         string    `gorm:"type:varchar(45)"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+	LastSeenAt time.Time `gorm:"not null"`
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (sessionV8) TableName() string { return "sessions" }
+
+// Refresh token lama belum punya session; session-nya dibuat otomatis saat
+// refresh berikutnya (lihat auth.Refresh)
+func init() {
+	Register(Migration{
+		Version: 8,
+		Name:    "sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&sessionV8{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&sessionV8{})
+		},
+	})
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userIdentityV9 struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	IDUser      uint64 `gorm:"not null;index"`
+	Issuer      string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject"`
+	Subject     string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject"`
+	Email       string `gorm:"type:varchar(100)"`
+	LastLoginAt *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (userIdentityV9) TableName() string { return "user_identities" }
+
+type oidcStateV9 struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement"`
+	StateHash    string    `gorm:"type:varchar(64);not null;unique"`
+	Nonce        string    `gorm:"type:varchar(64);not null"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null"`
+	IDUser       *uint64   `gorm:"index"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+
+	User *userV1 `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (oidcStateV9) TableName() string { return "oidc_states" }
+
+func init() {
+	Register(Migration{
+		Version: 9,
+		Name:    "oidc_identities",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&userIdentityV9{}, &oidcStateV9{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&oidcStateV9{}, &userIdentityV9{})
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type userBahasaV10 struct {
+	Bahasa *string `gorm:"type:varchar(5)"`
+}
+
+func (userBahasaV10) TableName() string { return "users" }
+
+func init() {
+	Register(Migration{
+		Version: 10,
+		Name:    "user_bahasa",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&userBahasaV10{}, "Bahasa")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&userBahasaV10{}, "Bahasa")
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// index untuk filter rentang harga dan urutan di GET /api/products
+type produkSearchV11 struct {
+	HargaKonsumen int       `gorm:"index:idx_produks_harga_konsumen"`
+	CreatedAt     time.Time `gorm:"index:idx_produks_created_at"`
+}
+
+func (produkSearchV11) TableName() string { return "produks" }
+
+var produkSearchIndexesV11 = []string{"idx_produks_harga_konsumen", "idx_produks_created_at"}
+
+func init() {
+	Register(Migration{
+		Version: 11,
+		Name:    "produk_search_index",
+		Up: func(tx *gorm.DB) error {
+			for _, name := range produkSearchIndexesV11 {
+				if err := tx.Migrator().CreateIndex(&produkSearchV11{}, name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, name := range produkSearchIndexesV11 {
+				if err := tx.Migrator().DropIndex(&produkSearchV11{}, name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
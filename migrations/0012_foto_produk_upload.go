@@ -0,0 +1,40 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// foto hasil upload: key object di BlobStore, urutan tampil dan foto utama.
+// Foto lama (hanya URL) punya storage_key kosong.
+type fotoProdukV12 struct {
+	StorageKey  string `gorm:"type:varchar(255);not null;default:''"`
+	ContentType string `gorm:"type:varchar(50);not null;default:''"`
+	Ukuran      int64  `gorm:"not null;default:0"`
+	Urutan      int    `gorm:"not null;default:0"`
+	Utama       bool   `gorm:"not null;default:false"`
+}
+
+func (fotoProdukV12) TableName() string { return "foto_produks" }
+
+var fotoProdukColumnsV12 = []string{"StorageKey", "ContentType", "Ukuran", "Urutan", "Utama"}
+
+func init() {
+	Register(Migration{
+		Version: 12,
+		Name:    "foto_produk_upload",
+		Up: func(tx *gorm.DB) error {
+			for _, col := range fotoProdukColumnsV12 {
+				if err := tx.Migrator().AddColumn(&fotoProdukV12{}, col); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, col := range fotoProdukColumnsV12 {
+				if err := tx.Migrator().DropColumn(&fotoProdukV12{}, col); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// varian foto (thumbnail & web) yang dibuat di background setelah upload,
+// untuk foto produk dan foto toko
+type fotoProdukV13 struct {
+	URLThumb     string `gorm:"type:varchar(255);not null;default:''"`
+	URLWeb       string `gorm:"type:varchar(255);not null;default:''"`
+	ThumbKey     string `gorm:"type:varchar(255);not null;default:''"`
+	WebKey       string `gorm:"type:varchar(255);not null;default:''"`
+	StatusVarian string `gorm:"type:varchar(20);not null;default:'ready'"`
+}
+
+func (fotoProdukV13) TableName() string { return "foto_produks" }
+
+type tokoV13 struct {
+	UrlFotoThumb *string `gorm:"type:varchar(255)"`
+	FotoKey      string  `gorm:"type:varchar(255);not null;default:''"`
+	FotoThumbKey string  `gorm:"type:varchar(255);not null;default:''"`
+	FotoWebKey   string  `gorm:"type:varchar(255);not null;default:''"`
+	FotoStatus   string  `gorm:"type:varchar(20);not null;default:''"`
+}
+
+func (tokoV13) TableName() string { return "tokos" }
+
+var (
+	fotoProdukColumnsV13 = []string{"URLThumb", "URLWeb", "ThumbKey", "WebKey", "StatusVarian"}
+	tokoColumnsV13       = []string{"UrlFotoThumb", "FotoKey", "FotoThumbKey", "FotoWebKey", "FotoStatus"}
+)
+
+func init() {
+	Register(Migration{
+		Version: 13,
+		Name:    "foto_varian",
+		Up: func(tx *gorm.DB) error {
+			for _, col := range fotoProdukColumnsV13 {
+				if err := tx.Migrator().AddColumn(&fotoProdukV13{}, col); err != nil {
+					return err
+				}
+			}
+			for _, col := range tokoColumnsV13 {
+				if err := tx.Migrator().AddColumn(&tokoV13{}, col); err != nil {
+					return err
+				}
+			}
+			// foto yang sudah di-upload sebelumnya ikut dibuatkan varian;
+			// foto lama yang hanya berupa URL tetap ready tanpa varian
+			return tx.Table("foto_produks").Where("storage_key <> ''").Update("status_varian", "pending").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, col := range tokoColumnsV13 {
+				if err := tx.Migrator().DropColumn(&tokoV13{}, col); err != nil {
+					return err
+				}
+			}
+			for _, col := range fotoProdukColumnsV13 {
+				if err := tx.Migrator().DropColumn(&fotoProdukV13{}, col); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
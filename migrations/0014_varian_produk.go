@@ -0,0 +1,117 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// varian produk: jenis opsi (ukuran, warna) beserta nilainya, kombinasi
+// varian dengan SKU, stok dan harga sendiri, foto per varian, dan snapshot
+// varian di log_produk
+
+type opsiProdukV14 struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	IDProduk  uint64    `gorm:"not null;index"`
+	Nama      string    `gorm:"type:varchar(50);not null"`
+	Urutan    int       `gorm:"not null;default:0"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	Produk *produkV1 `gorm:"foreignKey:IDProduk;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (opsiProdukV14) TableName() string { return "opsi_produks" }
+
+type nilaiOpsiV14 struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement"`
+	IDOpsi uint64 `gorm:"not null;index"`
+	Nilai  string `gorm:"type:varchar(50);not null"`
+	Urutan int    `gorm:"not null;default:0"`
+
+	Opsi *opsiProdukV14 `gorm:"foreignKey:IDOpsi;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (nilaiOpsiV14) TableName() string { return "nilai_opsis" }
+
+type varianProdukV14 struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	IDProduk      uint64    `gorm:"not null;index;uniqueIndex:idx_varian_produk_nama"`
+	SKU           string    `gorm:"type:varchar(64);not null;unique"`
+	Nama          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_varian_produk_nama"`
+	HargaReseller int       `gorm:"not null;default:0"`
+	HargaKonsumen int       `gorm:"not null;default:0"`
+	Stok          int       `gorm:"not null;default:0"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+
+	Produk *produkV1 `gorm:"foreignKey:IDProduk;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (varianProdukV14) TableName() string { return "varian_produks" }
+
+type varianNilaiV14 struct {
+	IDVarian uint64 `gorm:"primaryKey;autoIncrement:false"`
+	IDNilai  uint64 `gorm:"primaryKey;autoIncrement:false;index"`
+
+	Varian *varianProdukV14 `gorm:"foreignKey:IDVarian;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Nilai  *nilaiOpsiV14    `gorm:"foreignKey:IDNilai;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (varianNilaiV14) TableName() string { return "varian_nilais" }
+
+type fotoProdukV14 struct {
+	IDVarian *uint64 `gorm:"index"`
+}
+
+func (fotoProdukV14) TableName() string { return "foto_produks" }
+
+type logProdukV14 struct {
+	IDVarian   *uint64 `gorm:"index"`
+	SKU        string  `gorm:"type:varchar(64);not null;default:''"`
+	NamaVarian string  `gorm:"type:varchar(255);not null;default:''"`
+}
+
+func (logProdukV14) TableName() string { return "log_produks" }
+
+var logProdukColumnsV14 = []string{"IDVarian", "SKU", "NamaVarian"}
+
+func init() {
+	Register(Migration{
+		Version: 14,
+		Name:    "varian_produk",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&opsiProdukV14{}, &nilaiOpsiV14{}, &varianProdukV14{}, &varianNilaiV14{}); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&fotoProdukV14{}, "IDVarian"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(&fotoProdukV14{}, "IDVarian"); err != nil {
+				return err
+			}
+			for _, col := range logProdukColumnsV14 {
+				if err := tx.Migrator().AddColumn(&logProdukV14{}, col); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateIndex(&logProdukV14{}, "IDVarian")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&logProdukV14{}, "IDVarian"); err != nil {
+				return err
+			}
+			for _, col := range logProdukColumnsV14 {
+				if err := tx.Migrator().DropColumn(&logProdukV14{}, col); err != nil {
+					return err
+				}
+			}
+			if err := tx.Migrator().DropIndex(&fotoProdukV14{}, "IDVarian"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&fotoProdukV14{}, "IDVarian"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&varianNilaiV14{}, &varianProdukV14{}, &nilaiOpsiV14{}, &opsiProdukV14{})
+		},
+	})
+}
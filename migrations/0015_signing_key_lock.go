@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type signingKeyLockV15 struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	RotatedAt time.Time `gorm:"not null"`
+}
+
+func (signingKeyLockV15) TableName() string { return "signing_key_locks" }
+
+// satu baris sentinel yang dikunci Rotate supaya rotasi signing key dari
+// beberapa instance berjalan bergantian
+func init() {
+	Register(Migration{
+		Version: 15,
+		Name:    "signing_key_lock",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&signingKeyLockV15{}); err != nil {
+				return err
+			}
+			return tx.Create(&signingKeyLockV15{ID: 1, RotatedAt: time.Now()}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&signingKeyLockV15{})
+		},
+	})
+}
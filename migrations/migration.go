@@ -0,0 +1,220 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ================================
+// 📦 Struct Migrasi
+// ================================
+
+// Migration adalah satu langkah perubahan skema. Up dan Down dijalankan di
+// dalam transaksi database (kecuali DDL MySQL yang auto-commit).
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration mencatat migrasi yang sudah dijalankan
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"type:varchar(200);not null" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status satu migrasi untuk perintah `migrate status`
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	// Missing true kalau versi tercatat di database tapi file migrasinya tidak ada
+	Missing bool
+}
+
+var registry = map[int64]Migration{}
+
+// Register dipanggil dari init() di tiap file migrasi
+func Register(m Migration) {
+	if _, exists := registry[m.Version]; exists {
+		panic(fmt.Sprintf("migrasi versi %d didaftarkan dua kali", m.Version))
+	}
+	if m.Up == nil || m.Down == nil {
+		panic(fmt.Sprintf("migrasi %d_%s wajib punya Up dan Down", m.Version, m.Name))
+	}
+	registry[m.Version] = m
+}
+
+// All mengembalikan semua migrasi terurut dari versi terkecil
+func All() []Migration {
+	list := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list
+}
+
+// LatestVersion dipakai `migrate create` untuk menentukan nomor berikutnya
+func LatestVersion() int64 {
+	var latest int64
+	for v := range registry {
+		if v > latest {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// ================================
+// 🔹 Runner
+// ================================
+
+func ensureTable(db *gorm.DB) error {
+	return db.AutoMigrate(&SchemaMigration{})
+}
+
+func applied(db *gorm.DB) (map[int64]SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := db.Order("version asc").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int64]SchemaMigration, len(rows))
+	for _, r := range rows {
+		result[r.Version] = r
+	}
+	return result, nil
+}
+
+// runInTransaction menjalankan fn dalam satu transaksi. Khusus SQLite, foreign
+// key dimatikan dulu di koneksi yang sama karena DropColumn/AlterColumn di SQLite
+// membuat ulang tabel; integritasnya dicek ulang dengan foreign_key_check.
+func runInTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if db.Dialector.Name() != "sqlite" {
+		return db.Transaction(fn)
+	}
+
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := fn(tx); err != nil {
+				return err
+			}
+			var violations []map[string]interface{}
+			if err := tx.Raw("PRAGMA foreign_key_check").Scan(&violations).Error; err != nil {
+				return err
+			}
+			if len(violations) > 0 {
+				return fmt.Errorf("%d pelanggaran foreign key setelah migrasi", len(violations))
+			}
+			return nil
+		})
+	})
+}
+
+// Up menjalankan semua migrasi yang belum pernah dijalankan, mengembalikan yang berhasil
+func Up(db *gorm.DB) ([]Migration, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, fmt.Errorf("gagal membuat tabel schema_migrations: %v", err)
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, m := range All() {
+		if _, ok := done[m.Version]; ok {
+			continue
+		}
+
+		err := runInTransaction(db, func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   m.Version,
+				Name:      m.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("migrasi %04d_%s gagal: %v", m.Version, m.Name, err)
+		}
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// Down me-rollback n migrasi terakhir yang sudah dijalankan
+func Down(db *gorm.DB, n int) ([]Migration, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, fmt.Errorf("gagal membuat tabel schema_migrations: %v", err)
+	}
+
+	var rows []SchemaMigration
+	if err := db.Order("version desc").Limit(n).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for _, row := range rows {
+		m, ok := registry[row.Version]
+		if !ok {
+			return reverted, fmt.Errorf("file migrasi versi %d (%s) tidak ditemukan, tidak bisa rollback", row.Version, row.Name)
+		}
+
+		err := runInTransaction(db, func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, "version = ?", m.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("rollback %04d_%s gagal: %v", m.Version, m.Name, err)
+		}
+		reverted = append(reverted, m)
+	}
+	return reverted, nil
+}
+
+// GetStatus menggabungkan daftar migrasi terdaftar dengan yang tercatat di database
+func GetStatus(db *gorm.DB) ([]Status, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, fmt.Errorf("gagal membuat tabel schema_migrations: %v", err)
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+	for _, m := range All() {
+		s := Status{Version: m.Version, Name: m.Name}
+		if row, ok := done[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			s.AppliedAt = &appliedAt
+			delete(done, m.Version)
+		}
+		result = append(result, s)
+	}
+	for _, row := range done {
+		appliedAt := row.AppliedAt
+		result = append(result, Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Missing: true})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"go-crud/config"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Semua migrasi harus bisa naik, turun sampai kosong lalu naik lagi di
+// SQLite, lewat pool yang sama dengan server (":memory:" dengan
+// MaxOpenConns bawaan)
+func TestUpDownSQLite(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Driver, cfg.Name = "sqlite", ":memory:"
+	config.ConnectDatabase(cfg)
+	t.Cleanup(func() { config.CloseDatabase() })
+	db := config.DB
+
+	all := All()
+	tables := []string{"users", "produks", "trxes", "api_keys", "signing_keys", "signing_key_locks", "varian_produks"}
+
+	ran, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(ran) != len(all) {
+		t.Fatalf("%d migrasi dijalankan, seharusnya %d", len(ran), len(all))
+	}
+
+	// transaksi yang tumpang tindih memaksa pool memakai lebih dari satu
+	// koneksi; semuanya harus tetap melihat skema yang sama
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := db.Transaction(func(tx *gorm.DB) error {
+				var count int64
+				if err := tx.Table("users").Count(&count).Error; err != nil {
+					return err
+				}
+				time.Sleep(10 * time.Millisecond)
+				return tx.Table("produks").Count(&count).Error
+			})
+			if err != nil {
+				t.Errorf("query setelah Up: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, table := range tables {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("tabel %s tidak ada setelah Up", table)
+		}
+	}
+
+	if ran, err := Up(db); err != nil || len(ran) != 0 {
+		t.Fatalf("Up kedua menjalankan %d migrasi (err %v), seharusnya 0", len(ran), err)
+	}
+
+	reverted, err := Down(db, len(all))
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if len(reverted) != len(all) {
+		t.Fatalf("%d migrasi di-rollback, seharusnya %d", len(reverted), len(all))
+	}
+	for _, table := range tables {
+		if db.Migrator().HasTable(table) {
+			t.Errorf("tabel %s masih ada setelah Down", table)
+		}
+	}
+	status, err := GetStatus(db)
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	for _, s := range status {
+		if s.AppliedAt != nil {
+			t.Errorf("migrasi %d masih tercatat setelah Down", s.Version)
+		}
+	}
+
+	if ran, err := Up(db); err != nil || len(ran) != len(all) {
+		t.Fatalf("Up ulang menjalankan %d migrasi (err %v), seharusnya %d", len(ran), err, len(all))
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey dipakai client mesin (integrasi ERP, script) sebagai pengganti login.
+// Key asli hanya ditampilkan sekali saat dibuat, yang disimpan hanya prefix
+// (untuk identifikasi) dan hash sha256-nya.
+type APIKey struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDUser uint64 `gorm:"not null;index" json:"id_user"`
+	// diisi kalau key khusus untuk satu toko (service account toko)
+	IDToko  *uint64 `gorm:"index" json:"id_toko"`
+	Name    string  `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix  string  `gorm:"type:varchar(20);not null;unique" json:"prefix"`
+	KeyHash string  `gorm:"type:varchar(64);not null" json:"-"`
+	// daftar permission dipisah koma, selalu subset dari permission role pemilik
+	Permissions string     `gorm:"type:text;not null" json:"-"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  *string    `gorm:"type:varchar(45)" json:"last_used_ip"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+	Toko *Toko `gorm:"foreignKey:IDToko;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"toko,omitempty"`
+}
+
+// PermissionList memecah kolom Permissions menjadi slice (tidak pernah nil)
+func (k APIKey) PermissionList() []string {
+	list := []string{}
+	for _, p := range strings.Split(k.Permissions, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
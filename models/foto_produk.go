@@ -2,10 +2,32 @@ package models
 
 import "time"
 
+// status pembuatan varian foto (FotoProduk.StatusVarian, Toko.FotoStatus)
+const (
+	VarianPending = "pending"
+	VarianReady   = "ready"
+	VarianFailed  = "failed"
+)
+
 type FotoProduk struct {
 	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
 	IDProduk  uint64     `gorm:"not null;index" json:"id_produk"`
 	URL       string     `gorm:"type:varchar(255);not null" json:"url"` 
+	// key object di storage.BlobStore, kosong untuk foto lama yang hanya berupa URL
+	StorageKey  string   `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	ContentType string   `gorm:"type:varchar(50);not null;default:''" json:"content_type"`
+	Ukuran      int64    `gorm:"not null;default:0" json:"ukuran"`
+	// urutan tampil, kecil dulu
+	Urutan      int      `gorm:"not null;default:0" json:"urutan"`
+	Utama       bool     `gorm:"not null;default:false" json:"utama"`
+	// foto khusus satu varian (misal warna tertentu), nil untuk foto umum
+	IDVarian    *uint64  `gorm:"index" json:"id_varian"`
+	// varian dari imageproc, kosong selama StatusVarian belum ready
+	URLThumb     string  `gorm:"type:varchar(255);not null;default:''" json:"url_thumb"`
+	URLWeb       string  `gorm:"type:varchar(255);not null;default:''" json:"url_web"`
+	ThumbKey     string  `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	WebKey       string  `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	StatusVarian string  `gorm:"type:varchar(20);not null;default:'ready'" json:"status_varian"`
 	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -12,11 +12,15 @@ type LogProduk struct {
 	Deskripsi     *string    `gorm:"type:text" json:"deskripsi,omitempty"`
 	IDToko        uint64     `gorm:"not null;index" json:"id_toko"`
 	IDCategory    *uint64    `gorm:"index" json:"id_category,omitempty"`
+	// varian yang dibeli; harga di atas sudah harga varian
+	IDVarian      *uint64    `gorm:"index" json:"id_varian,omitempty"`
+	SKU           string     `gorm:"type:varchar(64);not null;default:''" json:"sku"`
+	NamaVarian    string     `gorm:"type:varchar(255);not null;default:''" json:"nama_varian"`
 	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relasi
 	Toko     *Toko     `gorm:"foreignKey:IDToko" json:"toko,omitempty"`
 	Category *Category `gorm:"foreignKey:IDCategory" json:"category,omitempty"`
-	Photos   []FotoProduk   `gorm:"foreignKey:IDProduk;references:IDProduk"`
+	Photos   []FotoProduk   `gorm:"foreignKey:IDProduk;references:IDProduk;constraint:-"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// OpsiProduk jenis pilihan varian produk, misal "Ukuran" atau "Warna"
+type OpsiProduk struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDProduk  uint64    `gorm:"not null;index" json:"id_produk"`
+	Nama      string    `gorm:"type:varchar(50);not null" json:"nama"`
+	Urutan    int       `gorm:"not null;default:0" json:"urutan"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relasi
+	Nilai []NilaiOpsi `gorm:"foreignKey:IDOpsi" json:"nilai,omitempty"`
+}
+
+// NilaiOpsi satu nilai pilihan, misal "XL" untuk opsi "Ukuran"
+type NilaiOpsi struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDOpsi uint64 `gorm:"not null;index" json:"id_opsi"`
+	Nilai  string `gorm:"type:varchar(50);not null" json:"nilai"`
+	Urutan int    `gorm:"not null;default:0" json:"urutan"`
+}
@@ -6,6 +6,7 @@ type Produk struct {
 	ID             uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
 	NamaProduk     string     `gorm:"type:varchar(150);not null;index" json:"nama_produk"` 
 	Slug           string     `gorm:"type:varchar(200);unique;not null" json:"slug"`       
+	// produk bervarian: stok total dan harga termurah dari semua varian
 	HargaReseller  int        `gorm:"not null;default:0" json:"harga_reseller"`
 	HargaKonsumen  int        `gorm:"not null;default:0" json:"harga_konsumen"`
 	Stok           int        `gorm:"not null;default:0" json:"stok"`
@@ -20,4 +21,6 @@ type Produk struct {
 	Category    *Category     `gorm:"foreignKey:IDCategory" json:"category,omitempty"`
 	FotoProduk  []FotoProduk  `gorm:"foreignKey:IDProduk" json:"foto_produk,omitempty"`
 	LogProduk   []LogProduk   `gorm:"foreignKey:IDProduk" json:"log_produk,omitempty"`
+	Opsi        []OpsiProduk   `gorm:"foreignKey:IDProduk" json:"opsi,omitempty"`
+	Varian      []VarianProduk `gorm:"foreignKey:IDProduk" json:"varian,omitempty"`
 }
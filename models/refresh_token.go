@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RefreshToken disimpan dalam bentuk hash (sha256), token asli hanya dikirim ke client
+type RefreshToken struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDUser     uint64     `gorm:"not null;index" json:"id_user"`
+	TokenHash  string     `gorm:"type:varchar(64);not null;unique" json:"-"`
+	FamilyID   string     `gorm:"type:varchar(64);not null;index" json:"family_id"`
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uint64    `json:"replaced_by,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RevokedToken berisi jti access token yang sudah logout sebelum kedaluwarsa
+type RevokedToken struct {
+	JTI       string    `gorm:"type:varchar(64);primaryKey" json:"jti"`
+	IDUser    uint64    `gorm:"not null;index" json:"id_user"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Session mewakili satu login di satu perangkat. Setiap session punya satu
+// family refresh token; access token membawa id session di klaim "sid".
+type Session struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDUser    uint64 `gorm:"not null;index" json:"id_user"`
+	FamilyID  string `gorm:"type:varchar(64);not null;unique" json:"-"`
+	Device    string `gorm:"type:varchar(100);not null" json:"device"`
+	UserAgent string `gorm:"type:varchar(500)" json:"user_agent"`
+	IP        string `gorm:"type:varchar(45)" json:"ip"`
+	// mengikuti masa berlaku refresh token terakhir di family ini
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`
+	LastSeenAt time.Time  `gorm:"not null" json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+}
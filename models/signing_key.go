@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// SigningKey adalah pasangan key untuk menandatangani JWT. Key dengan
+// ActivatesAt terbaru yang sudah lewat dipakai untuk tanda tangan; key lama
+// tetap dipublikasikan di JWKS sampai semua token yang ditandatanganinya kedaluwarsa.
+type SigningKey struct {
+	KID       string `gorm:"primaryKey;type:varchar(64)" json:"kid"`
+	Algorithm string `gorm:"type:varchar(10);not null" json:"alg"`
+	// PKCS#8 PEM, tidak pernah keluar dari server
+	PrivateKey  string    `gorm:"type:text;not null" json:"-"`
+	PublicKey   string    `gorm:"type:text;not null" json:"-"`
+	ActivatesAt time.Time `gorm:"not null;index" json:"activates_at"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// SigningKeyLock hanya berisi satu baris (ID 1) yang dikunci selama Rotate
+// berjalan, supaya dua instance tidak membuat key berikutnya bersamaan
+type SigningKeyLock struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	RotatedAt time.Time `gorm:"not null"`
+}
@@ -6,6 +6,12 @@ type Toko struct {
 	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
 	NamaToko  string     `gorm:"not null" json:"nama_toko"`
 	UrlFoto   *string    `json:"url_foto"`             
+	// foto hasil upload: UrlFoto berisi varian web setelah diproses
+	UrlFotoThumb *string `gorm:"type:varchar(255)" json:"url_foto_thumb"`
+	FotoKey      string  `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	FotoThumbKey string  `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	FotoWebKey   string  `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	FotoStatus   string  `gorm:"type:varchar(20);not null;default:''" json:"foto_status"`
 	IDUser    uint64     `gorm:"not null" json:"id_user"`
 	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
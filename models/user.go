@@ -2,19 +2,30 @@ package models
 
 import "time"
 
+// Nilai yang boleh untuk JenisKelamin (dulu enum MySQL, sekarang varchar + CHECK supaya portable)
+const (
+	JenisKelaminLakiLaki  = "Laki-laki"
+	JenisKelaminPerempuan = "Perempuan"
+)
+
 type User struct {
 	ID           uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
 	Nama         string      `gorm:"type:varchar(100);not null" json:"nama"`
-	KataSandi    string      `gorm:"type:varchar(255);not null" json:"kata_sandi"`
+	// hash bcrypt, tidak pernah ikut JSON (respons user memakai dto.User)
+	KataSandi    string      `gorm:"type:varchar(255);not null" json:"-"`
 	NoTelp       *string     `gorm:"type:varchar(20);unique" json:"no_telp"`     
 	TanggalLahir *time.Time  `json:"tanggal_lahir"`                             
-	JenisKelamin *string     `gorm:"type:enum('Laki-laki','Perempuan')" json:"jenis_kelamin"` 
+	JenisKelamin *string     `gorm:"type:varchar(20);check:chk_users_jenis_kelamin,jenis_kelamin IN ('Laki-laki','Perempuan')" json:"jenis_kelamin"` 
 	Tentang      *string     `gorm:"type:text" json:"tentang"`                  
 	Pekerjaan    *string     `gorm:"type:varchar(100)" json:"pekerjaan"`        
 	Email        string      `gorm:"type:varchar(100);unique;not null" json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
 	IDProvinsi   *string     `gorm:"type:varchar(10)" json:"id_provinsi"`       
 	IDKota       *string     `gorm:"type:varchar(10)" json:"id_kota"`           
-	IsAdmin      bool        `gorm:"not null;default:false" json:"is_admin"`
+	// bahasa respons API pilihan user ("id" / "en"), nil = ikut Accept-Language
+	Bahasa       *string     `gorm:"type:varchar(5)" json:"bahasa"`
+	// dinaikkan setiap logout semua sesi / ganti kata sandi, token lama jadi tidak berlaku
+	TokenVersion int         `gorm:"not null;default:0" json:"-"`
 	CreatedAt    time.Time   `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -22,4 +33,33 @@ type User struct {
 	Toko     *Toko     `gorm:"foreignKey:IDUser" json:"toko,omitempty"`
 	Alamat   []Alamat  `gorm:"foreignKey:IDUser" json:"alamat,omitempty"`
 	Trx      []Trx     `gorm:"foreignKey:IDUser" json:"trx,omitempty"`
+	Roles    []UserRole `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"roles,omitempty"`
+	TwoFactor *UserTwoFactor `gorm:"foreignKey:IDUser" json:"-"`
+
+	// diisi middleware saat request memakai API key: permission dibatasi ke
+	// scope key ini (nil = login biasa, tidak dibatasi)
+	Scopes []string `gorm:"-" json:"-"`
+}
+
+// RoleNames mengembalikan nama role user (Roles harus sudah di-Preload)
+func (u User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, r := range u.Roles {
+		names = append(names, r.Role)
+	}
+	return names
+}
+
+func (u User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// TwoFactorEnabled true kalau TOTP sudah aktif (TwoFactor harus sudah di-Preload)
+func (u User) TwoFactorEnabled() bool {
+	return u.TwoFactor != nil && u.TwoFactor.EnabledAt != nil
 }
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// UserIdentity menautkan akun di identity provider (SSO) ke User. Satu
+// identitas dikenali dari pasangan issuer + sub di id_token.
+type UserIdentity struct {
+	ID      uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDUser  uint64 `gorm:"not null;index" json:"id_user"`
+	Issuer  string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject" json:"issuer"`
+	Subject string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject" json:"subject"`
+	// email dari IdP saat login terakhir, hanya untuk ditampilkan
+	Email       string     `gorm:"type:varchar(100)" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// OIDCState menyimpan state, nonce dan code_verifier PKCE selama user berada
+// di halaman login IdP. Dihapus begitu callback diproses.
+type OIDCState struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	StateHash    string `gorm:"type:varchar(64);not null;unique" json:"-"`
+	Nonce        string `gorm:"type:varchar(64);not null" json:"-"`
+	CodeVerifier string `gorm:"type:varchar(128);not null" json:"-"`
+	// diisi kalau user yang sedang login menautkan akun SSO, nil = login biasa
+	IDUser    *uint64   `gorm:"index" json:"id_user"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (OIDCState) TableName() string { return "oidc_states" }
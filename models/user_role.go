@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Nama role yang dikenal aplikasi, daftar permission per role ada di package rbac
+const (
+	RoleAdmin   = "admin"
+	RoleSeller  = "seller"
+	RoleBuyer   = "buyer"
+	RoleSupport = "support"
+	RoleFinance = "finance"
+)
+
+type UserRole struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"-"`
+	IDUser    uint64    `gorm:"not null;uniqueIndex:idx_user_roles_user_role" json:"-"`
+	Role      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_user_roles_user_role" json:"role"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
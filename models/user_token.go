@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Tujuan token sekali pakai yang dikirim lewat email
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+)
+
+// UserToken adalah token sekali pakai (verifikasi email / reset kata sandi),
+// disimpan dalam bentuk hash sha256
+type UserToken struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDUser    uint64     `gorm:"not null;index" json:"id_user"`
+	Purpose   string     `gorm:"type:varchar(30);not null;index" json:"purpose"`
+	TokenHash string     `gorm:"type:varchar(64);not null;unique" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// UserTwoFactor menyimpan secret TOTP (RFC 6238). Baris dibuat saat setup,
+// 2FA baru aktif setelah EnabledAt terisi (kode pertama berhasil diverifikasi).
+type UserTwoFactor struct {
+	IDUser uint64 `gorm:"primaryKey;autoIncrement:false" json:"id_user"`
+	// secret base32, tidak pernah dikirim ulang ke client setelah setup
+	Secret    string     `gorm:"type:varchar(64);not null" json:"-"`
+	EnabledAt *time.Time `json:"enabled_at"`
+	// time step terakhir yang dipakai, mencegah kode yang sama dipakai dua kali
+	LastUsedStep int64     `gorm:"not null;default:0" json:"-"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// RecoveryCode dipakai sekali sebagai pengganti kode TOTP kalau HP hilang,
+// disimpan dalam bentuk hash sha256
+type RecoveryCode struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDUser    uint64     `gorm:"not null;index" json:"id_user"`
+	CodeHash  string     `gorm:"type:varchar(64);not null;unique" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relasi
+	User *User `gorm:"foreignKey:IDUser;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// VarianProduk satu kombinasi nilai opsi (misal XL + Merah) dengan SKU, stok
+// dan harga sendiri. Stok dan harga di Produk menjadi ringkasan semua varian.
+type VarianProduk struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	IDProduk uint64 `gorm:"not null;index;uniqueIndex:idx_varian_produk_nama" json:"id_produk"`
+	SKU      string `gorm:"type:varchar(64);not null;unique" json:"sku"`
+	// Nama label kombinasi, misal "Ukuran: XL, Warna: Merah"; unik per produk
+	Nama          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_varian_produk_nama" json:"nama"`
+	HargaReseller int       `gorm:"not null;default:0" json:"harga_reseller"`
+	HargaKonsumen int       `gorm:"not null;default:0" json:"harga_konsumen"`
+	Stok          int       `gorm:"not null;default:0" json:"stok"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relasi
+	Nilai []VarianNilai `gorm:"foreignKey:IDVarian" json:"nilai,omitempty"`
+}
+
+// VarianNilai nilai opsi yang membentuk satu varian
+type VarianNilai struct {
+	IDVarian uint64 `gorm:"primaryKey;autoIncrement:false" json:"id_varian"`
+	IDNilai  uint64 `gorm:"primaryKey;autoIncrement:false;index" json:"id_nilai"`
+}
@@ -0,0 +1,316 @@
+// Package devidp adalah identity provider OIDC minimal untuk development &
+// test lokal login SSO. Tidak ada database user: halaman login menerima email
+// & nama apa saja, sub dibuat dari email supaya user yang sama selalu dapat
+// sub yang sama. Dijalankan lewat cmd/oidc-dev-idp atau httptest di test.
+//
+// JANGAN dipakai di produksi.
+package devidp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type authCode struct {
+	RedirectURI   string
+	Challenge     string
+	Nonce         string
+	Email         string
+	Name          string
+	EmailVerified bool
+	ExpiresAt     time.Time
+}
+
+// IdP adalah http.Handler yang melayani discovery, JWKS, halaman login,
+// authorize dan token endpoint
+type IdP struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURIs []string
+	key          *rsa.PrivateKey
+	kid          string
+	mux          *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]authCode
+}
+
+// Config IdP. ClientSecret kosong = public client (PKCE saja)
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURIs []string
+}
+
+func New(cfg Config) (*IdP, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &IdP{
+		issuer:       strings.TrimSuffix(cfg.Issuer, "/"),
+		clientID:     cfg.ClientID,
+		clientSecret: cfg.ClientSecret,
+		redirectURIs: cfg.RedirectURIs,
+		key:          key,
+		kid:          "dev-" + randomString(6),
+		mux:          http.NewServeMux(),
+		codes:        map[string]authCode{},
+	}
+	p.mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	p.mux.HandleFunc("GET /jwks", p.jwks)
+	p.mux.HandleFunc("GET /authorize", p.authorizePage)
+	p.mux.HandleFunc("POST /authorize", p.authorize)
+	p.mux.HandleFunc("POST /token", p.token)
+	return p, nil
+}
+
+// Issuer dipakai relying party sebagai oidc.issuer
+func (p *IdP) Issuer() string {
+	return p.issuer
+}
+
+func (p *IdP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.ServeHTTP(w, r)
+}
+
+func (p *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+	})
+}
+
+func (p *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": p.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>Dev IdP</title></head>
+<body style="font-family:sans-serif;max-width:360px;margin:60px auto">
+<h2>Login SSO (dev)</h2>
+<form method="post" action="/authorize">
+{{range $k, $v := .}}<input type="hidden" name="{{$k}}" value="{{$v}}">
+{{end}}<p><label>Email<br><input name="email" type="email" required></label></p>
+<p><label>Nama<br><input name="name"></label></p>
+<p><label><input name="email_verified" type="checkbox" value="true" checked> email terverifikasi</label></p>
+<button type="submit">Masuk</button>
+<button type="submit" name="deny" value="1">Batal</button>
+</form></body></html>`))
+
+// authorizePage memeriksa request dari relying party lalu menampilkan form login
+func (p *IdP) authorizePage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if msg := p.checkAuthorize(q); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	hidden := map[string]string{}
+	for _, k := range []string{"redirect_uri", "state", "nonce", "code_challenge"} {
+		hidden[k] = q.Get(k)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	loginPage.Execute(w, hidden)
+}
+
+func (p *IdP) checkAuthorize(q url.Values) string {
+	switch {
+	case q.Get("client_id") != p.clientID:
+		return "client_id tidak dikenal"
+	case !p.allowedRedirect(q.Get("redirect_uri")):
+		return "redirect_uri tidak diizinkan"
+	case q.Get("response_type") != "code":
+		return "response_type harus code"
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		return "PKCE S256 wajib"
+	case !strings.Contains(" "+q.Get("scope")+" ", " openid "):
+		return "scope openid wajib"
+	}
+	return ""
+}
+
+func (p *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	redirectURI := r.PostForm.Get("redirect_uri")
+	if !p.allowedRedirect(redirectURI) {
+		http.Error(w, "redirect_uri tidak diizinkan", http.StatusBadRequest)
+		return
+	}
+
+	back := url.Values{}
+	back.Set("state", r.PostForm.Get("state"))
+	if r.PostForm.Get("deny") != "" {
+		back.Set("error", "access_denied")
+		back.Set("error_description", "user membatalkan login")
+		http.Redirect(w, r, redirectURI+"?"+back.Encode(), http.StatusFound)
+		return
+	}
+
+	email := strings.TrimSpace(r.PostForm.Get("email"))
+	if email == "" {
+		http.Error(w, "email wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString(24)
+	p.mu.Lock()
+	p.codes[code] = authCode{
+		RedirectURI:   redirectURI,
+		Challenge:     r.PostForm.Get("code_challenge"),
+		Nonce:         r.PostForm.Get("nonce"),
+		Email:         email,
+		Name:          strings.TrimSpace(r.PostForm.Get("name")),
+		EmailVerified: r.PostForm.Get("email_verified") == "true",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	back.Set("code", code)
+	http.Redirect(w, r, redirectURI+"?"+back.Encode(), http.StatusFound)
+}
+
+func (p *IdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", err.Error())
+		return
+	}
+
+	id, secret, ok := r.BasicAuth()
+	if ok {
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != p.clientID || subtle.ConstantTimeCompare([]byte(secret), []byte(p.clientSecret)) != 1 {
+		tokenError(w, "invalid_client", "client tidak dikenal")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "hanya authorization_code")
+		return
+	}
+
+	// code hanya bisa ditukar sekali
+	p.mu.Lock()
+	code, found := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	switch {
+	case !found || time.Now().After(code.ExpiresAt):
+		tokenError(w, "invalid_grant", "code tidak valid atau kedaluwarsa")
+		return
+	case r.PostForm.Get("redirect_uri") != code.RedirectURI:
+		tokenError(w, "invalid_grant", "redirect_uri tidak sama")
+		return
+	case s256(r.PostForm.Get("code_verifier")) != code.Challenge:
+		tokenError(w, "invalid_grant", "code_verifier tidak cocok")
+		return
+	}
+
+	sum := sha256.Sum256([]byte(strings.ToLower(code.Email)))
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            hex.EncodeToString(sum[:8]),
+		"aud":            p.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          code.Nonce,
+		"email":          code.Email,
+		"email_verified": code.EmailVerified,
+	}
+	if code.Name != "" {
+		claims["name"] = code.Name
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.kid
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		tokenError(w, "server_error", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(24),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (p *IdP) allowedRedirect(uri string) bool {
+	for _, allowed := range p.redirectURIs {
+		if uri != "" && uri == strings.TrimSpace(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
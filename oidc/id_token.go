@@ -0,0 +1,93 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidIDToken = errors.New("id_token dari IdP tidak valid")
+
+// Claims adalah identitas user yang sudah diverifikasi dari id_token
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Verify memeriksa tanda tangan id_token dengan JWKS IdP, lalu iss, aud,
+// exp dan nonce (OIDC Core §3.1.3.7)
+func (p *Provider) Verify(ctx context.Context, raw, nonce string) (*Claims, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	// token untuk beberapa audience wajib menyebut client kita di azp
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.cfg.ClientID {
+			return nil, fmt.Errorf("%w: azp tidak sesuai", ErrInvalidIDToken)
+		}
+	}
+	got, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce tidak sesuai", ErrInvalidIDToken)
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, fmt.Errorf("%w: klaim sub kosong", ErrInvalidIDToken)
+	}
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+
+	return &Claims{
+		Issuer:        meta.Issuer,
+		Subject:       sub,
+		Email:         strings.TrimSpace(email),
+		EmailVerified: boolClaim(claims["email_verified"]),
+		Name:          strings.TrimSpace(name),
+	}, nil
+}
+
+// beberapa IdP mengirim email_verified sebagai string "true"
+func boolClaim(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
+
+// S256Challenge menghitung code_challenge PKCE dari code_verifier (RFC 7636 §4.2)
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// ================================
+// 🔹 HANDLER /openapi.json & /docs
+// ================================
+
+// Handler menyajikan dokumen sebagai JSON. Dokumen dibangun sekali saat
+// request pertama karena route tidak berubah setelah server jalan.
+func (s *Spec) Handler() echo.HandlerFunc {
+	var (
+		once sync.Once
+		doc  *Document
+	)
+	return func(c echo.Context) error {
+		once.Do(func() { doc = s.Document() })
+		return c.JSON(http.StatusOK, doc)
+	}
+}
+
+// DocsHandler menyajikan Swagger UI (aset ter-embed, tidak butuh CDN) di
+// bawah prefix, misal "/docs", dan membaca dokumen dari specURL. Daftarkan
+// untuk prefix dan prefix+"/*".
+func DocsHandler(prefix, specURL string) echo.HandlerFunc {
+	initializer := `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: ` + strconv.Quote(specURL) + `,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    persistAuthorization: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+	files := http.StripPrefix(prefix+"/", http.FileServer(http.FS(swaggerFiles.FS)))
+
+	return func(c echo.Context) error {
+		path := c.Request().URL.Path
+		switch {
+		case path == prefix:
+			// aset di index.html memakai path relatif, jadi butuh "/" di akhir
+			return c.Redirect(http.StatusMovedPermanently, prefix+"/")
+		case strings.HasSuffix(path, "/swagger-initializer.js"):
+			return c.Blob(http.StatusOK, "application/javascript; charset=utf-8", []byte(initializer))
+		}
+		files.ServeHTTP(c.Response(), c.Request())
+		return nil
+	}
+}
@@ -0,0 +1,282 @@
+package openapi
+
+import (
+	"go-crud/validation"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ================================
+// 🔹 SCHEMA DARI STRUCT GO
+// ================================
+
+// schemaRegistry membuat Schema dari tipe Go lewat reflection. Struct bernama
+// masuk components/schemas dan dirujuk lewat $ref; nama field mengikuti tag
+// json dan batasan (required, min, max, oneof, ...) diambil dari tag validate.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// tipe dengan MarshalJSON sendiri yang bentuk JSON-nya diketahui
+var knownTypes = map[string]func() *Schema{
+	"time.Time":                func() *Schema { return &Schema{Type: "string", Format: "date-time"} },
+	"gorm.io/gorm.DeletedAt":   func() *Schema { return &Schema{Type: "string", Format: "date-time", Nullable: true} },
+	"encoding/json.RawMessage": func() *Schema { return &Schema{} },
+	// file di form multipart
+	"mime/multipart.FileHeader": func() *Schema { return &Schema{Type: "string", Format: "binary"} },
+}
+
+var nonIdent = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func (r *schemaRegistry) schemaOf(v interface{}) *Schema {
+	if v == nil {
+		return &Schema{Nullable: true}
+	}
+	return r.schema(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		s := r.schema(t.Elem())
+		if s.Ref != "" {
+			// $ref tidak boleh punya sibling di OpenAPI 3.0
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	}
+
+	if known, ok := knownTypes[t.PkgPath()+"."+t.Name()]; ok {
+		return known()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		s := &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s.AdditionalProperties = r.schema(t.Elem())
+		}
+		return s
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return ref(r.component(t))
+	}
+	// interface{} dan tipe lain: bebas
+	return &Schema{}
+}
+
+// component mendaftarkan struct bernama ke components/schemas
+func (r *schemaRegistry) component(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := componentName(t.Name())
+	if _, taken := r.schemas[name]; taken {
+		// nama sama dari package lain, misal controllers.City dan utils.City
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// daftarkan dulu sebelum isi field supaya tipe rekursif tidak berputar
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+// componentName tipe generic seperti Page[go-crud/dto.Produk] jadi PageProduk
+func componentName(name string) string {
+	base, args, generic := strings.Cut(name, "[")
+	if !generic {
+		return nonIdent.ReplaceAllString(name, "")
+	}
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		base += arg[strings.LastIndex(arg, ".")+1:]
+	}
+	return nonIdent.ReplaceAllString(base, "")
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.addFields(s, t)
+	return s
+}
+
+func (r *schemaRegistry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.SplitN(tag, ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+
+		// struct embedded tanpa tag json: field-nya ikut naik, sama seperti encoding/json
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := r.schema(f.Type)
+		if applyRules(prop, f.Type, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		if desc := f.Tag.Get("doc"); desc != "" {
+			prop = describe(prop, desc)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// describe menambah deskripsi; schema $ref dibungkus allOf karena $ref tidak
+// boleh punya sibling
+func describe(s *Schema, desc string) *Schema {
+	if s.Ref != "" {
+		return &Schema{AllOf: []*Schema{s}, Description: desc}
+	}
+	s.Description = desc
+	return s
+}
+
+// ================================
+// 🔹 TAG VALIDATE -> BATASAN SCHEMA
+// ================================
+
+// applyRules menerjemahkan tag validate ke batasan schema dan melaporkan
+// apakah field wajib diisi. Rule setelah "dive" berlaku untuk isi slice,
+// batasannya sudah ada di schema elemennya, jadi dilewati.
+func applyRules(s *Schema, t reflect.Type, tag string) (required bool) {
+	if tag == "" || tag == "-" {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if s.Ref != "" || len(s.AllOf) > 0 {
+		// batasan struct ada di component-nya sendiri
+		return strings.Contains(","+tag+",", ",required,")
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, enumValue(t, v))
+			}
+		case "min", "gte":
+			setBound(s, t, param, true)
+		case "max", "lte":
+			setBound(s, t, param, false)
+		case "len":
+			setBound(s, t, param, true)
+			setBound(s, t, param, false)
+		case "harga":
+			setBound(s, t, "0", true)
+			setBound(s, t, strconv.Itoa(validation.MaxHarga), false)
+		case "ltefield":
+			s.Description = appendSentence(s.Description, "Tidak boleh lebih besar dari "+validation.SnakeCase(param)+".")
+		default:
+			if pattern, ok := validation.Pattern(name); ok {
+				s.Pattern = pattern
+			}
+		}
+	}
+	return required
+}
+
+func setBound(s *Schema, t reflect.Type, param string, lower bool) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		if lower {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if lower {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	default:
+		f := float64(n)
+		if lower {
+			s.Minimum = &f
+		} else {
+			s.Maximum = &f
+		}
+	}
+}
+
+func enumValue(t reflect.Type, v string) interface{} {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+func appendSentence(s, sentence string) string {
+	if s == "" {
+		return sentence
+	}
+	return s + " " + sentence
+}
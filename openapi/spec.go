@@ -0,0 +1,320 @@
+package openapi
+
+import (
+	"go-crud/apperror"
+	"go-crud/utils"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ================================
+// 🔹 DAFTAR ROUTE
+// ================================
+
+// Auth cara autentikasi sebuah route
+type Auth int
+
+const (
+	Public Auth = iota
+	// Bearer hanya JWT hasil login (route tanpa RequirePermission atau
+	// AllowAPIKey, atau yang memakai middleware.DenyAPIKey)
+	Bearer
+	// BearerOrAPIKey JWT atau API key (X-API-Key)
+	BearerOrAPIKey
+)
+
+// Route dokumentasi satu endpoint. Request dan respons ditulis sebagai nilai
+// kosong dari tipe aslinya (misal controllers.LoginRequest{}), schema-nya
+// dibentuk dari struct tersebut.
+type Route struct {
+	Method      string
+	Path        string // format echo, misal /api/products/:id
+	Tag         string
+	Summary     string
+	Description string
+	Auth        Auth
+	// Permission RBAC yang dibutuhkan (middleware.RequirePermission)
+	Permission string
+	// Params menimpa parameter path bawaan dengan nama sama, misal kode
+	// wilayah :id yang berupa string
+	Params []Parameter
+	// Query struct dengan tag `query`, jadi parameter query
+	Query interface{}
+	// Body request JSON
+	Body interface{}
+	// BodyType media type Body selain JSON, misal multipart/form-data untuk upload
+	BodyType string
+	// Data isi field "data" di utils.BaseResponse
+	Data interface{}
+	// Raw respons JSON apa adanya tanpa BaseResponse (misal JWKS)
+	Raw interface{}
+	// ContentType respons selain JSON (text/plain, text/html)
+	ContentType string
+	// Status kode sukses, default 200. 302 = redirect.
+	Status int
+}
+
+// Spec kumpulan Route yang dibangun menjadi satu Document
+type Spec struct {
+	info   Info
+	tags   []Tag
+	routes []Route
+}
+
+func New(info Info, tags ...Tag) *Spec {
+	return &Spec{info: info, tags: tags}
+}
+
+func (s *Spec) Add(routes ...Route) {
+	s.routes = append(s.routes, routes...)
+}
+
+// ================================
+// 🔹 BANGUN DOKUMEN
+// ================================
+
+// Document membentuk dokumen OpenAPI dari semua route yang terdaftar
+func (s *Spec) Document() *Document {
+	reg := newSchemaRegistry()
+	reg.schemas["BaseResponse"] = reg.structSchema(reflect.TypeOf(utils.BaseResponse{}))
+	reg.schemas["Problem"] = reg.structSchema(reflect.TypeOf(apperror.Problem{}))
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    s.info,
+		Tags:    s.tags,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: reg.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "Access token dari /login atau /refresh",
+				},
+				"apiKeyAuth": {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "X-API-Key",
+					Description: "API key dari /api/api-keys",
+				},
+			},
+		},
+	}
+
+	for _, route := range s.routes {
+		path := pathTemplate(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = s.operation(reg, route)
+	}
+	return doc
+}
+
+func (s *Spec) operation(reg *schemaRegistry, route Route) *Operation {
+	op := &Operation{
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: operationID(route.Method, route.Path),
+		Responses:   map[string]*Response{},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.Permission != "" {
+		op.Description = appendSentence(op.Description, "Butuh permission `"+route.Permission+"`.")
+	}
+
+	switch route.Auth {
+	case Bearer:
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+	case BearerOrAPIKey:
+		op.Security = []map[string][]string{{"bearerAuth": {}}, {"apiKeyAuth": {}}}
+	}
+
+	op.Parameters = append(pathParameters(route.Path, route.Params), queryParameters(reg, route.Query)...)
+	op.Parameters = append(op.Parameters, Parameter{
+		Name:        "Accept-Language",
+		In:          "header",
+		Description: "Bahasa pesan respons (id, en). Default bahasa Indonesia.",
+		Schema:      &Schema{Type: "string", Example: "en"},
+	})
+
+	if route.Body != nil {
+		bodyType := route.BodyType
+		if bodyType == "" {
+			bodyType = echo.MIMEApplicationJSON
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{bodyType: {Schema: reg.schemaOf(route.Body)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = successResponse(reg, route, status)
+	op.Responses["default"] = &Response{
+		Description: "Error. Field code berisi kode error yang stabil (apperror.Code).",
+		Content: map[string]MediaType{
+			echo.MIMEApplicationJSON: {Schema: ref("BaseResponse")},
+			apperror.MIMEProblemJSON: {Schema: ref("Problem")},
+		},
+	}
+	return op
+}
+
+func successResponse(reg *schemaRegistry, route Route, status int) *Response {
+	resp := &Response{Description: http.StatusText(status)}
+	switch {
+	case status == http.StatusFound:
+		resp.Headers = map[string]Header{
+			"Location": {Description: "URL tujuan redirect", Schema: &Schema{Type: "string", Format: "uri"}},
+		}
+	case route.ContentType != "":
+		resp.Content = map[string]MediaType{route.ContentType: {Schema: &Schema{Type: "string"}}}
+	case route.Raw != nil:
+		resp.Content = map[string]MediaType{echo.MIMEApplicationJSON: {Schema: reg.schemaOf(route.Raw)}}
+	default:
+		// BaseResponse dengan field data sesuai route.Data
+		envelope := &Schema{AllOf: []*Schema{
+			ref("BaseResponse"),
+			{Type: "object", Properties: map[string]*Schema{"data": reg.schemaOf(route.Data)}},
+		}}
+		resp.Content = map[string]MediaType{echo.MIMEApplicationJSON: {Schema: envelope}}
+	}
+	return resp
+}
+
+// wildcardParam nama parameter untuk segmen * echo (misal /docs/*)
+const wildcardParam = "path"
+
+// pathTemplate /api/products/:id -> /api/products/{id}, /docs/* -> /docs/{path}
+func pathTemplate(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			parts[i] = "{" + part[1:] + "}"
+		case part == "*":
+			parts[i] = "{" + wildcardParam + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathParameters parameter dari segmen :nama; "id" dan "id_*" (misal
+// :id_foto) dianggap angka, sisanya string (misal kode wilayah
+// :province_id). Parameter di overrides dipakai apa adanya.
+func pathParameters(path string, overrides []Parameter) []Parameter {
+	var params []Parameter
+	for _, part := range strings.Split(path, "/") {
+		if part == "*" {
+			params = append(params, Parameter{Name: wildcardParam, In: "path", Required: true, Description: "Sisa path", Schema: &Schema{Type: "string"}})
+			continue
+		}
+		if !strings.HasPrefix(part, ":") {
+			continue
+		}
+		if i := slices.IndexFunc(overrides, func(p Parameter) bool { return p.Name == part[1:] }); i >= 0 {
+			param := overrides[i]
+			param.In, param.Required = "path", true
+			params = append(params, param)
+			continue
+		}
+		schema := &Schema{Type: "string"}
+		if part == ":id" || strings.HasPrefix(part, ":id_") {
+			schema = &Schema{Type: "integer", Format: "int64"}
+		}
+		params = append(params, Parameter{Name: part[1:], In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+// queryParameters satu parameter per field bertag `query`
+func queryParameters(reg *schemaRegistry, query interface{}) []Parameter {
+	if query == nil {
+		return nil
+	}
+	t := reflect.TypeOf(query)
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Tag.Get("query")
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := reg.schema(f.Type)
+		required := applyRules(schema, f.Type, f.Tag.Get("validate"))
+		params = append(params, Parameter{
+			Name:        name,
+			In:          "query",
+			Description: f.Tag.Get("doc"),
+			Required:    required,
+			Schema:      schema,
+		})
+	}
+	return params
+}
+
+// operationID GET /api/products/:id -> get_api_products_id
+func operationID(method, path string) string {
+	path = strings.ReplaceAll(path, "*", wildcardParam)
+	id := strings.ToLower(method) + "_" + nonIdent.ReplaceAllString(strings.Trim(path, "/"), "_")
+	return strings.TrimSuffix(id, "_")
+}
+
+// ================================
+// 🔹 CEK KELENGKAPAN
+// ================================
+
+// Check membandingkan route yang terdaftar di echo dengan route di spec.
+// missing = route echo yang belum didokumentasikan, stale = route di spec
+// yang tidak ada di echo. Keduanya "METHOD /path", terurut.
+func (s *Spec) Check(routes []*echo.Route) (missing, stale []string) {
+	documented := map[string]bool{}
+	for _, r := range s.routes {
+		documented[strings.ToUpper(r.Method)+" "+r.Path] = true
+	}
+
+	registered := map[string]bool{}
+	for _, r := range routes {
+		// route internal echo (RouteNotFound dari group.Use) bukan endpoint
+		if !isHTTPMethod(r.Method) {
+			continue
+		}
+		key := r.Method + " " + r.Path
+		registered[key] = true
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(stale)
+	return missing, stale
+}
+
+func isHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Result hasil pengecekan fixed window limiter
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// sisa waktu sampai window di-reset (dipakai untuk header Retry-After)
+	ResetIn time.Duration
+}
+
+// Allow menghitung satu request untuk key dan menolak kalau sudah melebihi
+// limit dalam window yang sedang berjalan
+func Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	count, ttl, err := Default.Incr(ctx, key, window)
+	if err != nil {
+		return Result{}, err
+	}
+
+	remaining := limit - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   count <= int64(limit),
+		Limit:     limit,
+		Remaining: remaining,
+		ResetIn:   ttl,
+	}, nil
+}
+
+// RetryAfterSeconds membulatkan durasi ke atas dalam detik (minimal 1) untuk header Retry-After
+func RetryAfterSeconds(d time.Duration) int {
+	s := int(math.Ceil(d.Seconds()))
+	if s < 1 {
+		return 1
+	}
+	return s
+}
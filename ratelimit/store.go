@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"go-crud/config"
+	"go-crud/lifecycle"
+	"sync"
+	"time"
+)
+
+// Store menyimpan counter dengan masa berlaku. Implementasi: MemoryStore
+// (default, hanya untuk satu instance) dan RedisStore (dibagi antar instance).
+type Store interface {
+	// Incr menambah counter, TTL hanya di-set saat key baru dibuat.
+	// Mengembalikan nilai baru dan sisa waktu sampai key kedaluwarsa.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, time.Duration, error)
+	// Get mengembalikan 0 kalau key tidak ada atau sudah kedaluwarsa
+	Get(ctx context.Context, key string) (int64, time.Duration, error)
+	Set(ctx context.Context, key string, value int64, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Default dipakai middleware dan auth, di-set oleh Init saat server start
+var Default Store = NewMemoryStore()
+
+// New membuat Store sesuai rate_limit.store
+func New(cfg config.RateLimitConfig) (Store, error) {
+	switch cfg.Store {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "redis":
+		return NewRedisStore(cfg)
+	default:
+		return nil, fmt.Errorf("rate limit store tidak dikenal: %s", cfg.Store)
+	}
+}
+
+// Init dipanggil saat server start: memasang store dan worker/hook pendukungnya
+func Init(cfg config.RateLimitConfig) error {
+	s, err := New(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch store := s.(type) {
+	case *MemoryStore:
+		store.StartSweeper(time.Minute)
+	case *RedisStore:
+		lifecycle.OnShutdown("ratelimit-redis", func(ctx context.Context) error {
+			return store.Close()
+		})
+	}
+
+	Default = s
+	return nil
+}
+
+// ================================
+// 🧠 MemoryStore
+// ================================
+
+type memoryEntry struct {
+	value     int64
+	expiresAt time.Time
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}}
+}
+
+// get harus dipanggil dengan mu terkunci
+func (s *MemoryStore) get(key string, now time.Time) (memoryEntry, bool) {
+	e, ok := s.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if !now.Before(e.expiresAt) {
+		delete(s.entries, key)
+		return memoryEntry{}, false
+	}
+	return e, true
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, ok := s.get(key, now)
+	if !ok {
+		e = memoryEntry{expiresAt: now.Add(ttl)}
+	}
+	e.value++
+	s.entries[key] = e
+	return e.value, e.expiresAt.Sub(now), nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (int64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, ok := s.get(key, now)
+	if !ok {
+		return 0, 0, nil
+	}
+	return e.value, e.expiresAt.Sub(now), nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value int64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep membuang key kedaluwarsa supaya map tidak terus membesar
+func (s *MemoryStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// StartSweeper menjalankan sweep berkala sebagai worker lifecycle
+func (s *MemoryStore) StartSweeper(interval time.Duration) {
+	lifecycle.Go("ratelimit-sweeper", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sweep()
+			}
+		}
+	})
+}
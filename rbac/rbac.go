@@ -0,0 +1,127 @@
+package rbac
+
+import (
+	"go-crud/models"
+	"sort"
+)
+
+// ================================
+// 🛡️ Permission
+// ================================
+const (
+	PermUserRead   = "user:read"   // lihat data semua user
+	PermUserWrite  = "user:write"  // ubah data user lain
+	PermUserDelete = "user:delete" // hapus user
+	PermRoleAssign = "role:assign" // atur role user
+
+	PermTokoRead   = "toko:read"   // lihat semua toko
+	PermTokoWrite  = "toko:write"  // ubah toko milik orang lain
+	PermTokoDelete = "toko:delete" // nonaktifkan toko
+
+	PermCategoryWrite = "category:write"
+	PermProductWrite  = "product:write" // kelola produk di toko sendiri
+
+	PermTransactionCreate  = "transaction:create"
+	PermTransactionReadAll = "transaction:read" // lihat transaksi semua user
+)
+
+// Daftar permission untuk setiap role
+var rolePermissions = map[string][]string{
+	models.RoleAdmin: {
+		PermUserRead, PermUserWrite, PermUserDelete, PermRoleAssign,
+		PermTokoRead, PermTokoWrite, PermTokoDelete,
+		PermCategoryWrite, PermProductWrite,
+		PermTransactionCreate, PermTransactionReadAll,
+	},
+	models.RoleSeller: {
+		PermProductWrite,
+	},
+	models.RoleBuyer: {
+		PermTransactionCreate,
+	},
+	models.RoleSupport: {
+		PermUserRead, PermTokoRead, PermTransactionReadAll,
+	},
+	models.RoleFinance: {
+		PermTransactionReadAll,
+	},
+}
+
+// Permission yang boleh dipilih untuk API key milik toko (service account toko)
+var TokoKeyPermissions = []string{PermProductWrite}
+
+// Role default untuk user yang daftar lewat /register (otomatis punya toko)
+var DefaultRoles = []string{models.RoleBuyer, models.RoleSeller}
+
+// IsValidRole mengecek nama role yang dikirim lewat API
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Roles mengembalikan semua role beserta permission-nya (untuk GET /api/roles)
+func Roles() map[string][]string {
+	result := make(map[string][]string, len(rolePermissions))
+	for role, perms := range rolePermissions {
+		result[role] = append([]string{}, perms...)
+	}
+	return result
+}
+
+// Permissions menggabungkan permission dari semua role user
+func Permissions(user models.User) []string {
+	set := map[string]bool{}
+	for _, role := range user.RoleNames() {
+		for _, p := range rolePermissions[role] {
+			if inScope(user, p) {
+				set[p] = true
+			}
+		}
+	}
+	perms := make([]string, 0, len(set))
+	for p := range set {
+		perms = append(perms, p)
+	}
+	sort.Strings(perms)
+	return perms
+}
+
+// Can mengecek apakah user punya permission tertentu (Roles harus sudah di-Preload)
+func Can(user models.User, permission string) bool {
+	if !inScope(user, permission) {
+		return false
+	}
+	for _, role := range user.RoleNames() {
+		for _, p := range rolePermissions[role] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inScope true kalau request tidak memakai API key, atau permission termasuk scope key
+func inScope(user models.User, permission string) bool {
+	if user.Scopes == nil {
+		return true
+	}
+	for _, s := range user.Scopes {
+		if s == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidPermission mengecek nama permission yang dikirim lewat API
+func IsValidPermission(permission string) bool {
+	for _, perms := range rolePermissions {
+		for _, p := range perms {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"go-crud/models"
+
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	// FindByIDs urutan hasil tidak dijamin, ID yang tidak ada dilewati
+	FindByIDs(ctx context.Context, ids []uint64) ([]models.Category, error)
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) FindByIDs(ctx context.Context, ids []uint64) ([]models.Category, error) {
+	categories := []models.Category{}
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	err := Conn(ctx, r.db).Where("id IN ?", ids).Find(&categories).Error
+	return categories, err
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"go-crud/models"
+
+	"gorm.io/gorm"
+)
+
+type FotoProdukRepository interface {
+	// FindByProduk urut menurut urutan tampil
+	FindByProduk(ctx context.Context, idProduk uint64) ([]models.FotoProduk, error)
+	FindByID(ctx context.Context, idProduk, id uint64) (*models.FotoProduk, error)
+	Create(ctx context.Context, fotos []models.FotoProduk) error
+	// SetOrder mengisi urutan sesuai posisi ID di ids (mulai dari 1)
+	SetOrder(ctx context.Context, idProduk uint64, ids []uint64) error
+	// SetPrimary menjadikan id satu-satunya foto utama produk
+	SetPrimary(ctx context.Context, idProduk, id uint64) error
+	Delete(ctx context.Context, id uint64) error
+	DeleteByProduk(ctx context.Context, idProduk uint64) error
+	// AssignVarian menjadikan foto ids (milik produk) foto khusus varian
+	// idVarian; foto varian itu yang tidak ada di ids kembali jadi foto umum
+	AssignVarian(ctx context.Context, idProduk, idVarian uint64, ids []uint64) error
+	// ClearVarian foto milik varian yang dihapus kembali jadi foto umum
+	ClearVarian(ctx context.Context, idVarian []uint64) error
+
+	// Get tanpa syarat produk, dipakai worker varian foto
+	Get(ctx context.Context, id uint64) (*models.FotoProduk, error)
+	// FindPendingVarian ID foto yang variannya belum dibuat, yang lama dulu
+	FindPendingVarian(ctx context.Context, limit int) ([]uint64, error)
+	// SetVarian menyimpan hasil varian hanya kalau foto masih ada, masih
+	// pending dan file aslinya masih storageKey; false kalau tidak
+	SetVarian(ctx context.Context, id uint64, storageKey string, fields map[string]interface{}) (bool, error)
+}
+
+type fotoProdukRepository struct {
+	db *gorm.DB
+}
+
+func NewFotoProdukRepository(db *gorm.DB) FotoProdukRepository {
+	return &fotoProdukRepository{db: db}
+}
+
+// orderFoto urutan tampil foto, dipakai juga saat Preload dari produk
+func orderFoto(db *gorm.DB) *gorm.DB {
+	return db.Order("urutan ASC, id ASC")
+}
+
+func (r *fotoProdukRepository) FindByProduk(ctx context.Context, idProduk uint64) ([]models.FotoProduk, error) {
+	fotos := []models.FotoProduk{}
+	err := orderFoto(Conn(ctx, r.db)).Where("id_produk = ?", idProduk).Find(&fotos).Error
+	return fotos, err
+}
+
+func (r *fotoProdukRepository) FindByID(ctx context.Context, idProduk, id uint64) (*models.FotoProduk, error) {
+	var foto models.FotoProduk
+	if err := Conn(ctx, r.db).Where("id_produk = ?", idProduk).First(&foto, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &foto, nil
+}
+
+func (r *fotoProdukRepository) Create(ctx context.Context, fotos []models.FotoProduk) error {
+	return Conn(ctx, r.db).Create(&fotos).Error
+}
+
+func (r *fotoProdukRepository) SetOrder(ctx context.Context, idProduk uint64, ids []uint64) error {
+	for i, id := range ids {
+		err := Conn(ctx, r.db).Model(&models.FotoProduk{}).
+			Where("id = ? AND id_produk = ?", id, idProduk).
+			Update("urutan", i+1).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fotoProdukRepository) SetPrimary(ctx context.Context, idProduk, id uint64) error {
+	// satu UPDATE supaya tidak pernah ada dua foto utama
+	return Conn(ctx, r.db).Model(&models.FotoProduk{}).
+		Where("id_produk = ?", idProduk).
+		Update("utama", gorm.Expr("CASE WHEN id = ? THEN ? ELSE ? END", id, true, false)).Error
+}
+
+func (r *fotoProdukRepository) Delete(ctx context.Context, id uint64) error {
+	return Conn(ctx, r.db).Delete(&models.FotoProduk{}, id).Error
+}
+
+func (r *fotoProdukRepository) DeleteByProduk(ctx context.Context, idProduk uint64) error {
+	return Conn(ctx, r.db).Where("id_produk = ?", idProduk).Delete(&models.FotoProduk{}).Error
+}
+
+func (r *fotoProdukRepository) AssignVarian(ctx context.Context, idProduk, idVarian uint64, ids []uint64) error {
+	if err := r.ClearVarian(ctx, []uint64{idVarian}); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return Conn(ctx, r.db).Model(&models.FotoProduk{}).
+		Where("id IN ? AND id_produk = ?", ids, idProduk).
+		Update("id_varian", idVarian).Error
+}
+
+func (r *fotoProdukRepository) ClearVarian(ctx context.Context, idVarian []uint64) error {
+	if len(idVarian) == 0 {
+		return nil
+	}
+	return Conn(ctx, r.db).Model(&models.FotoProduk{}).
+		Where("id_varian IN ?", idVarian).
+		Update("id_varian", nil).Error
+}
+
+func (r *fotoProdukRepository) Get(ctx context.Context, id uint64) (*models.FotoProduk, error) {
+	var foto models.FotoProduk
+	if err := Conn(ctx, r.db).First(&foto, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &foto, nil
+}
+
+func (r *fotoProdukRepository) FindPendingVarian(ctx context.Context, limit int) ([]uint64, error) {
+	var ids []uint64
+	err := Conn(ctx, r.db).Model(&models.FotoProduk{}).
+		Where("status_varian = ?", models.VarianPending).
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *fotoProdukRepository) SetVarian(ctx context.Context, id uint64, storageKey string, fields map[string]interface{}) (bool, error) {
+	res := Conn(ctx, r.db).Model(&models.FotoProduk{}).
+		Where("id = ? AND storage_key = ? AND status_varian = ?", id, storageKey, models.VarianPending).
+		Updates(fields)
+	return res.RowsAffected == 1, res.Error
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"go-crud/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProdukRepository interface {
+	// Search dan FindByID sudah memuat Toko, Category, FotoProduk, Opsi (dengan
+	// Nilai) dan Varian (dengan Nilai).
+	// Search mengembalikan satu halaman produk beserta total yang cocok filter.
+	Search(ctx context.Context, filter ProdukFilter) ([]models.Produk, int64, error)
+	FindByID(ctx context.Context, id uint64) (*models.Produk, error)
+	// FindByIDs urutan hasil tidak dijamin, ID yang tidak ada dilewati
+	FindByIDs(ctx context.Context, ids []uint64) ([]models.Produk, error)
+	Create(ctx context.Context, produk *models.Produk) error
+	Update(ctx context.Context, produk *models.Produk, fields map[string]interface{}) error
+	Delete(ctx context.Context, id uint64) error
+	// DecrementStock mengurangi stok secara atomik, false kalau stok tidak cukup
+	DecrementStock(ctx context.Context, id uint64, qty int) (bool, error)
+	// Lock mengunci baris produk (SELECT ... FOR UPDATE) sampai transaksi di
+	// ctx selesai. SQLite mengabaikan klausanya karena penulisannya sudah
+	// berurutan.
+	Lock(ctx context.Context, id uint64) error
+}
+
+type produkRepository struct {
+	db *gorm.DB
+}
+
+func NewProdukRepository(db *gorm.DB) ProdukRepository {
+	return &produkRepository{db: db}
+}
+
+func (r *produkRepository) withRelations(ctx context.Context) *gorm.DB {
+	return Conn(ctx, r.db).Preload("Toko").Preload("Category").Preload("FotoProduk", orderFoto).
+		Preload("Opsi", orderOpsi).Preload("Opsi.Nilai", orderOpsi).
+		Preload("Varian", orderVarian).Preload("Varian.Nilai")
+}
+
+// ================================
+// 🔹 FILTER & URUTAN
+// ================================
+
+const (
+	ProdukSortNewest    = "newest"
+	ProdukSortPriceAsc  = "price_asc"
+	ProdukSortPriceDesc = "price_desc"
+	ProdukSortName      = "name"
+)
+
+// urutan kolom per pilihan sort; id jadi pemutus supaya halaman stabil
+var produkOrders = map[string]string{
+	ProdukSortNewest:    "created_at DESC, id DESC",
+	ProdukSortPriceAsc:  "harga_konsumen ASC, id ASC",
+	ProdukSortPriceDesc: "harga_konsumen DESC, id DESC",
+	ProdukSortName:      "nama_produk ASC, id ASC",
+}
+
+// ProdukFilter field kosong / nil berarti tidak difilter
+type ProdukFilter struct {
+	// Keyword dicari di nama_produk dan deskripsi, tidak peka huruf besar
+	Keyword    string
+	IDCategory *uint64
+	IDToko     *uint64
+	// rentang harga_konsumen, inklusif
+	MinHarga *int
+	MaxHarga *int
+	InStock  bool
+	// Sort salah satu ProdukSort*, selain itu dianggap newest
+	Sort   string
+	Offset int
+	Limit  int
+}
+
+// likeEscaper karakter wildcard LIKE dari input user dicocokkan apa adanya.
+// Escape pakai '!' karena backslash diperlakukan beda oleh MySQL.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func (f ProdukFilter) apply(db *gorm.DB) *gorm.DB {
+	if keyword := strings.TrimSpace(f.Keyword); keyword != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(keyword)) + "%"
+		db = db.Where("(LOWER(nama_produk) LIKE ? ESCAPE '!' OR LOWER(deskripsi) LIKE ? ESCAPE '!')", pattern, pattern)
+	}
+	if f.IDCategory != nil {
+		db = db.Where("id_category = ?", *f.IDCategory)
+	}
+	if f.IDToko != nil {
+		db = db.Where("id_toko = ?", *f.IDToko)
+	}
+	if f.MinHarga != nil {
+		db = db.Where("harga_konsumen >= ?", *f.MinHarga)
+	}
+	if f.MaxHarga != nil {
+		db = db.Where("harga_konsumen <= ?", *f.MaxHarga)
+	}
+	if f.InStock {
+		db = db.Where("stok > 0")
+	}
+	return db
+}
+
+func (r *produkRepository) Search(ctx context.Context, filter ProdukFilter) ([]models.Produk, int64, error) {
+	var total int64
+	if err := filter.apply(Conn(ctx, r.db).Model(&models.Produk{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	products := []models.Produk{}
+	if total == 0 || int64(filter.Offset) >= total {
+		return products, total, nil
+	}
+
+	order, ok := produkOrders[filter.Sort]
+	if !ok {
+		order = produkOrders[ProdukSortNewest]
+	}
+	// relasi hanya di-Preload untuk baris di halaman ini
+	err := filter.apply(r.withRelations(ctx)).
+		Order(order).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&products).Error
+	return products, total, err
+}
+
+func (r *produkRepository) FindByID(ctx context.Context, id uint64) (*models.Produk, error) {
+	var product models.Produk
+	if err := r.withRelations(ctx).First(&product, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &product, nil
+}
+
+func (r *produkRepository) FindByIDs(ctx context.Context, ids []uint64) ([]models.Produk, error) {
+	products := []models.Produk{}
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := r.withRelations(ctx).Where("id IN ?", ids).Find(&products).Error
+	return products, err
+}
+
+func (r *produkRepository) Create(ctx context.Context, produk *models.Produk) error {
+	return Conn(ctx, r.db).Create(produk).Error
+}
+
+func (r *produkRepository) Update(ctx context.Context, produk *models.Produk, fields map[string]interface{}) error {
+	return Conn(ctx, r.db).Model(produk).Omit(clause.Associations).Updates(fields).Error
+}
+
+func (r *produkRepository) Delete(ctx context.Context, id uint64) error {
+	return Conn(ctx, r.db).Delete(&models.Produk{}, id).Error
+}
+
+func (r *produkRepository) DecrementStock(ctx context.Context, id uint64, qty int) (bool, error) {
+	// kondisi stok >= qty mencegah dua transaksi bersamaan membuat stok minus
+	res := Conn(ctx, r.db).Model(&models.Produk{}).
+		Where("id = ? AND stok >= ?", id, qty).
+		Update("stok", gorm.Expr("stok - ?", qty))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *produkRepository) Lock(ctx context.Context, id uint64) error {
+	var product models.Produk
+	err := Conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&product, id).Error
+	return notFound(err)
+}
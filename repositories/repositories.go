@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound dikembalikan repository kalau data tidak ada, supaya service
+// tidak perlu tahu gorm.ErrRecordNotFound
+var ErrNotFound = errors.New("data tidak ditemukan")
+
+// Repositories berisi semua repository GORM, dibuat sekali saat server start
+// lalu di-inject ke service
+type Repositories struct {
+	Users        UserRepository
+	Toko         TokoRepository
+	Produk       ProdukRepository
+	FotoProduk   FotoProdukRepository
+	Varian       VarianRepository
+	Categories   CategoryRepository
+	Transactions TransactionRepository
+	Tx           Transactor
+}
+
+func New(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Users:        NewUserRepository(db),
+		Toko:         NewTokoRepository(db),
+		Produk:       NewProdukRepository(db),
+		FotoProduk:   NewFotoProdukRepository(db),
+		Varian:       NewVarianRepository(db),
+		Categories:   NewCategoryRepository(db),
+		Transactions: NewTransactionRepository(db),
+		Tx:           NewTransactor(db),
+	}
+}
+
+// ================================
+// 🔹 Transaksi database
+// ================================
+
+// Transactor menjalankan beberapa operasi repository dalam satu transaksi.
+// Transaksi dibawa lewat ctx, jadi setiap repository yang menerima ctx dari
+// fn otomatis ikut transaksi yang sama.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+type txState struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return gormTransactor{db: db}
+}
+
+func (t gormTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// transaksi bersarang cukup ikut transaksi luar
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	state := &txState{}
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range state.afterCommit {
+		f()
+	}
+	return nil
+}
+
+// AfterCommit menunda fn sampai transaksi di ctx berhasil di-commit (misal
+// kirim email). Di luar transaksi fn langsung dijalankan.
+func AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// Conn mengembalikan transaksi aktif di ctx, atau db kalau tidak sedang dalam
+// transaksi. Dipakai repository dan kode lama yang masih menerima *gorm.DB.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db.WithContext(ctx)
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"go-crud/models"
+
+	"gorm.io/gorm"
+)
+
+type TokoRepository interface {
+	// semua method Find sudah memuat User pemilik toko
+	FindAll(ctx context.Context) ([]models.Toko, error)
+	FindByID(ctx context.Context, id uint64) (*models.Toko, error)
+	FindByUserID(ctx context.Context, userID uint64) (*models.Toko, error)
+	// FindByIDs urutan hasil tidak dijamin, ID yang tidak ada dilewati
+	FindByIDs(ctx context.Context, ids []uint64) ([]models.Toko, error)
+	Update(ctx context.Context, toko *models.Toko, fields map[string]interface{}) error
+
+	// Get tanpa relasi, dipakai worker varian foto
+	Get(ctx context.Context, id uint64) (*models.Toko, error)
+	// FindPendingFoto ID toko yang varian fotonya belum dibuat
+	FindPendingFoto(ctx context.Context, limit int) ([]uint64, error)
+	// SetFotoVarian menyimpan hasil varian hanya kalau foto toko masih
+	// fotoKey dan masih pending; false kalau foto sudah diganti
+	SetFotoVarian(ctx context.Context, id uint64, fotoKey string, fields map[string]interface{}) (bool, error)
+}
+
+type tokoRepository struct {
+	db *gorm.DB
+}
+
+func NewTokoRepository(db *gorm.DB) TokoRepository {
+	return &tokoRepository{db: db}
+}
+
+func (r *tokoRepository) FindAll(ctx context.Context) ([]models.Toko, error) {
+	var toko []models.Toko
+	err := Conn(ctx, r.db).Preload("User").Find(&toko).Error
+	return toko, err
+}
+
+func (r *tokoRepository) FindByID(ctx context.Context, id uint64) (*models.Toko, error) {
+	var toko models.Toko
+	if err := Conn(ctx, r.db).Preload("User").First(&toko, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &toko, nil
+}
+
+func (r *tokoRepository) FindByUserID(ctx context.Context, userID uint64) (*models.Toko, error) {
+	var toko models.Toko
+	if err := Conn(ctx, r.db).Preload("User").Where("id_user = ?", userID).First(&toko).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &toko, nil
+}
+
+func (r *tokoRepository) FindByIDs(ctx context.Context, ids []uint64) ([]models.Toko, error) {
+	toko := []models.Toko{}
+	if len(ids) == 0 {
+		return toko, nil
+	}
+	err := Conn(ctx, r.db).Preload("User").Where("id IN ?", ids).Find(&toko).Error
+	return toko, err
+}
+
+func (r *tokoRepository) Update(ctx context.Context, toko *models.Toko, fields map[string]interface{}) error {
+	return Conn(ctx, r.db).Model(toko).Updates(fields).Error
+}
+
+func (r *tokoRepository) Get(ctx context.Context, id uint64) (*models.Toko, error) {
+	var toko models.Toko
+	if err := Conn(ctx, r.db).First(&toko, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &toko, nil
+}
+
+func (r *tokoRepository) FindPendingFoto(ctx context.Context, limit int) ([]uint64, error) {
+	var ids []uint64
+	err := Conn(ctx, r.db).Model(&models.Toko{}).
+		Where("foto_status = ?", models.VarianPending).
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *tokoRepository) SetFotoVarian(ctx context.Context, id uint64, fotoKey string, fields map[string]interface{}) (bool, error) {
+	res := Conn(ctx, r.db).Model(&models.Toko{}).
+		Where("id = ? AND foto_key = ? AND foto_status = ?", id, fotoKey, models.VarianPending).
+		Updates(fields)
+	return res.RowsAffected == 1, res.Error
+}
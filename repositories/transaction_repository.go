@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"go-crud/models"
+
+	"gorm.io/gorm"
+)
+
+type TransactionRepository interface {
+	// FindAll memuat DetailTrx.LogProduk, FindByID juga memuat alamat, toko,
+	// kategori dan foto produk di setiap detail
+	FindAll(ctx context.Context) ([]models.Trx, error)
+	FindByID(ctx context.Context, id uint64) (*models.Trx, error)
+	Create(ctx context.Context, trx *models.Trx) error
+	CreateLogProduk(ctx context.Context, log *models.LogProduk) error
+	CreateDetail(ctx context.Context, detail *models.DetailTrx) error
+	UpdateTotal(ctx context.Context, trx *models.Trx, total int) error
+}
+
+type transactionRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionRepository(db *gorm.DB) TransactionRepository {
+	return &transactionRepository{db: db}
+}
+
+func (r *transactionRepository) FindAll(ctx context.Context) ([]models.Trx, error) {
+	var trans []models.Trx
+	err := Conn(ctx, r.db).Preload("DetailTrx.LogProduk").
+		Order("created_at desc").
+		Find(&trans).Error
+	return trans, err
+}
+
+func (r *transactionRepository) FindByID(ctx context.Context, id uint64) (*models.Trx, error) {
+	var trx models.Trx
+	if err := Conn(ctx, r.db).
+		Preload("Alamat").
+		Preload("DetailTrx.LogProduk.Toko").
+		Preload("DetailTrx.LogProduk.Category").
+		Preload("DetailTrx.LogProduk.Photos").
+		First(&trx, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &trx, nil
+}
+
+func (r *transactionRepository) Create(ctx context.Context, trx *models.Trx) error {
+	return Conn(ctx, r.db).Create(trx).Error
+}
+
+func (r *transactionRepository) CreateLogProduk(ctx context.Context, log *models.LogProduk) error {
+	return Conn(ctx, r.db).Create(log).Error
+}
+
+func (r *transactionRepository) CreateDetail(ctx context.Context, detail *models.DetailTrx) error {
+	return Conn(ctx, r.db).Create(detail).Error
+}
+
+func (r *transactionRepository) UpdateTotal(ctx context.Context, trx *models.Trx, total int) error {
+	if err := Conn(ctx, r.db).Model(trx).Update("harga_total", total).Error; err != nil {
+		return err
+	}
+	trx.HargaTotal = total
+	return nil
+}
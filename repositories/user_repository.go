@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"go-crud/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserRepository interface {
+	// FindAll dan FindByID sudah memuat Roles
+	FindAll(ctx context.Context) ([]models.User, error)
+	FindByID(ctx context.Context, id uint64) (*models.User, error)
+	// Save hanya menyimpan kolom user, relasi (Roles, Toko, ...) tidak ikut
+	Save(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) FindAll(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := Conn(ctx, r.db).Preload("Roles").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uint64) (*models.User, error) {
+	var user models.User
+	if err := Conn(ctx, r.db).Preload("Roles").First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Save(ctx context.Context, user *models.User) error {
+	return Conn(ctx, r.db).Omit(clause.Associations).Save(user).Error
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uint64) error {
+	return Conn(ctx, r.db).Delete(&models.User{}, id).Error
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"go-crud/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VarianRepository opsi dan varian produk. Membaca opsi dan varian cukup
+// lewat ProdukRepository yang sudah memuat keduanya.
+type VarianRepository interface {
+	// ReplaceOpsi menghapus semua opsi, nilai opsi dan hubungan varian-nilai
+	// produk lalu menyimpan opsi (beserta Nilai) baru. ID nilai baru terisi di
+	// opsi setelah berhasil.
+	ReplaceOpsi(ctx context.Context, idProduk uint64, opsi []models.OpsiProduk) error
+	Create(ctx context.Context, varian *models.VarianProduk) error
+	Update(ctx context.Context, varian *models.VarianProduk, fields map[string]interface{}) error
+	// SetNilai mengganti nilai opsi yang membentuk varian
+	SetNilai(ctx context.Context, idVarian uint64, idNilai []uint64) error
+	Delete(ctx context.Context, ids []uint64) error
+	DeleteByProduk(ctx context.Context, idProduk uint64) error
+	// SKUTaken true kalau SKU sudah dipakai varian produk lain
+	SKUTaken(ctx context.Context, sku string, idProduk uint64) (bool, error)
+	// DecrementStock mengurangi stok varian secara atomik, false kalau stok
+	// tidak cukup
+	DecrementStock(ctx context.Context, id uint64, qty int) (bool, error)
+}
+
+type varianRepository struct {
+	db *gorm.DB
+}
+
+func NewVarianRepository(db *gorm.DB) VarianRepository {
+	return &varianRepository{db: db}
+}
+
+// orderOpsi urutan opsi dan nilai opsi seperti yang diinput penjual
+func orderOpsi(db *gorm.DB) *gorm.DB {
+	return db.Order("urutan ASC, id ASC")
+}
+
+func orderVarian(db *gorm.DB) *gorm.DB {
+	return db.Order("id ASC")
+}
+
+func (r *varianRepository) ReplaceOpsi(ctx context.Context, idProduk uint64, opsi []models.OpsiProduk) error {
+	db := Conn(ctx, r.db)
+	varian := db.Model(&models.VarianProduk{}).Select("id").Where("id_produk = ?", idProduk)
+	if err := db.Where("id_varian IN (?)", varian).Delete(&models.VarianNilai{}).Error; err != nil {
+		return err
+	}
+	ids := db.Model(&models.OpsiProduk{}).Select("id").Where("id_produk = ?", idProduk)
+	if err := db.Where("id_opsi IN (?)", ids).Delete(&models.NilaiOpsi{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("id_produk = ?", idProduk).Delete(&models.OpsiProduk{}).Error; err != nil {
+		return err
+	}
+	if len(opsi) == 0 {
+		return nil
+	}
+	return db.Create(&opsi).Error
+}
+
+func (r *varianRepository) Create(ctx context.Context, varian *models.VarianProduk) error {
+	return Conn(ctx, r.db).Omit("Nilai").Create(varian).Error
+}
+
+func (r *varianRepository) Update(ctx context.Context, varian *models.VarianProduk, fields map[string]interface{}) error {
+	return Conn(ctx, r.db).Model(varian).Omit(clause.Associations).Updates(fields).Error
+}
+
+func (r *varianRepository) SetNilai(ctx context.Context, idVarian uint64, idNilai []uint64) error {
+	db := Conn(ctx, r.db)
+	if err := db.Where("id_varian = ?", idVarian).Delete(&models.VarianNilai{}).Error; err != nil {
+		return err
+	}
+	if len(idNilai) == 0 {
+		return nil
+	}
+	rows := make([]models.VarianNilai, len(idNilai))
+	for i, id := range idNilai {
+		rows[i] = models.VarianNilai{IDVarian: idVarian, IDNilai: id}
+	}
+	return db.Create(&rows).Error
+}
+
+func (r *varianRepository) Delete(ctx context.Context, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	db := Conn(ctx, r.db)
+	if err := db.Where("id_varian IN ?", ids).Delete(&models.VarianNilai{}).Error; err != nil {
+		return err
+	}
+	return db.Where("id IN ?", ids).Delete(&models.VarianProduk{}).Error
+}
+
+func (r *varianRepository) DeleteByProduk(ctx context.Context, idProduk uint64) error {
+	if err := r.ReplaceOpsi(ctx, idProduk, nil); err != nil {
+		return err
+	}
+	return Conn(ctx, r.db).Where("id_produk = ?", idProduk).Delete(&models.VarianProduk{}).Error
+}
+
+func (r *varianRepository) SKUTaken(ctx context.Context, sku string, idProduk uint64) (bool, error) {
+	var count int64
+	err := Conn(ctx, r.db).Model(&models.VarianProduk{}).
+		Where("sku = ? AND id_produk <> ?", sku, idProduk).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *varianRepository) DecrementStock(ctx context.Context, id uint64, qty int) (bool, error) {
+	res := Conn(ctx, r.db).Model(&models.VarianProduk{}).
+		Where("id = ? AND stok >= ?", id, qty).
+		Update("stok", gorm.Expr("stok - ?", qty))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
@@ -0,0 +1,195 @@
+package routes
+
+import (
+	"go-crud/auth"
+	"go-crud/controllers"
+	"go-crud/dto"
+	"go-crud/openapi"
+	"go-crud/rbac"
+	"go-crud/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ================================
+// 🔹 DOKUMENTASI OPENAPI
+// ================================
+
+// Spec daftar semua endpoint untuk /openapi.json. Setiap route baru di
+// InitRoutes wajib ditambahkan di sini juga, kalau tidak server mencatat
+// peringatan saat start dan `go run ./cmd/openapi -check` gagal.
+func Spec() *openapi.Spec {
+	spec := openapi.New(openapi.Info{
+		Title:       "go-crud API",
+		Description: "API toko online: akun, toko, produk, alamat dan transaksi. Respons JSON memakai amplop BaseResponse, kecuali JWKS.",
+		Version:     "1.0.0",
+	},
+		openapi.Tag{Name: "auth", Description: "Registrasi, login, token dan akun"},
+		openapi.Tag{Name: "2fa", Description: "Verifikasi dua langkah (TOTP)"},
+		openapi.Tag{Name: "sso", Description: "Login dan penautan akun lewat OIDC"},
+		openapi.Tag{Name: "api-keys", Description: "API key untuk integrasi mesin"},
+		openapi.Tag{Name: "sessions", Description: "Perangkat yang sedang login"},
+		openapi.Tag{Name: "users", Description: "Data user dan role"},
+		openapi.Tag{Name: "toko", Description: "Toko milik user"},
+		openapi.Tag{Name: "products", Description: "Produk di toko"},
+		openapi.Tag{Name: "categories", Description: "Kategori produk"},
+		openapi.Tag{Name: "alamat", Description: "Alamat pengiriman"},
+		openapi.Tag{Name: "transactions", Description: "Transaksi pembelian"},
+		openapi.Tag{Name: "wilayah", Description: "Data provinsi & kota (proxy API wilayah)"},
+		openapi.Tag{Name: "meta", Description: "Info server dan dokumentasi"},
+	)
+
+	wilayahID := []openapi.Parameter{{Name: "id", Description: "Kode wilayah", Schema: &openapi.Schema{Type: "string", Example: "11"}}}
+
+	// ====== PUBLIC ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/", Tag: "meta", Summary: "Pesan selamat datang", ContentType: echo.MIMETextPlainCharsetUTF8},
+		openapi.Route{Method: http.MethodGet, Path: "/.well-known/jwks.json", Tag: "meta", Summary: "Public key untuk verifikasi JWT (RFC 7517)", Raw: auth.JWKSet{}},
+		openapi.Route{Method: http.MethodGet, Path: "/openapi.json", Tag: "meta", Summary: "Dokumen OpenAPI ini", Raw: map[string]interface{}{}},
+		openapi.Route{Method: http.MethodGet, Path: "/docs", Tag: "meta", Summary: "Swagger UI", ContentType: echo.MIMETextHTMLCharsetUTF8},
+		openapi.Route{Method: http.MethodGet, Path: "/docs/*", Tag: "meta", Summary: "Aset Swagger UI", ContentType: echo.MIMETextHTMLCharsetUTF8},
+		openapi.Route{Method: http.MethodGet, Path: "/uploads/*", Tag: "meta", Summary: "File upload (storage driver local)", ContentType: "application/octet-stream"},
+
+		openapi.Route{Method: http.MethodPost, Path: "/register", Tag: "auth", Summary: "Daftar akun baru (sekaligus toko)",
+			Description: "Email yang sudah terdaftar mendapat respons yang sama; pemiliknya diberi tahu lewat email.",
+			Body:        controllers.RegisterRequest{}, Data: dto.Register{}},
+		openapi.Route{Method: http.MethodPost, Path: "/login", Tag: "auth", Summary: "Login dengan email & kata sandi",
+			Description: "Kalau 2FA aktif, data berisi TwoFactorChallengeResponse dan token didapat dari /login/2fa.",
+			Body:        controllers.LoginRequest{}, Data: dto.Login{}},
+		openapi.Route{Method: http.MethodPost, Path: "/login/2fa", Tag: "auth", Summary: "Login langkah 2: kode TOTP atau recovery code", Body: controllers.LoginTwoFactorRequest{}, Data: dto.Login{}},
+		openapi.Route{Method: http.MethodPost, Path: "/refresh", Tag: "auth", Summary: "Tukar refresh token dengan token baru", Body: controllers.RefreshRequest{}, Data: auth.TokenPair{}},
+		openapi.Route{Method: http.MethodPost, Path: "/logout", Tag: "auth", Summary: "Logout perangkat ini atau semua perangkat", Auth: openapi.Bearer, Body: controllers.LogoutRequest{}},
+		openapi.Route{Method: http.MethodPost, Path: "/email/verify", Tag: "auth", Summary: "Verifikasi email dengan token dari email", Body: controllers.VerifyEmailRequest{}},
+		openapi.Route{Method: http.MethodPost, Path: "/password/forgot", Tag: "auth", Summary: "Kirim email reset kata sandi", Body: controllers.ForgotPasswordRequest{}},
+		openapi.Route{Method: http.MethodPost, Path: "/password/reset", Tag: "auth", Summary: "Ganti kata sandi dengan token reset", Body: controllers.ResetPasswordRequest{}},
+	)
+
+	// ====== SSO (OIDC) ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/oidc/login", Tag: "sso", Summary: "Redirect ke halaman login IdP", Status: http.StatusFound},
+		openapi.Route{Method: http.MethodGet, Path: "/oidc/callback", Tag: "sso", Summary: "Callback dari IdP (redirect browser)", Query: controllers.OIDCCallbackRequest{}, Data: dto.Login{}},
+		openapi.Route{Method: http.MethodPost, Path: "/oidc/callback", Tag: "sso", Summary: "Callback dari frontend", Body: controllers.OIDCCallbackRequest{}, Data: dto.Login{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/identities", Tag: "sso", Summary: "Akun SSO yang tertaut", Auth: openapi.Bearer, Data: []dto.Identity{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/identities/link", Tag: "sso", Summary: "Mulai menautkan akun SSO", Auth: openapi.Bearer, Data: dto.AuthorizationURL{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/identities/callback", Tag: "sso", Summary: "Selesaikan penautan akun SSO", Auth: openapi.Bearer, Body: controllers.LinkIdentityRequest{}, Data: dto.Identity{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/identities/:id", Tag: "sso", Summary: "Lepas tautan akun SSO", Auth: openapi.Bearer},
+	)
+
+	// ====== PROFILE, 2FA, API KEY, SESSION ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/api/profile", Tag: "auth", Summary: "Data user dari token", Auth: openapi.BearerOrAPIKey, Data: dto.Profile{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/email/verification", Tag: "auth", Summary: "Kirim ulang email verifikasi", Auth: openapi.Bearer},
+
+		openapi.Route{Method: http.MethodGet, Path: "/api/2fa", Tag: "2fa", Summary: "Status 2FA", Auth: openapi.Bearer, Data: dto.TwoFactorStatus{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/2fa/setup", Tag: "2fa", Summary: "Buat secret TOTP & URI QR code", Auth: openapi.Bearer, Data: dto.TwoFactorSetup{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/2fa/enable", Tag: "2fa", Summary: "Aktifkan 2FA dengan kode pertama", Auth: openapi.Bearer, Body: controllers.TwoFactorCodeRequest{}, Data: dto.RecoveryCodes{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/2fa/disable", Tag: "2fa", Summary: "Nonaktifkan 2FA", Auth: openapi.Bearer, Body: controllers.DisableTwoFactorRequest{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/2fa/recovery-codes", Tag: "2fa", Summary: "Buat ulang recovery code", Auth: openapi.Bearer, Body: controllers.TwoFactorCodeRequest{}, Data: dto.RecoveryCodes{}},
+
+		openapi.Route{Method: http.MethodGet, Path: "/api/api-keys", Tag: "api-keys", Summary: "API key milik user", Auth: openapi.Bearer, Data: []dto.APIKey{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/api-keys", Tag: "api-keys", Summary: "Buat API key (key asli hanya ditampilkan sekali)", Auth: openapi.Bearer,
+			Body: controllers.CreateAPIKeyRequest{}, Data: dto.CreatedAPIKey{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/api-keys/:id", Tag: "api-keys", Summary: "Cabut API key", Auth: openapi.Bearer, Data: dto.APIKey{}},
+
+		openapi.Route{Method: http.MethodGet, Path: "/api/sessions", Tag: "sessions", Summary: "Perangkat yang sedang login", Auth: openapi.Bearer, Data: []dto.Session{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/sessions/:id", Tag: "sessions", Summary: "Keluarkan satu perangkat", Auth: openapi.Bearer},
+	)
+
+	// ====== USERS & ROLES ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/api/users", Tag: "users", Summary: "Semua user", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermUserRead, Data: []dto.UserWithWilayah{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/users/:id", Tag: "users", Summary: "Detail user (diri sendiri atau user:read)", Auth: openapi.Bearer, Data: dto.UserWithWilayah{}},
+		openapi.Route{Method: http.MethodPut, Path: "/api/users/:id", Tag: "users", Summary: "Ubah data user", Auth: openapi.Bearer, Body: controllers.UpdateUserRequest{}, Data: dto.UserWithWilayah{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/users/:id", Tag: "users", Summary: "Hapus user", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermUserDelete},
+		openapi.Route{Method: http.MethodPut, Path: "/api/users/:id/roles", Tag: "users", Summary: "Ganti seluruh role user", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermRoleAssign,
+			Body: controllers.UpdateRolesRequest{}, Data: dto.UserRoles{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/users/:id/unlock", Tag: "users", Summary: "Buka kunci login user", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermUserWrite, Data: dto.UnlockUser{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/roles", Tag: "users", Summary: "Semua role beserta permission-nya", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermRoleAssign, Data: map[string][]string{}},
+	)
+
+	// ====== TOKO ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/api/toko", Tag: "toko", Summary: "Semua toko", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermTokoRead, Data: dto.Page[dto.Toko]{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/toko/my", Tag: "toko", Summary: "Toko milik user login", Auth: openapi.BearerOrAPIKey, Data: dto.Toko{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/toko/:id", Tag: "toko", Summary: "Detail toko", Auth: openapi.BearerOrAPIKey, Data: dto.Toko{}},
+		openapi.Route{Method: http.MethodPut, Path: "/api/toko/:id", Tag: "toko", Summary: "Ubah toko (pemilik atau toko:write)", Auth: openapi.Bearer, Body: controllers.UpdateTokoRequest{}, Data: ""},
+		openapi.Route{Method: http.MethodPut, Path: "/api/toko/:id/photo", Tag: "toko", Summary: "Upload foto toko (pemilik atau toko:write)", Auth: openapi.Bearer,
+			Description: "url_foto langsung berisi file asli dengan foto_status pending. Thumbnail dan versi web dibuat di background; " +
+				"setelah foto_status ready, url_foto diganti versi web dan url_foto_thumb berisi thumbnail.",
+			Body: controllers.UploadTokoPhotoRequest{}, BodyType: echo.MIMEMultipartForm, Data: dto.Toko{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/toko/:id", Tag: "toko", Summary: "Nonaktifkan toko", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermTokoDelete, Data: ""},
+	)
+
+	// ====== PRODUK & KATEGORI ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/api/products", Tag: "products", Summary: "Daftar produk dengan filter, urutan dan pagination", Auth: openapi.BearerOrAPIKey, Query: controllers.ListProductsQuery{}, Data: dto.Page[dto.Produk]{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/products/search", Tag: "products", Summary: "Cari produk (full-text)", Auth: openapi.BearerOrAPIKey,
+			Description: "Mencari di nama_produk dan deskripsi, diurutkan menurut relevansi. Kata berimbuhan dicocokkan dengan kata dasarnya " +
+				"(\"membeli\" menemukan \"beli\") dan salah ketik kecil tetap ditemukan (\"spatu\" menemukan \"sepatu\"). " +
+				"facets berisi jumlah hasil per kategori dan toko.",
+			Query: controllers.SearchProductsQuery{}, Data: dto.ProdukSearch{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/products/:id", Tag: "products", Summary: "Detail produk", Auth: openapi.BearerOrAPIKey, Data: dto.Produk{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/products", Tag: "products", Summary: "Tambah produk di toko sendiri", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite,
+			Body: controllers.CreateProductRequest{}, Data: dto.Produk{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodPut, Path: "/api/products/:id", Tag: "products", Summary: "Ubah produk", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite,
+			Description: "Stok dan harga produk bervarian tidak bisa diubah di sini, ubah lewat variannya.",
+			Body:        controllers.UpdateProductRequest{}, Data: ""},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/products/:id", Tag: "products", Summary: "Hapus produk", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite},
+
+		openapi.Route{Method: http.MethodGet, Path: "/api/products/:id/photos", Tag: "products", Summary: "Foto produk sesuai urutan tampil", Auth: openapi.BearerOrAPIKey, Data: []dto.FotoProduk{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/products/:id/photos", Tag: "products", Summary: "Upload foto produk", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite,
+			Description: "Format dibaca dari isi file (JPEG, PNG, WebP, GIF); ukuran per file dan jumlah foto per produk dibatasi config storage. " +
+				"Foto baru ditaruh di urutan paling belakang dan foto pertama produk otomatis jadi foto utama. " +
+				"Thumbnail dan versi web dibuat di background; selama status_varian pending, url_thumb dan url_web berisi url asli.",
+			Body: controllers.UploadPhotosRequest{}, BodyType: echo.MIMEMultipartForm, Data: []dto.FotoProduk{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodPut, Path: "/api/products/:id/photos/order", Tag: "products", Summary: "Ubah urutan foto produk", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite,
+			Body: controllers.ReorderPhotosRequest{}, Data: []dto.FotoProduk{}},
+		openapi.Route{Method: http.MethodPut, Path: "/api/products/:id/photos/:id_foto/primary", Tag: "products", Summary: "Jadikan foto utama", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite, Data: []dto.FotoProduk{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/products/:id/photos/:id_foto", Tag: "products", Summary: "Hapus foto produk", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite},
+
+		openapi.Route{Method: http.MethodGet, Path: "/api/products/:id/variants", Tag: "products", Summary: "Opsi dan varian produk", Auth: openapi.BearerOrAPIKey, Data: dto.ProdukVarian{}},
+		openapi.Route{Method: http.MethodPut, Path: "/api/products/:id/variants", Tag: "products", Summary: "Ganti semua opsi dan varian produk", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite,
+			Description: "Varian dengan kombinasi opsi yang sama seperti sebelumnya tetap memakai ID dan fotonya; kombinasi yang tidak dikirim dihapus. " +
+				"Stok produk menjadi total stok varian dan harga produk menjadi harga varian termurah.",
+			Body: controllers.ReplaceVariantsRequest{}, Data: dto.ProdukVarian{}},
+		openapi.Route{Method: http.MethodPut, Path: "/api/products/:id/variants/:id_varian", Tag: "products", Summary: "Ubah SKU, harga, stok atau foto satu varian", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermProductWrite,
+			Body: controllers.UpdateVariantRequest{}, Data: dto.ProdukVarian{}},
+
+		openapi.Route{Method: http.MethodGet, Path: "/api/categories", Tag: "categories", Summary: "Semua kategori", Auth: openapi.BearerOrAPIKey, Data: []dto.Category{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/categories/:id", Tag: "categories", Summary: "Detail kategori", Auth: openapi.BearerOrAPIKey, Data: dto.Category{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/categories", Tag: "categories", Summary: "Tambah kategori", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermCategoryWrite,
+			Body: controllers.CategoryRequest{}, Data: dto.Category{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodPut, Path: "/api/categories/:id", Tag: "categories", Summary: "Ubah kategori", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermCategoryWrite,
+			Body: controllers.CategoryRequest{}, Data: dto.Category{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/categories/:id", Tag: "categories", Summary: "Hapus kategori", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermCategoryWrite},
+	)
+
+	// ====== ALAMAT ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/api/alamat/my", Tag: "alamat", Summary: "Alamat milik user login", Auth: openapi.Bearer, Data: []dto.Alamat{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/alamat", Tag: "alamat", Summary: "Tambah alamat", Auth: openapi.Bearer, Body: controllers.CreateAlamatRequest{}, Data: dto.Alamat{}},
+		openapi.Route{Method: http.MethodGet, Path: "/api/alamat/:id", Tag: "alamat", Summary: "Detail alamat beserta provinsi & kota", Auth: openapi.Bearer, Data: dto.AlamatDetail{}},
+		openapi.Route{Method: http.MethodPut, Path: "/api/alamat/:id", Tag: "alamat", Summary: "Ubah alamat", Auth: openapi.Bearer, Body: controllers.UpdateAlamatRequest{}, Data: ""},
+		openapi.Route{Method: http.MethodDelete, Path: "/api/alamat/:id", Tag: "alamat", Summary: "Hapus alamat", Auth: openapi.Bearer, Data: ""},
+	)
+
+	// ====== WILAYAH ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/provcity/listprovinces", Tag: "wilayah", Summary: "Semua provinsi", Data: []utils.Province{}},
+		openapi.Route{Method: http.MethodGet, Path: "/provcity/listcities/:province_id", Tag: "wilayah", Summary: "Kota/kabupaten di satu provinsi", Data: []utils.City{}},
+		openapi.Route{Method: http.MethodGet, Path: "/provcity/detailprovince/:id", Tag: "wilayah", Summary: "Detail provinsi", Params: wilayahID, Data: utils.Province{}},
+		openapi.Route{Method: http.MethodGet, Path: "/provcity/detailcity/:id", Tag: "wilayah", Summary: "Detail kota/kabupaten", Params: wilayahID, Data: utils.City{}},
+	)
+
+	// ====== TRANSAKSI ======
+	spec.Add(
+		openapi.Route{Method: http.MethodGet, Path: "/api/transactions", Tag: "transactions", Summary: "Semua transaksi", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermTransactionReadAll, Data: []dto.Transaction{}},
+		openapi.Route{Method: http.MethodPost, Path: "/api/transactions", Tag: "transactions", Summary: "Buat transaksi", Auth: openapi.BearerOrAPIKey, Permission: rbac.PermTransactionCreate,
+			Description: "Produk bervarian wajib memakai id_varian; harga dan stok diambil dari varian itu.",
+			Body:        controllers.CreateTransactionRequest{}, Data: dto.TransactionCreated{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodGet, Path: "/api/transactions/:id", Tag: "transactions", Summary: "Detail transaksi milik user", Auth: openapi.Bearer, Data: dto.Transaction{}},
+	)
+
+	return spec
+}
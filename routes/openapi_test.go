@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Setiap route di router harus ada di routes/openapi.go dan sebaliknya
+func TestSpecMatchesRoutes(t *testing.T) {
+	e := echo.New()
+	InitRoutes(e)
+
+	missing, stale := Spec().Check(e.Routes())
+	for _, r := range missing {
+		t.Errorf("route belum didokumentasikan: %s", r)
+	}
+	for _, r := range stale {
+		t.Errorf("route di dokumentasi tidak ada di router: %s", r)
+	}
+}
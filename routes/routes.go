@@ -1,53 +1,152 @@
 package routes
 
 import (
+	"go-crud/config"
 	"go-crud/controllers"
 	"go-crud/middleware"
+	"go-crud/openapi"
+	"go-crud/rbac"
+	"go-crud/repositories"
+	"go-crud/services"
+	"go-crud/storage"
 
 	"github.com/labstack/echo/v4"
 )
 
 func InitRoutes(e *echo.Echo) {
+	// ====== SERVICE & REPOSITORY ======
+	storageCfg := config.App.Storage
+	photoLimits := services.PhotoLimits{MaxSize: int64(storageCfg.PhotoMaxSize), MaxCount: storageCfg.PhotoMaxCount}
+	svc := services.New(repositories.New(config.DB), controllers.AccountHooks{}, photoLimits)
+	userHandler := controllers.NewUserController(svc.Users)
+	tokoHandler := controllers.NewTokoController(svc.Toko, photoLimits)
+	produkHandler := controllers.NewProdukController(svc.Produk)
+	fotoHandler := controllers.NewFotoProdukController(svc.FotoProduk, photoLimits)
+	varianHandler := controllers.NewVarianController(svc.Varian)
+	trxHandler := controllers.NewTransactionController(svc.Transactions)
+
 	// ====== ROUTE PUBLIC ======
 	e.GET("/", controllers.Home)
-	e.POST("/register", controllers.Register)
-	e.POST("/login", controllers.Login)
+	e.GET("/.well-known/jwks.json", controllers.JWKS)
+	// file upload (driver storage local)
+	e.GET(storage.LocalPrefix+"/*", storage.Handler())
+
+	// ====== DOKUMENTASI API (lihat routes/openapi.go) ======
+	e.GET("/openapi.json", Spec().Handler())
+	e.GET("/docs", openapi.DocsHandler("/docs", "/openapi.json"))
+	e.GET("/docs/*", openapi.DocsHandler("/docs", "/openapi.json"))
+
+	e.POST("/refresh", controllers.Refresh)
+	e.POST("/logout", controllers.Logout, middleware.UseJWT(), middleware.AttachUser(), middleware.DenyAPIKey())
+
+	// ====== ROUTE PUBLIC DENGAN RATE LIMIT PER IP ======
+	limits := config.App.RateLimit
+	loginLimit := middleware.RateLimitByIP("login", limits.LoginIPLimit, limits.LoginIPWindow)
+	registerLimit := middleware.RateLimitByIP("register", limits.RegisterIPLimit, limits.RegisterIPWindow)
+
+	e.POST("/login", controllers.Login, loginLimit)
+	e.POST("/login/2fa", controllers.LoginTwoFactor, loginLimit)
+	e.POST("/register", controllers.Register, registerLimit)
+	e.POST("/email/verify", controllers.VerifyEmail, registerLimit)
+	e.POST("/password/forgot", controllers.ForgotPassword, registerLimit)
+	e.POST("/password/reset", controllers.ResetPassword, registerLimit)
+
+	// ====== ROUTE LOGIN SSO (OIDC) ======
+	e.GET("/oidc/login", controllers.OIDCLogin, loginLimit)
+	e.GET("/oidc/callback", controllers.OIDCCallback, loginLimit)
+	e.POST("/oidc/callback", controllers.OIDCCallback, loginLimit)
 
 	// ====== ROUTE YANG BUTUH JWT ======
 	api := e.Group("/api")
 	api.Use(middleware.UseJWT())
+	// API key hanya bisa dipakai di route dengan RequirePermission atau AllowAPIKey
 	api.Use(middleware.AttachUser()) 
+	api.Use(middleware.RequireTwoFactorSetup())
 
 	// ====== ROUTE PROFILE ======
-	api.GET("/profile", controllers.Profile)
+	api.GET("/profile", controllers.Profile, middleware.AllowAPIKey())
+	api.POST("/email/verification", controllers.ResendVerification, middleware.DenyAPIKey())
+
+	// ====== ROUTE 2FA (TOTP) ======
+	twoFactor := api.Group("/2fa", middleware.DenyAPIKey())
+	{
+		twoFactor.GET("", controllers.GetTwoFactorStatus)
+		twoFactor.POST("/setup", controllers.SetupTwoFactor)
+		twoFactor.POST("/enable", controllers.EnableTwoFactor)
+		twoFactor.POST("/disable", controllers.DisableTwoFactor)
+		twoFactor.POST("/recovery-codes", controllers.RegenerateRecoveryCodes)
+	}
+
+	// ====== ROUTE API KEY (hanya lewat login, key tidak bisa membuat key) ======
+	apiKeys := api.Group("/api-keys", middleware.DenyAPIKey())
+	{
+		apiKeys.GET("", controllers.GetMyAPIKeys)
+		apiKeys.POST("", controllers.CreateAPIKey)
+		apiKeys.DELETE("/:id", controllers.RevokeAPIKey)
+	}
+
+	// ====== ROUTE SESSION / PERANGKAT ======
+	sessions := api.Group("/sessions", middleware.DenyAPIKey())
+	{
+		sessions.GET("", controllers.GetMySessions)
+		sessions.DELETE("/:id", controllers.DeleteMySession)
+	}
+
+	// ====== ROUTE AKUN SSO YANG TERTAUT ======
+	identities := api.Group("/identities", middleware.DenyAPIKey())
+	{
+		identities.GET("", controllers.GetMyIdentities)
+		identities.POST("/link", controllers.LinkIdentity)
+		identities.POST("/callback", controllers.LinkIdentityCallback)
+		identities.DELETE("/:id", controllers.UnlinkIdentity)
+	}
 
 	// ====== ROUTE USERS ======
 	users := api.Group("/users")
 	{
-		users.GET("", controllers.GetAllUsers)       
-		users.GET("/:id", controllers.GetUserByID)   
-		users.PUT("/:id", controllers.UpdateUser)    
-		users.DELETE("/:id", controllers.DeleteUser) 
+		users.GET("", userHandler.GetAllUsers, middleware.RequirePermission(rbac.PermUserRead))
+		users.GET("/:id", userHandler.GetUserByID)   
+		users.PUT("/:id", userHandler.UpdateUser, middleware.DenyAPIKey())
+		users.DELETE("/:id", userHandler.DeleteUser, middleware.RequirePermission(rbac.PermUserDelete))
+		users.PUT("/:id/roles", controllers.UpdateUserRoles, middleware.RequirePermission(rbac.PermRoleAssign))
+		users.POST("/:id/unlock", userHandler.UnlockUser, middleware.RequirePermission(rbac.PermUserWrite))
 	}
 
+	// ====== ROUTE ROLES ======
+	api.GET("/roles", controllers.GetRoles, middleware.RequirePermission(rbac.PermRoleAssign))
+
 	// ====== ROUTE STORES ======
 	toko := api.Group("/toko")
 	{
-		toko.GET("", controllers.GetAllToko)       
-		toko.GET("/my", controllers.GetMyToko)      
-		toko.GET("/:id", controllers.GetTokoByID)   
-		toko.PUT("/:id", controllers.UpdateToko)    
-		toko.DELETE("/:id", controllers.DeleteToko) 
+		toko.GET("", tokoHandler.GetAllToko, middleware.RequirePermission(rbac.PermTokoRead))
+		toko.GET("/my", tokoHandler.GetMyToko, middleware.AllowAPIKey())      
+		toko.GET("/:id", tokoHandler.GetTokoByID, middleware.AllowAPIKey())   
+		toko.PUT("/:id", tokoHandler.UpdateToko)    
+		toko.PUT("/:id/photo", tokoHandler.UploadTokoPhoto)
+		toko.DELETE("/:id", tokoHandler.DeleteToko, middleware.RequirePermission(rbac.PermTokoDelete))
 	}
 
 	// ====== ROUTE PRODUCTS ======
 	products := api.Group("/products")
 	{
-		products.GET("", controllers.GetAllProducts)     
-		products.GET("/:id", controllers.GetProductByID) 
-		products.POST("", controllers.CreateProduct)     
-		products.PUT("/:id", controllers.UpdateProduct)  
-		products.DELETE("/:id", controllers.DeleteProduct) 
+		products.GET("/search", produkHandler.SearchProducts, middleware.AllowAPIKey())
+		products.GET("", produkHandler.GetAllProducts, middleware.AllowAPIKey())     
+		products.GET("/:id", produkHandler.GetProductByID, middleware.AllowAPIKey()) 
+		products.POST("", produkHandler.CreateProduct, middleware.RequirePermission(rbac.PermProductWrite))
+		products.PUT("/:id", produkHandler.UpdateProduct, middleware.RequirePermission(rbac.PermProductWrite))
+		products.DELETE("/:id", produkHandler.DeleteProduct, middleware.RequirePermission(rbac.PermProductWrite))
+
+		// foto produk
+		products.GET("/:id/photos", fotoHandler.ListPhotos, middleware.AllowAPIKey())
+		products.POST("/:id/photos", fotoHandler.UploadPhotos, middleware.RequirePermission(rbac.PermProductWrite))
+		products.PUT("/:id/photos/order", fotoHandler.ReorderPhotos, middleware.RequirePermission(rbac.PermProductWrite))
+		products.PUT("/:id/photos/:id_foto/primary", fotoHandler.SetPrimaryPhoto, middleware.RequirePermission(rbac.PermProductWrite))
+		products.DELETE("/:id/photos/:id_foto", fotoHandler.DeletePhoto, middleware.RequirePermission(rbac.PermProductWrite))
+
+		// varian produk (ukuran, warna, ...)
+		products.GET("/:id/variants", varianHandler.GetVariants, middleware.AllowAPIKey())
+		products.PUT("/:id/variants", varianHandler.ReplaceVariants, middleware.RequirePermission(rbac.PermProductWrite))
+		products.PUT("/:id/variants/:id_varian", varianHandler.UpdateVariant, middleware.RequirePermission(rbac.PermProductWrite))
 	}
 
 	// ====== ROUTE ALAMAT ======
@@ -69,21 +168,21 @@ func InitRoutes(e *echo.Echo) {
 		provcity.GET("/detailcity/:id", controllers.GetDetailCity)
 	}
 
-	// ====== ROUTE KATEGORI (admin only) ======
+	// ====== ROUTE KATEGORI (tulis butuh category:write) ======
 	categories := api.Group("/categories")
 	{
-		categories.GET("", controllers.GetAllCategories)      
-		categories.GET("/:id", controllers.GetCategoryByID)      
-		categories.POST("", controllers.CreateCategory)       
-		categories.PUT("/:id", controllers.UpdateCategory)    
-		categories.DELETE("/:id", controllers.DeleteCategory) 
+		categories.GET("", controllers.GetAllCategories, middleware.AllowAPIKey())      
+		categories.GET("/:id", controllers.GetCategoryByID, middleware.AllowAPIKey())      
+		categories.POST("", controllers.CreateCategory, middleware.RequirePermission(rbac.PermCategoryWrite))
+		categories.PUT("/:id", controllers.UpdateCategory, middleware.RequirePermission(rbac.PermCategoryWrite))
+		categories.DELETE("/:id", controllers.DeleteCategory, middleware.RequirePermission(rbac.PermCategoryWrite))
 	}
 
 	// ====== ROUTE TRANSAKSI ======
 	transactions := api.Group("/transactions")
 	{   
-		transactions.GET("", controllers.GetAllTransactions)  
-		transactions.POST("", controllers.CreateTransaction)         
-		transactions.GET("/:id", controllers.GetTransactionByID)     
+		transactions.GET("", trxHandler.GetAllTransactions, middleware.RequirePermission(rbac.PermTransactionReadAll))
+		transactions.POST("", trxHandler.CreateTransaction, middleware.RequirePermission(rbac.PermTransactionCreate))
+		transactions.GET("/:id", trxHandler.GetTransactionByID)     
 	}
 }
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ================================
+// 🔹 ANALYZER
+// ================================
+
+// kata umum yang tidak membantu membedakan produk, tidak di-index dan
+// diabaikan di query
+var stopwords = map[string]struct{}{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		yang dan di ke dari untuk dengan ini itu atau pada dalam oleh
+		juga ada akan bisa sudah belum tidak bukan adalah serta agar
+		karena sebagai secara sangat lebih paling para tersebut kami
+		kita saya anda dia mereka nya pun lah kah the of and for with`) {
+		stopwords[w] = struct{}{}
+	}
+}
+
+// tokenize memecah teks menjadi kata huruf kecil; selain huruf dan angka
+// dianggap pemisah, jadi "sepatu-sepatu" menjadi dua kata
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		if _, stop := stopwords[f]; !stop {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// variants kata itu sendiri diikuti kandidat kata dasarnya
+func variants(token string) []string {
+	return append([]string{token}, Stems(token)...)
+}
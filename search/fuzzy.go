@@ -0,0 +1,56 @@
+package search
+
+// ================================
+// 🔹 TOLERANSI TYPO
+// ================================
+
+// maxEdits jumlah salah ketik yang ditoleransi menurut panjang kata. Kata
+// pendek harus persis, karena satu huruf beda sudah jadi kata lain
+// (lari / hari, teh / tes).
+func maxEdits(n int) int {
+	switch {
+	case n < 5:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance jarak Damerau-Levenshtein (optimal string alignment) antara a dan
+// b: sisip, hapus, ganti dan tukar dua huruf bersebelahan masing-masing satu
+// langkah. Berhenti lebih awal dan mengembalikan max+1 kalau jaraknya pasti
+// melebihi max.
+func distance(a, b []rune, max int) int {
+	if d := len(a) - len(b); d > max || -d > max {
+		return max + 1
+	}
+
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,373 @@
+// Package search berisi inverted index full-text yang berjalan di dalam
+// proses server (tanpa service eksternal): analyzer dengan stemming bahasa
+// Indonesia, toleransi typo, ranking BM25 dan hitungan facet.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ================================
+// 🔹 DOKUMEN & FIELD
+// ================================
+
+// Field teks yang di-index; Boost memperbesar bobot kata di field ini,
+// misal nama produk lebih penting dari deskripsi
+type Field struct {
+	Name  string
+	Boost float64
+}
+
+// Document satu entri index. Text berisi teks per nama Field, Facets nilai
+// (ID) per facet, 0 berarti dokumen tidak punya nilai untuk facet itu.
+type Document struct {
+	ID     uint64
+	Text   map[string]string
+	Facets map[string]uint64
+}
+
+type entry struct {
+	// bobot tiap term di dokumen ini (frekuensi x boost field)
+	terms  map[string]float64
+	length float64
+	facets map[string]uint64
+}
+
+// data isi index yang bisa ditukar sekaligus saat Rebuild
+type data struct {
+	docs     map[uint64]*entry
+	postings map[string]map[uint64]float64
+	totalLen float64
+}
+
+func newData() *data {
+	return &data{docs: map[uint64]*entry{}, postings: map[string]map[uint64]float64{}}
+}
+
+// ================================
+// 🔹 INDEX
+// ================================
+
+// Index aman dipakai bersamaan dari banyak goroutine
+type Index struct {
+	fields []Field
+
+	mu sync.RWMutex
+	d  *data
+	// perubahan yang masuk selama Rebuild, diputar ulang ke index baru
+	pending    []func(*data)
+	rebuilding bool
+
+	rebuildMu sync.Mutex
+}
+
+func NewIndex(fields ...Field) *Index {
+	return &Index{fields: fields, d: newData()}
+}
+
+// Len jumlah dokumen di index
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.d.docs)
+}
+
+// Put menambah atau mengganti dokumen
+func (ix *Index) Put(doc Document) {
+	e := ix.analyze(doc)
+	ix.apply(func(d *data) { d.put(doc.ID, e) })
+}
+
+// Delete menghapus dokumen, tidak apa-apa kalau ID tidak ada
+func (ix *Index) Delete(id uint64) {
+	ix.apply(func(d *data) { d.remove(id) })
+}
+
+func (ix *Index) apply(op func(*data)) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	op(ix.d)
+	if ix.rebuilding {
+		ix.pending = append(ix.pending, op)
+	}
+}
+
+// Rebuild membangun ulang index dari load, yang memanggil put untuk setiap
+// dokumen. Selama load berjalan pencarian tetap memakai index lama, dan
+// Put/Delete yang terjadi di tengah jalan ikut diterapkan ke index baru.
+func (ix *Index) Rebuild(load func(put func(Document)) error) error {
+	ix.rebuildMu.Lock()
+	defer ix.rebuildMu.Unlock()
+
+	ix.mu.Lock()
+	ix.rebuilding = true
+	ix.pending = nil
+	ix.mu.Unlock()
+
+	fresh := newData()
+	err := load(func(doc Document) { fresh.put(doc.ID, ix.analyze(doc)) })
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if err == nil {
+		for _, op := range ix.pending {
+			op(fresh)
+		}
+		ix.d = fresh
+	}
+	ix.rebuilding = false
+	ix.pending = nil
+	return err
+}
+
+func (ix *Index) analyze(doc Document) *entry {
+	e := &entry{terms: map[string]float64{}, facets: map[string]uint64{}}
+	for _, f := range ix.fields {
+		for _, token := range tokenize(doc.Text[f.Name]) {
+			for _, term := range variants(token) {
+				e.terms[term] += f.Boost
+			}
+			e.length += f.Boost
+		}
+	}
+	for name, v := range doc.Facets {
+		if v != 0 {
+			e.facets[name] = v
+		}
+	}
+	return e
+}
+
+func (d *data) put(id uint64, e *entry) {
+	d.remove(id)
+	d.docs[id] = e
+	d.totalLen += e.length
+	for term, w := range e.terms {
+		p := d.postings[term]
+		if p == nil {
+			p = map[uint64]float64{}
+			d.postings[term] = p
+		}
+		p[id] = w
+	}
+}
+
+func (d *data) remove(id uint64) {
+	old, ok := d.docs[id]
+	if !ok {
+		return
+	}
+	for term := range old.terms {
+		delete(d.postings[term], id)
+		if len(d.postings[term]) == 0 {
+			delete(d.postings, term)
+		}
+	}
+	d.totalLen -= old.length
+	delete(d.docs, id)
+}
+
+// ================================
+// 🔹 PENCARIAN
+// ================================
+
+type Request struct {
+	// Query kosong mencocokkan semua dokumen (berguna untuk facet saja)
+	Query string
+	// Filters hanya dokumen dengan nilai facet ini
+	Filters map[string]uint64
+	// Facets nama facet yang dihitung dari seluruh hasil
+	Facets []string
+	// Exact mematikan toleransi typo dan pencocokan awalan kata
+	Exact  bool
+	Offset int
+	Limit  int
+}
+
+type Hit struct {
+	ID    uint64
+	Score float64
+}
+
+type FacetCount struct {
+	Value uint64
+	Count int
+}
+
+type Result struct {
+	// Total jumlah seluruh dokumen yang cocok, Hits hanya satu halaman
+	Total  int
+	Hits   []Hit
+	Facets map[string][]FacetCount
+}
+
+// bobot kecocokan relatif terhadap kata yang persis sama
+const (
+	weightStem   = 0.8
+	weightPrefix = 0.6
+	weightTypo   = 0.7
+	bm25K1       = 1.2
+	bm25B        = 0.75
+	minPrefixLen = 3
+)
+
+// Search mencari dokumen yang memuat semua kata di query (atau variannya),
+// diurutkan dari skor BM25 tertinggi
+func (ix *Index) Search(req Request) Result {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	d := ix.d
+
+	var scores map[uint64]float64
+	if tokens := tokenize(req.Query); len(tokens) > 0 {
+		for i, token := range tokens {
+			matched := d.match(token, req.Exact)
+			if i == 0 {
+				scores = matched
+				continue
+			}
+			// semua kata di query harus ditemukan
+			for id, s := range scores {
+				if m, ok := matched[id]; ok {
+					scores[id] = s + m
+				} else {
+					delete(scores, id)
+				}
+			}
+		}
+	} else {
+		// query kosong atau hanya berisi stopword
+		scores = make(map[uint64]float64, len(d.docs))
+		for id := range d.docs {
+			scores[id] = 0
+		}
+	}
+
+	res := Result{Hits: []Hit{}, Facets: map[string][]FacetCount{}}
+	counts := map[string]map[uint64]int{}
+	for _, name := range req.Facets {
+		counts[name] = map[uint64]int{}
+	}
+	for id, score := range scores {
+		e := d.docs[id]
+		if !matchesFilters(e, req.Filters) {
+			continue
+		}
+		res.Hits = append(res.Hits, Hit{ID: id, Score: score})
+		for name, c := range counts {
+			if v := e.facets[name]; v != 0 {
+				c[v]++
+			}
+		}
+	}
+	res.Total = len(res.Hits)
+	for name, c := range counts {
+		res.Facets[name] = sortedFacets(c)
+	}
+
+	// skor sama: dokumen terbaru (ID terbesar) dulu
+	sort.Slice(res.Hits, func(i, j int) bool {
+		if res.Hits[i].Score != res.Hits[j].Score {
+			return res.Hits[i].Score > res.Hits[j].Score
+		}
+		return res.Hits[i].ID > res.Hits[j].ID
+	})
+	res.Hits = paginate(res.Hits, req.Offset, req.Limit)
+	return res
+}
+
+// match skor per dokumen untuk satu kata query. Kandidat term: kata itu
+// sendiri, kata dasarnya, lalu (kalau tidak Exact) term yang diawali kata itu
+// atau berbeda sedikit karena typo. Satu dokumen hanya mengambil kandidat
+// dengan skor tertinggi supaya kata yang sama tidak dihitung berkali-kali.
+func (d *data) match(token string, exact bool) map[uint64]float64 {
+	candidates := map[string]float64{}
+	for i, v := range variants(token) {
+		if _, ok := d.postings[v]; ok {
+			w := 1.0
+			if i > 0 {
+				w = weightStem
+			}
+			candidates[v] = w
+		}
+	}
+
+	if !exact {
+		q := []rune(token)
+		edits := maxEdits(len(q))
+		for term := range d.postings {
+			if _, ok := candidates[term]; ok {
+				continue
+			}
+			if len(q) >= minPrefixLen && strings.HasPrefix(term, token) {
+				candidates[term] = weightPrefix
+				continue
+			}
+			if edits == 0 {
+				continue
+			}
+			if n := utf8.RuneCountInString(term); n-len(q) > edits || len(q)-n > edits {
+				continue
+			}
+			if dist := distance(q, []rune(term), edits); dist <= edits {
+				candidates[term] = weightTypo / float64(dist)
+			}
+		}
+	}
+
+	scores := map[uint64]float64{}
+	n := float64(len(d.docs))
+	avgLen := d.totalLen / math.Max(n, 1)
+	for term, w := range candidates {
+		p := d.postings[term]
+		df := float64(len(p))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range p {
+			norm := bm25K1 * (1 - bm25B + bm25B*d.docs[id].length/math.Max(avgLen, 1))
+			s := w * idf * tf * (bm25K1 + 1) / (tf + norm)
+			if s > scores[id] {
+				scores[id] = s
+			}
+		}
+	}
+	return scores
+}
+
+func matchesFilters(e *entry, filters map[string]uint64) bool {
+	for name, v := range filters {
+		if e.facets[name] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedFacets dari jumlah terbanyak, jumlah sama diurutkan menurut nilai
+func sortedFacets(counts map[uint64]int) []FacetCount {
+	out := make([]FacetCount, 0, len(counts))
+	for v, c := range counts {
+		out = append(out, FacetCount{Value: v, Count: c})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+func paginate(hits []Hit, offset, limit int) []Hit {
+	if offset < 0 || offset >= len(hits) {
+		return []Hit{}
+	}
+	hits = hits[offset:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	return hits
+}
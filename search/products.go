@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"go-crud/config"
+	"go-crud/lifecycle"
+	"go-crud/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ================================
+// 🔹 INDEX PRODUK
+// ================================
+
+// nama facet produk
+const (
+	FacetCategory = "category"
+	FacetToko     = "toko"
+)
+
+// Products index pencarian produk. Dibangun dari database saat server start
+// (RebuildProducts) lalu dijaga tetap sinkron oleh ProdukService setiap
+// produk dibuat, diubah atau dihapus.
+var Products = NewIndex(
+	Field{Name: "nama_produk", Boost: 3},
+	Field{Name: "deskripsi", Boost: 1},
+)
+
+// ProductDocument dokumen index untuk satu produk
+func ProductDocument(p models.Produk) Document {
+	doc := Document{
+		ID:     p.ID,
+		Text:   map[string]string{"nama_produk": p.NamaProduk},
+		Facets: map[string]uint64{FacetToko: p.IDToko},
+	}
+	if p.Deskripsi != nil {
+		doc.Text["deskripsi"] = *p.Deskripsi
+	}
+	if p.IDCategory != nil {
+		doc.Facets[FacetCategory] = *p.IDCategory
+	}
+	return doc
+}
+
+// rebuildBatch jumlah produk yang dibaca per query saat membangun ulang index
+const rebuildBatch = 500
+
+// RebuildProducts membangun ulang Products dari tabel produks
+func RebuildProducts(db *gorm.DB) (int, error) {
+	count := 0
+	err := Products.Rebuild(func(put func(Document)) error {
+		var batch []models.Produk
+		return db.Model(&models.Produk{}).
+			Select("id", "nama_produk", "deskripsi", "id_toko", "id_category").
+			FindInBatches(&batch, rebuildBatch, func(tx *gorm.DB, _ int) error {
+				for _, p := range batch {
+					put(ProductDocument(p))
+				}
+				count += len(batch)
+				return nil
+			}).Error
+	})
+	return count, err
+}
+
+// StartProductRefresh membangun ulang Products secara berkala, supaya
+// perubahan dari instance server lain (atau langsung di database) ikut
+// terbaca. interval <= 0 mematikan refresh.
+func StartProductRefresh(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	lifecycle.Go("search-product-refresh", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := RebuildProducts(config.DB.WithContext(ctx)); err != nil {
+					log.Printf("gagal membangun ulang index produk: %v", err)
+				}
+			}
+		}
+	})
+}
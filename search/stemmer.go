@@ -0,0 +1,139 @@
+package search
+
+import "strings"
+
+// ================================
+// 🔹 STEMMER BAHASA INDONESIA
+// ================================
+// Versi ringkas algoritma Nazief-Adriani tanpa kamus kata dasar. Karena tidak
+// ada kamus, imbuhan yang ambigu (misal "memakan" bisa dari "makan" atau
+// "pakan") menghasilkan beberapa kandidat; semuanya di-index bersama kata
+// aslinya sehingga "membeli", "dibeli" dan "beli" saling menemukan.
+
+// minStem panjang minimal kata dasar, sisa yang lebih pendek dibuang
+const minStem = 3
+
+var (
+	particles   = []string{"lah", "kah", "tah", "pun"}
+	possessives = []string{"nya", "ku", "mu"}
+	// akhiran "i" sengaja tidak dilepas: terlalu banyak kata dasar berakhiran i
+	// (kopi, roti, kunci)
+	derivations = []string{"kan", "an"}
+)
+
+// Stems kandidat kata dasar dari word (huruf kecil), tanpa word itu sendiri
+func Stems(word string) []string {
+	if len([]rune(word)) <= minStem {
+		return nil
+	}
+
+	seen := map[string]bool{word: true}
+	var out []string
+	add := func(s string) {
+		if !seen[s] && len([]rune(s)) >= minStem {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+
+	base := trimSuffix(trimSuffix(word, particles), possessives)
+	add(base)
+	for _, root := range []string{base, trimSuffix(base, derivations)} {
+		suffixed := root != word
+		add(root)
+		for _, s := range stripPrefix(root, suffixed) {
+			add(s)
+			// imbuhan bertingkat: memper-, diper-, berke-, ...
+			for _, s2 := range stripPrefix(s, suffixed) {
+				add(s2)
+			}
+		}
+	}
+	return out
+}
+
+func trimSuffix(word string, suffixes []string) string {
+	for _, suf := range suffixes {
+		if strings.HasSuffix(word, suf) && len([]rune(word))-len(suf) >= minStem {
+			return strings.TrimSuffix(word, suf)
+		}
+	}
+	return word
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aiueo", b) >= 0
+}
+
+// stripPrefix kandidat setelah satu awalan dilepas. ke- dan se- hanya
+// dilepas kalau akhiran juga sudah dilepas (konfiks ke-an, se-nya), supaya
+// kata dasar seperti "kemeja" dan "sepatu" tidak ikut terpotong.
+func stripPrefix(w string, suffixed bool) []string {
+	if len(w) < 4 {
+		return nil
+	}
+	rest := func(n int) string { return w[n:] }
+
+	switch {
+	case strings.HasPrefix(w, "di"):
+		return []string{rest(2)}
+	case strings.HasPrefix(w, "ke"), strings.HasPrefix(w, "se"):
+		if suffixed {
+			return []string{rest(2)}
+		}
+		return nil
+	case strings.HasPrefix(w, "ber"), strings.HasPrefix(w, "ter"), strings.HasPrefix(w, "per"):
+		return rPrefix(w)
+	case strings.HasPrefix(w, "bel") && strings.HasPrefix(w[3:], "ajar"):
+		return []string{rest(3)}
+	case strings.HasPrefix(w, "be") && !isVowel(w[2]):
+		return []string{rest(2)}
+	case strings.HasPrefix(w, "me"), strings.HasPrefix(w, "pe"):
+		return nasalPrefix(w[2:])
+	}
+	return nil
+}
+
+// rPrefix ber-, ter-, per-: sebelum vokal huruf r bisa milik kata dasar
+// (berasa -> rasa, terawat -> rawat)
+func rPrefix(w string) []string {
+	r := w[3:]
+	if r != "" && isVowel(r[0]) {
+		return []string{r, "r" + r}
+	}
+	return []string{r}
+}
+
+// nasalPrefix menangani meN- / peN- beserta peluluhan konsonan awal kata dasar
+// (menulis -> tulis, memakai -> pakai, menyapu -> sapu, mengirim -> kirim)
+func nasalPrefix(r string) []string {
+	switch {
+	case strings.HasPrefix(r, "ng"):
+		r = r[2:]
+		if r != "" && isVowel(r[0]) {
+			return []string{r, "k" + r}
+		}
+		return []string{r}
+	case strings.HasPrefix(r, "ny"):
+		r = r[2:]
+		if r != "" && isVowel(r[0]) {
+			return []string{"s" + r}
+		}
+		return nil
+	case strings.HasPrefix(r, "m"):
+		r = r[1:]
+		if r != "" && isVowel(r[0]) {
+			return []string{"p" + r, "m" + r}
+		}
+		return []string{r}
+	case strings.HasPrefix(r, "n"):
+		r = r[1:]
+		if r != "" && isVowel(r[0]) {
+			return []string{"t" + r, "n" + r}
+		}
+		return []string{r}
+	case r != "" && strings.IndexByte("lrwy", r[0]) >= 0:
+		return []string{r}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"go-crud/apperror"
+	"go-crud/models"
+	"go-crud/repositories"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// ================================
+// 🔹 FAKE REPOSITORY
+// ================================
+// Semua fake berbagi satu fakeDB di memori. Method interface yang tidak
+// dipakai test diwarisi dari interface nil (panic kalau terpanggil).
+
+type fakeDB struct {
+	produk  map[uint64]models.Produk
+	trx     []models.Trx
+	logs    []models.LogProduk
+	details []models.DetailTrx
+	updates []map[string]interface{}
+}
+
+func newFakeDB(products ...models.Produk) *fakeDB {
+	db := &fakeDB{produk: map[uint64]models.Produk{}}
+	for _, p := range products {
+		db.produk[p.ID] = p
+	}
+	return db
+}
+
+func (db *fakeDB) clone() *fakeDB {
+	c := *db
+	c.produk = make(map[uint64]models.Produk, len(db.produk))
+	for id, p := range db.produk {
+		p.Varian = slices.Clone(p.Varian)
+		c.produk[id] = p
+	}
+	c.trx = slices.Clone(db.trx)
+	c.logs = slices.Clone(db.logs)
+	c.details = slices.Clone(db.details)
+	c.updates = slices.Clone(db.updates)
+	return &c
+}
+
+// fakeTx mengembalikan isi fakeDB ke keadaan sebelum fn kalau fn gagal,
+// seperti rollback
+type fakeTx struct{ db *fakeDB }
+
+func (t fakeTx) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	saved := t.db.clone()
+	if err := fn(ctx); err != nil {
+		*t.db = *saved
+		return err
+	}
+	return nil
+}
+
+type fakeProdukRepo struct {
+	repositories.ProdukRepository
+	db *fakeDB
+}
+
+func (r fakeProdukRepo) FindByID(ctx context.Context, id uint64) (*models.Produk, error) {
+	p, ok := r.db.produk[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	p.Varian = slices.Clone(p.Varian)
+	return &p, nil
+}
+
+func (r fakeProdukRepo) Update(ctx context.Context, produk *models.Produk, fields map[string]interface{}) error {
+	r.db.updates = append(r.db.updates, maps.Clone(fields))
+	return nil
+}
+
+func (r fakeProdukRepo) DecrementStock(ctx context.Context, id uint64, qty int) (bool, error) {
+	p := r.db.produk[id]
+	if p.Stok < qty {
+		return false, nil
+	}
+	p.Stok -= qty
+	r.db.produk[id] = p
+	return true, nil
+}
+
+type fakeVarianRepo struct {
+	repositories.VarianRepository
+	db *fakeDB
+}
+
+func (r fakeVarianRepo) DecrementStock(ctx context.Context, id uint64, qty int) (bool, error) {
+	for _, p := range r.db.produk {
+		for i := range p.Varian {
+			if v := &p.Varian[i]; v.ID == id {
+				if v.Stok < qty {
+					return false, nil
+				}
+				v.Stok -= qty
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+type fakeTrxRepo struct {
+	repositories.TransactionRepository
+	db *fakeDB
+}
+
+func (r fakeTrxRepo) Create(ctx context.Context, trx *models.Trx) error {
+	trx.ID = uint64(len(r.db.trx) + 1)
+	r.db.trx = append(r.db.trx, *trx)
+	return nil
+}
+
+func (r fakeTrxRepo) CreateLogProduk(ctx context.Context, log *models.LogProduk) error {
+	log.ID = uint64(len(r.db.logs) + 1)
+	r.db.logs = append(r.db.logs, *log)
+	return nil
+}
+
+func (r fakeTrxRepo) CreateDetail(ctx context.Context, detail *models.DetailTrx) error {
+	detail.ID = uint64(len(r.db.details) + 1)
+	r.db.details = append(r.db.details, *detail)
+	return nil
+}
+
+func (r fakeTrxRepo) UpdateTotal(ctx context.Context, trx *models.Trx, total int) error {
+	trx.HargaTotal = total
+	r.db.trx[trx.ID-1].HargaTotal = total
+	return nil
+}
+
+// ================================
+// 🔹 HELPER
+// ================================
+
+// wantAppError memastikan err adalah *apperror.Error dengan kode dan key
+// pesan tertentu; key kosong berarti tidak ada error
+func wantAppError(t *testing.T, err error, code apperror.Code, key string) {
+	t.Helper()
+	if key == "" {
+		if err != nil {
+			t.Fatalf("error tidak diharapkan: %v", err)
+		}
+		return
+	}
+	appErr, ok := apperror.As(err)
+	if !ok {
+		t.Fatalf("error %v, seharusnya %s %q", err, code, key)
+	}
+	if appErr.Code != code || appErr.Message != key {
+		t.Fatalf("error %s %q, seharusnya %s %q", appErr.Code, appErr.Message, code, key)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go-crud/apperror"
+	"go-crud/imageproc"
+	"go-crud/models"
+	"go-crud/repositories"
+	"go-crud/storage"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type FotoProdukService interface {
+	List(ctx context.Context, idProduk uint64) ([]models.FotoProduk, error)
+	// Upload menyimpan foto ke BlobStore lalu mencatatnya di belakang foto
+	// yang sudah ada. Foto pertama sebuah produk otomatis menjadi foto utama.
+	Upload(ctx context.Context, actor models.User, idProduk uint64, files []PhotoUpload) ([]models.FotoProduk, error)
+	// Reorder ids harus berisi semua foto produk tepat satu kali
+	Reorder(ctx context.Context, actor models.User, idProduk uint64, ids []uint64) ([]models.FotoProduk, error)
+	SetPrimary(ctx context.Context, actor models.User, idProduk, id uint64) ([]models.FotoProduk, error)
+	Delete(ctx context.Context, actor models.User, idProduk, id uint64) error
+}
+
+// PhotoUpload satu file dari form multipart
+type PhotoUpload struct {
+	Filename string
+	Size     int64
+	Body     io.Reader
+}
+
+// PhotoLimits batas upload, dari config storage
+type PhotoLimits struct {
+	MaxSize  int64
+	MaxCount int
+}
+
+// tipe gambar yang diterima, ditentukan dari isi file (bukan nama file atau
+// Content-Type kiriman client) beserta ekstensi key-nya
+var photoTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+type fotoProdukService struct {
+	produk repositories.ProdukRepository
+	fotos  repositories.FotoProdukRepository
+	tx     repositories.Transactor
+	blobs  storage.BlobStore
+	queue  *imageproc.Queue
+	limits PhotoLimits
+}
+
+func NewFotoProdukService(produk repositories.ProdukRepository, fotos repositories.FotoProdukRepository, tx repositories.Transactor, blobs storage.BlobStore, queue *imageproc.Queue, limits PhotoLimits) FotoProdukService {
+	return &fotoProdukService{produk: produk, fotos: fotos, tx: tx, blobs: blobs, queue: queue, limits: limits}
+}
+
+var errFotoNotFound = apperror.NotFound("photo.not_found")
+
+func (s *fotoProdukService) List(ctx context.Context, idProduk uint64) ([]models.FotoProduk, error) {
+	if _, err := s.produk.FindByID(ctx, idProduk); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errProdukNotFound
+		}
+		return nil, err
+	}
+	return s.fotos.FindByProduk(ctx, idProduk)
+}
+
+// checkPhoto memeriksa ukuran file dan tipe gambar dari isinya. Body yang
+// dikembalikan tetap berisi file utuh. Dipakai juga untuk foto toko.
+func checkPhoto(file PhotoUpload, maxSize int64) (contentType string, body io.Reader, err error) {
+	if file.Size > maxSize {
+		return "", nil, apperror.New(apperror.CodePayloadTooLarge, "photo.too_large").WithArgs(file.Filename, humanSize(maxSize))
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file.Body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", nil, err
+	}
+	head = head[:n]
+	contentType = http.DetectContentType(head)
+	if _, ok := photoTypes[contentType]; !ok {
+		return "", nil, apperror.New(apperror.CodeUnsupportedMedia, "photo.unsupported_type").WithArgs(file.Filename, contentType)
+	}
+	return contentType, io.MultiReader(bytes.NewReader(head), file.Body), nil
+}
+
+func (s *fotoProdukService) Upload(ctx context.Context, actor models.User, idProduk uint64, files []PhotoUpload) ([]models.FotoProduk, error) {
+	if len(files) == 0 {
+		return nil, apperror.BadRequest("photo.no_file")
+	}
+	if _, err := ownedProduk(ctx, s.produk, actor, idProduk, "photo.edit_forbidden"); err != nil {
+		return nil, err
+	}
+
+	// cek semua file dulu supaya tidak ada yang tersimpan kalau satu ditolak
+	bodies := make([]io.Reader, len(files))
+	types := make([]string, len(files))
+	for i, file := range files {
+		contentType, body, err := checkPhoto(file, s.limits.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+		bodies[i], types[i] = body, contentType
+	}
+
+	// blob disimpan di luar transaksi supaya baris produk tidak terkunci
+	// selama upload; kalau transaksinya gagal blob-nya dihapus lagi
+	fotos := make([]models.FotoProduk, 0, len(files))
+	for i, file := range files {
+		key, err := storage.NewKey(fmt.Sprintf("produk/%d", idProduk), photoTypes[types[i]])
+		if err == nil {
+			err = s.blobs.Put(ctx, key, bodies[i], file.Size, types[i])
+		}
+		if err != nil {
+			deleteFotoBlobs(s.blobs, fotos)
+			return nil, err
+		}
+		fotos = append(fotos, models.FotoProduk{
+			IDProduk:     idProduk,
+			URL:          s.blobs.URL(key),
+			StorageKey:   key,
+			ContentType:  types[i],
+			Ukuran:       file.Size,
+			StatusVarian: models.VarianPending,
+		})
+	}
+
+	// batas jumlah, urutan dan foto utama dihitung dari foto yang ada saat
+	// produk terkunci, jadi dua upload bersamaan tidak bisa melewati
+	// MaxCount atau sama-sama menjadi foto utama
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.produk.Lock(ctx, idProduk); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return errProdukNotFound
+			}
+			return err
+		}
+		existing, err := s.fotos.FindByProduk(ctx, idProduk)
+		if err != nil {
+			return err
+		}
+		if len(existing)+len(files) > s.limits.MaxCount {
+			return apperror.Validation("photo.limit_reached", nil).WithArgs(s.limits.MaxCount)
+		}
+
+		nextOrder, hasPrimary := 1, false
+		for _, f := range existing {
+			nextOrder = max(nextOrder, f.Urutan+1)
+			hasPrimary = hasPrimary || f.Utama
+		}
+		for i := range fotos {
+			fotos[i].Urutan = nextOrder + i
+			fotos[i].Utama = !hasPrimary && i == 0
+		}
+
+		if err := s.fotos.Create(ctx, fotos); err != nil {
+			return err
+		}
+		// varian dibuat di background, response tidak menunggu
+		repositories.AfterCommit(ctx, func() {
+			for _, f := range fotos {
+				s.queue.Enqueue(imageproc.Job{Kind: imageproc.JobFotoProduk, ID: f.ID})
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		deleteFotoBlobs(s.blobs, fotos)
+		return nil, err
+	}
+	return fotos, nil
+}
+
+func (s *fotoProdukService) Reorder(ctx context.Context, actor models.User, idProduk uint64, ids []uint64) ([]models.FotoProduk, error) {
+	if _, err := ownedProduk(ctx, s.produk, actor, idProduk, "photo.edit_forbidden"); err != nil {
+		return nil, err
+	}
+	existing, err := s.fotos.FindByProduk(ctx, idProduk)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([]uint64, len(existing))
+	for i, f := range existing {
+		current[i] = f.ID
+	}
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	slices.Sort(current)
+	if !slices.Equal(sorted, current) {
+		return nil, apperror.Validation("photo.order_mismatch", nil)
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		return s.fotos.SetOrder(ctx, idProduk, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.fotos.FindByProduk(ctx, idProduk)
+}
+
+func (s *fotoProdukService) SetPrimary(ctx context.Context, actor models.User, idProduk, id uint64) ([]models.FotoProduk, error) {
+	if _, err := ownedProduk(ctx, s.produk, actor, idProduk, "photo.edit_forbidden"); err != nil {
+		return nil, err
+	}
+	if _, err := s.foto(ctx, idProduk, id); err != nil {
+		return nil, err
+	}
+	if err := s.fotos.SetPrimary(ctx, idProduk, id); err != nil {
+		return nil, err
+	}
+	return s.fotos.FindByProduk(ctx, idProduk)
+}
+
+func (s *fotoProdukService) Delete(ctx context.Context, actor models.User, idProduk, id uint64) error {
+	if _, err := ownedProduk(ctx, s.produk, actor, idProduk, "photo.edit_forbidden"); err != nil {
+		return err
+	}
+	foto, err := s.foto(ctx, idProduk, id)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.fotos.Delete(ctx, id); err != nil {
+			return err
+		}
+		// foto utama dihapus: foto berikutnya menggantikan
+		if foto.Utama {
+			rest, err := s.fotos.FindByProduk(ctx, idProduk)
+			if err != nil {
+				return err
+			}
+			if len(rest) > 0 {
+				if err := s.fotos.SetPrimary(ctx, idProduk, rest[0].ID); err != nil {
+					return err
+				}
+			}
+		}
+		// file baru dihapus setelah commit, supaya baris yang masih ada tidak
+		// pernah menunjuk file yang sudah hilang
+		repositories.AfterCommit(ctx, func() { deleteFotoBlobs(s.blobs, []models.FotoProduk{*foto}) })
+		return nil
+	})
+}
+
+func (s *fotoProdukService) foto(ctx context.Context, idProduk, id uint64) (*models.FotoProduk, error) {
+	foto, err := s.fotos.FindByID(ctx, idProduk, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, errFotoNotFound
+	}
+	return foto, err
+}
+
+// deleteFotoBlobs membersihkan file foto beserta variannya. Dipakai juga saat
+// produk dihapus.
+func deleteFotoBlobs(blobs storage.BlobStore, fotos []models.FotoProduk) {
+	for _, f := range fotos {
+		deleteBlobs(blobs, f.StorageKey, f.ThumbKey, f.WebKey)
+	}
+}
+
+// deleteBlobs key kosong dilewati; gagal hapus hanya dicatat karena filenya
+// sudah tidak dirujuk
+func deleteBlobs(blobs storage.BlobStore, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := blobs.Delete(context.Background(), key); err != nil {
+			log.Printf("gagal menghapus file %s: %v", key, err)
+		}
+	}
+}
+
+// humanSize 5242880 -> "5 MB", 1572864 -> "1.5 MB"
+func humanSize(n int64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	v, i := float64(n), 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", v), ".0") + " " + units[i]
+}
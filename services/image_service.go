@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go-crud/imageproc"
+	"go-crud/models"
+	"go-crud/repositories"
+	"go-crud/storage"
+	"log"
+	"path"
+)
+
+// ImageService membuat varian foto produk dan foto toko. Dipanggil worker
+// imageproc.Queue, bukan dari request.
+type ImageService interface {
+	Process(ctx context.Context, job imageproc.Job) error
+	Pending(ctx context.Context, limit int) ([]imageproc.Job, error)
+}
+
+type imageService struct {
+	fotos     repositories.FotoProdukRepository
+	toko      repositories.TokoRepository
+	blobs     storage.BlobStore
+	processor imageproc.Processor
+}
+
+func NewImageService(fotos repositories.FotoProdukRepository, toko repositories.TokoRepository, blobs storage.BlobStore, processor imageproc.Processor) ImageService {
+	return &imageService{fotos: fotos, toko: toko, blobs: blobs, processor: processor}
+}
+
+// variants key dan URL varian yang sudah disimpan, per nama varian
+type variants map[string]struct{ key, url string }
+
+func (s *imageService) Process(ctx context.Context, job imageproc.Job) error {
+	switch job.Kind {
+	case imageproc.JobFotoProduk:
+		return s.processFoto(ctx, job.ID)
+	case imageproc.JobToko:
+		return s.processToko(ctx, job.ID)
+	default:
+		return fmt.Errorf("jenis job tidak dikenal: %s", job.Kind)
+	}
+}
+
+func (s *imageService) processFoto(ctx context.Context, id uint64) error {
+	foto, err := s.fotos.Get(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil // foto sudah dihapus
+	}
+	if err != nil {
+		return err
+	}
+	if foto.StatusVarian != models.VarianPending || foto.StorageKey == "" {
+		return nil
+	}
+
+	vs, err := s.build(ctx, foto.StorageKey)
+	if err != nil {
+		if !isPermanent(err) {
+			return err
+		}
+		log.Printf("varian foto produk #%d gagal dibuat: %v", id, err)
+		_, err = s.fotos.SetVarian(ctx, id, foto.StorageKey, map[string]interface{}{"status_varian": models.VarianFailed})
+		return err
+	}
+
+	thumb, web := vs[imageproc.VariantThumb], vs[imageproc.VariantWeb]
+	ok, err := s.fotos.SetVarian(ctx, id, foto.StorageKey, map[string]interface{}{
+		"url_thumb":     thumb.url,
+		"thumb_key":     thumb.key,
+		"url_web":       web.url,
+		"web_key":       web.key,
+		"status_varian": models.VarianReady,
+	})
+	if err != nil || !ok {
+		// foto dihapus atau diganti selama diproses
+		s.discard(vs)
+	}
+	return err
+}
+
+func (s *imageService) processToko(ctx context.Context, id uint64) error {
+	toko, err := s.toko.Get(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if toko.FotoStatus != models.VarianPending || toko.FotoKey == "" {
+		return nil
+	}
+
+	vs, err := s.build(ctx, toko.FotoKey)
+	if err != nil {
+		if !isPermanent(err) {
+			return err
+		}
+		log.Printf("varian foto toko #%d gagal dibuat: %v", id, err)
+		_, err = s.toko.SetFotoVarian(ctx, id, toko.FotoKey, map[string]interface{}{"foto_status": models.VarianFailed})
+		return err
+	}
+
+	// url_foto diganti varian web supaya client lama langsung dapat versi
+	// yang sudah dioptimasi; file asli tetap disimpan di foto_key
+	thumb, web := vs[imageproc.VariantThumb], vs[imageproc.VariantWeb]
+	ok, err := s.toko.SetFotoVarian(ctx, id, toko.FotoKey, map[string]interface{}{
+		"url_foto":       web.url,
+		"url_foto_thumb": thumb.url,
+		"foto_thumb_key": thumb.key,
+		"foto_web_key":   web.key,
+		"foto_status":    models.VarianReady,
+	})
+	if err != nil || !ok {
+		s.discard(vs)
+	}
+	return err
+}
+
+// build membuat semua varian dari file asli dan menyimpannya di folder yang
+// sama. Key-nya acak supaya worker lain yang memproses foto yang sama (server
+// lebih dari satu) tidak menimpa atau menghapus file yang sudah tercatat.
+func (s *imageService) build(ctx context.Context, key string) (variants, error) {
+	body, err := s.blobs.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := s.processor.Process(body)
+	body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	vs := variants{}
+	for _, out := range outputs {
+		variantKey, err := storage.NewKey(path.Dir(key), "_"+out.Name+out.Ext)
+		if err == nil {
+			err = s.blobs.Put(ctx, variantKey, bytes.NewReader(out.Data), int64(len(out.Data)), out.ContentType)
+		}
+		if err != nil {
+			s.discard(vs)
+			return nil, err
+		}
+		vs[out.Name] = struct{ key, url string }{variantKey, s.blobs.URL(variantKey)}
+	}
+	return vs, nil
+}
+
+func (s *imageService) discard(vs variants) {
+	for _, v := range vs {
+		deleteBlobs(s.blobs, v.key)
+	}
+}
+
+// isPermanent error yang tidak akan hilang kalau diproses ulang: file asli
+// rusak atau sudah tidak ada
+func isPermanent(err error) bool {
+	return errors.Is(err, imageproc.ErrInvalidImage) || errors.Is(err, storage.ErrNotFound)
+}
+
+func (s *imageService) Pending(ctx context.Context, limit int) ([]imageproc.Job, error) {
+	fotoIDs, err := s.fotos.FindPendingVarian(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	tokoIDs, err := s.toko.FindPendingFoto(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]imageproc.Job, 0, len(fotoIDs)+len(tokoIDs))
+	for _, id := range fotoIDs {
+		jobs = append(jobs, imageproc.Job{Kind: imageproc.JobFotoProduk, ID: id})
+	}
+	for _, id := range tokoIDs {
+		jobs = append(jobs, imageproc.Job{Kind: imageproc.JobToko, ID: id})
+	}
+	return jobs, nil
+}
@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/models"
+	"go-crud/repositories"
+	"go-crud/search"
+	"go-crud/storage"
+	"strings"
+)
+
+type ProdukService interface {
+	// List satu halaman produk sesuai query; page dan limit di hasil sudah dinormalisasi
+	List(ctx context.Context, query ProdukQuery) (*ProdukPage, error)
+	// Search pencarian full-text lewat index search.Products, diurutkan menurut relevansi
+	Search(ctx context.Context, query SearchProdukQuery) (*ProdukSearchPage, error)
+	Get(ctx context.Context, id uint64) (*models.Produk, error)
+	// Create menambah produk ke toko milik actor
+	Create(ctx context.Context, actor models.User, input ProdukInput) (*models.Produk, error)
+	Update(ctx context.Context, actor models.User, id uint64, input UpdateProdukInput) (*models.Produk, error)
+	Delete(ctx context.Context, actor models.User, id uint64) error
+}
+
+const (
+	DefaultProdukLimit = 20
+	MaxProdukLimit     = 100
+	// MaxProdukPage supaya (page-1)*limit tidak overflow menjadi offset
+	// negatif; halaman sejauh ini tetap kosong
+	MaxProdukPage = 10000
+)
+
+// ProdukQuery filter daftar produk; Page mulai dari 1, Sort salah satu
+// repositories.ProdukSort*
+type ProdukQuery struct {
+	Keyword    string
+	IDCategory *uint64
+	IDToko     *uint64
+	MinHarga   *int
+	MaxHarga   *int
+	InStock    bool
+	Sort       string
+	Page       int
+	Limit      int
+}
+
+type ProdukPage struct {
+	Items []models.Produk
+	Total int64
+	Page  int
+	Limit int
+}
+
+// SearchProdukQuery Keyword kosong mengembalikan semua produk (untuk facet)
+type SearchProdukQuery struct {
+	Keyword    string
+	IDCategory *uint64
+	IDToko     *uint64
+	// Exact mematikan toleransi typo dan pencocokan awalan kata
+	Exact bool
+	Page  int
+	Limit int
+}
+
+// ProdukFacet jumlah hasil pencarian per kategori / toko
+type ProdukFacet struct {
+	ID    uint64
+	Nama  string
+	Count int
+}
+
+type ProdukSearchPage struct {
+	ProdukPage
+	Categories []ProdukFacet
+	Toko       []ProdukFacet
+}
+
+type ProdukInput struct {
+	NamaProduk    string
+	HargaReseller int
+	HargaKonsumen int
+	Stok          int
+	Deskripsi     *string
+	IDCategory    *uint64
+}
+
+// UpdateProdukInput: field nil berarti tidak diubah
+type UpdateProdukInput struct {
+	NamaProduk    *string
+	HargaReseller *int
+	HargaKonsumen *int
+	Stok          *int
+	Deskripsi     *string
+	IDCategory    *uint64
+}
+
+type produkService struct {
+	produk     repositories.ProdukRepository
+	toko       repositories.TokoRepository
+	categories repositories.CategoryRepository
+	fotos      repositories.FotoProdukRepository
+	varian     repositories.VarianRepository
+	tx         repositories.Transactor
+	// blobs file foto produk, ikut dihapus bersama produknya
+	blobs storage.BlobStore
+	// index diperbarui setelah perubahan produk di-commit
+	index *search.Index
+}
+
+func NewProdukService(produk repositories.ProdukRepository, toko repositories.TokoRepository, categories repositories.CategoryRepository, fotos repositories.FotoProdukRepository, varian repositories.VarianRepository, tx repositories.Transactor, blobs storage.BlobStore, index *search.Index) ProdukService {
+	return &produkService{produk: produk, toko: toko, categories: categories, fotos: fotos, varian: varian, tx: tx, blobs: blobs, index: index}
+}
+
+var errProdukNotFound = apperror.NotFound("product.not_found")
+
+// Slug dibuat dari nama produk, juga dipakai untuk snapshot di log_produk
+func Slug(nama string) string {
+	return strings.ToLower(strings.ReplaceAll(nama, " ", "-"))
+}
+
+// normalizePage halaman mulai dari 1 dan paling banyak MaxProdukPage, limit
+// default DefaultProdukLimit dan paling banyak MaxProdukLimit
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if page > MaxProdukPage {
+		page = MaxProdukPage
+	}
+	if limit < 1 {
+		limit = DefaultProdukLimit
+	}
+	if limit > MaxProdukLimit {
+		limit = MaxProdukLimit
+	}
+	return page, limit
+}
+
+func (s *produkService) List(ctx context.Context, query ProdukQuery) (*ProdukPage, error) {
+	if query.MinHarga != nil && query.MaxHarga != nil && *query.MinHarga > *query.MaxHarga {
+		return nil, apperror.Validation("product.invalid_price_range", nil)
+	}
+	query.Page, query.Limit = normalizePage(query.Page, query.Limit)
+	if query.Sort == "" {
+		query.Sort = repositories.ProdukSortNewest
+	}
+
+	items, total, err := s.produk.Search(ctx, repositories.ProdukFilter{
+		Keyword:    query.Keyword,
+		IDCategory: query.IDCategory,
+		IDToko:     query.IDToko,
+		MinHarga:   query.MinHarga,
+		MaxHarga:   query.MaxHarga,
+		InStock:    query.InStock,
+		Sort:       query.Sort,
+		Offset:     (query.Page - 1) * query.Limit,
+		Limit:      query.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ProdukPage{Items: items, Total: total, Page: query.Page, Limit: query.Limit}, nil
+}
+
+func (s *produkService) Search(ctx context.Context, query SearchProdukQuery) (*ProdukSearchPage, error) {
+	query.Page, query.Limit = normalizePage(query.Page, query.Limit)
+	filters := map[string]uint64{}
+	if query.IDCategory != nil {
+		filters[search.FacetCategory] = *query.IDCategory
+	}
+	if query.IDToko != nil {
+		filters[search.FacetToko] = *query.IDToko
+	}
+
+	res := s.index.Search(search.Request{
+		Query:   query.Keyword,
+		Filters: filters,
+		Facets:  []string{search.FacetCategory, search.FacetToko},
+		Exact:   query.Exact,
+		Offset:  (query.Page - 1) * query.Limit,
+		Limit:   query.Limit,
+	})
+
+	// index hanya menyimpan ID, data produk diambil dari database dengan
+	// urutan relevansi dari index
+	ids := make([]uint64, len(res.Hits))
+	for i, hit := range res.Hits {
+		ids[i] = hit.ID
+	}
+	found, err := s.produk.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint64]models.Produk, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+	items := make([]models.Produk, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			items = append(items, p)
+		}
+	}
+
+	page := &ProdukSearchPage{
+		ProdukPage: ProdukPage{Items: items, Total: int64(res.Total), Page: query.Page, Limit: query.Limit},
+	}
+	if page.Categories, err = s.categoryFacets(ctx, res.Facets[search.FacetCategory]); err != nil {
+		return nil, err
+	}
+	if page.Toko, err = s.tokoFacets(ctx, res.Facets[search.FacetToko]); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// categoryFacets melengkapi facet dengan nama kategori; kategori yang sudah
+// dihapus (index belum di-refresh) dilewati
+func (s *produkService) categoryFacets(ctx context.Context, counts []search.FacetCount) ([]ProdukFacet, error) {
+	categories, err := s.categories.FindByIDs(ctx, facetIDs(counts))
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint64]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.NamaCategory
+	}
+	return namedFacets(counts, names), nil
+}
+
+func (s *produkService) tokoFacets(ctx context.Context, counts []search.FacetCount) ([]ProdukFacet, error) {
+	toko, err := s.toko.FindByIDs(ctx, facetIDs(counts))
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint64]string, len(toko))
+	for _, t := range toko {
+		names[t.ID] = t.NamaToko
+	}
+	return namedFacets(counts, names), nil
+}
+
+func facetIDs(counts []search.FacetCount) []uint64 {
+	ids := make([]uint64, len(counts))
+	for i, c := range counts {
+		ids[i] = c.Value
+	}
+	return ids
+}
+
+func namedFacets(counts []search.FacetCount, names map[uint64]string) []ProdukFacet {
+	facets := make([]ProdukFacet, 0, len(counts))
+	for _, c := range counts {
+		if nama, ok := names[c.Value]; ok {
+			facets = append(facets, ProdukFacet{ID: c.Value, Nama: nama, Count: c.Count})
+		}
+	}
+	return facets
+}
+
+func (s *produkService) Get(ctx context.Context, id uint64) (*models.Produk, error) {
+	product, err := s.produk.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, errProdukNotFound
+	}
+	return product, err
+}
+
+func (s *produkService) Create(ctx context.Context, actor models.User, input ProdukInput) (*models.Produk, error) {
+	store, err := s.toko.FindByUserID(ctx, actor.ID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, apperror.Forbidden("product.no_toko")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	product := models.Produk{
+		NamaProduk:    input.NamaProduk,
+		Slug:          Slug(input.NamaProduk),
+		HargaReseller: input.HargaReseller,
+		HargaKonsumen: input.HargaKonsumen,
+		Stok:          input.Stok,
+		Deskripsi:     input.Deskripsi,
+		IDToko:        store.ID,
+		IDCategory:    input.IDCategory,
+	}
+	if err := s.produk.Create(ctx, &product); err != nil {
+		return nil, err
+	}
+	s.reindex(ctx, product)
+	return &product, nil
+}
+
+// owned mengambil produk dan memastikan produk itu milik toko actor
+func (s *produkService) owned(ctx context.Context, actor models.User, id uint64, forbidden string) (*models.Produk, error) {
+	return ownedProduk(ctx, s.produk, actor, id, forbidden)
+}
+
+// ownedProduk dipakai juga oleh service foto produk
+func ownedProduk(ctx context.Context, produk repositories.ProdukRepository, actor models.User, id uint64, forbidden string) (*models.Produk, error) {
+	product, err := produk.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, errProdukNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if product.Toko == nil {
+		return nil, apperror.Forbidden("toko.not_found")
+	}
+	if product.Toko.IDUser != actor.ID {
+		return nil, apperror.Forbidden(forbidden)
+	}
+	return product, nil
+}
+
+func (s *produkService) Update(ctx context.Context, actor models.User, id uint64, input UpdateProdukInput) (*models.Produk, error) {
+	product, err := s.owned(ctx, actor, id, "product.edit_forbidden")
+	if err != nil {
+		return nil, err
+	}
+
+	// harga reseller tidak boleh di atas harga konsumen, dicek terhadap
+	// gabungan nilai lama dan nilai baru karena update bisa parsial
+	reseller, konsumen := product.HargaReseller, product.HargaKonsumen
+	if input.HargaReseller != nil {
+		reseller = *input.HargaReseller
+	}
+	if input.HargaKonsumen != nil {
+		konsumen = *input.HargaKonsumen
+	}
+	if reseller > konsumen {
+		return nil, apperror.Validation("product.reseller_price_too_high", nil)
+	}
+	// stok dan harga produk bervarian adalah ringkasan dari variannya
+	if len(product.Varian) > 0 && (input.HargaReseller != nil || input.HargaKonsumen != nil || input.Stok != nil) {
+		return nil, apperror.Validation("product.has_variants", nil)
+	}
+
+	updates := map[string]interface{}{}
+	if input.NamaProduk != nil && *input.NamaProduk != "" {
+		updates["nama_produk"] = *input.NamaProduk
+		updates["slug"] = Slug(*input.NamaProduk)
+	}
+	if input.HargaKonsumen != nil {
+		updates["harga_konsumen"] = *input.HargaKonsumen
+	}
+	if input.HargaReseller != nil {
+		updates["harga_reseller"] = *input.HargaReseller
+	}
+	if input.Stok != nil {
+		updates["stok"] = *input.Stok
+	}
+	if input.Deskripsi != nil {
+		updates["deskripsi"] = *input.Deskripsi
+	}
+	if input.IDCategory != nil {
+		updates["id_category"] = *input.IDCategory
+	}
+	if len(updates) == 0 {
+		return nil, apperror.BadRequest("common.nothing_changed")
+	}
+
+	if err := s.produk.Update(ctx, product, updates); err != nil {
+		return nil, err
+	}
+	s.reindex(ctx, *product)
+	return product, nil
+}
+
+func (s *produkService) Delete(ctx context.Context, actor models.User, id uint64) error {
+	product, err := s.owned(ctx, actor, id, "product.delete_forbidden")
+	if err != nil {
+		return err
+	}
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.fotos.DeleteByProduk(ctx, id); err != nil {
+			return err
+		}
+		if err := s.varian.DeleteByProduk(ctx, id); err != nil {
+			return err
+		}
+		if err := s.produk.Delete(ctx, id); err != nil {
+			return err
+		}
+		fotos := product.FotoProduk
+		repositories.AfterCommit(ctx, func() {
+			deleteFotoBlobs(s.blobs, fotos)
+			s.index.Delete(id)
+		})
+		return nil
+	})
+}
+
+// reindex memperbarui dokumen produk di index setelah transaksi di ctx commit
+func (s *produkService) reindex(ctx context.Context, product models.Produk) {
+	doc := search.ProductDocument(product)
+	repositories.AfterCommit(ctx, func() { s.index.Put(doc) })
+}
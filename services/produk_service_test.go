@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"go-crud/apperror"
+	"go-crud/models"
+	"go-crud/search"
+	"reflect"
+	"testing"
+)
+
+// produk 1 tanpa varian dan produk 2 bervarian, keduanya milik toko user 7;
+// produk 3 tokonya tidak termuat
+func produkFixture() *fakeDB {
+	toko := &models.Toko{ID: 4, IDUser: 7}
+	return newFakeDB(
+		models.Produk{ID: 1, NamaProduk: "Kaos", HargaReseller: 10000, HargaKonsumen: 15000, Stok: 3, IDToko: 4, Toko: toko},
+		models.Produk{
+			ID: 2, NamaProduk: "Sepatu", HargaReseller: 300000, HargaKonsumen: 350000, Stok: 2, IDToko: 4, Toko: toko,
+			Varian: []models.VarianProduk{{ID: 21, IDProduk: 2, SKU: "SPT-40", Stok: 2}},
+		},
+		models.Produk{ID: 3, NamaProduk: "Topi", IDToko: 9},
+	)
+}
+
+func newTestProdukService(db *fakeDB) ProdukService {
+	return NewProdukService(fakeProdukRepo{db: db}, nil, nil, nil, nil, fakeTx{db: db}, nil, search.NewIndex())
+}
+
+func TestUpdateProduk(t *testing.T) {
+	owner := models.User{ID: 7}
+	cases := []struct {
+		name  string
+		id    uint64
+		input UpdateProdukInput
+		code  apperror.Code
+		key   string
+		want  map[string]interface{}
+	}{
+		{
+			name:  "harga reseller di atas harga konsumen baru",
+			id:    1,
+			input: UpdateProdukInput{HargaKonsumen: ptr(9000)},
+			code:  apperror.CodeValidation, key: "product.reseller_price_too_high",
+		},
+		{
+			name:  "harga reseller baru di atas harga konsumen lama",
+			id:    1,
+			input: UpdateProdukInput{HargaReseller: ptr(15001)},
+			code:  apperror.CodeValidation, key: "product.reseller_price_too_high",
+		},
+		{
+			name:  "kedua harga baru terbalik",
+			id:    1,
+			input: UpdateProdukInput{HargaReseller: ptr(20000), HargaKonsumen: ptr(19000)},
+			code:  apperror.CodeValidation, key: "product.reseller_price_too_high",
+		},
+		{
+			name:  "harga sama boleh",
+			id:    1,
+			input: UpdateProdukInput{HargaReseller: ptr(15000)},
+			want:  map[string]interface{}{"harga_reseller": 15000},
+		},
+		{
+			name:  "harga konsumen produk bervarian",
+			id:    2,
+			input: UpdateProdukInput{HargaKonsumen: ptr(400000)},
+			code:  apperror.CodeValidation, key: "product.has_variants",
+		},
+		{
+			name:  "harga reseller produk bervarian",
+			id:    2,
+			input: UpdateProdukInput{HargaReseller: ptr(1)},
+			code:  apperror.CodeValidation, key: "product.has_variants",
+		},
+		{
+			name:  "stok produk bervarian",
+			id:    2,
+			input: UpdateProdukInput{Stok: ptr(10)},
+			code:  apperror.CodeValidation, key: "product.has_variants",
+		},
+		{
+			name:  "nama produk bervarian tetap boleh",
+			id:    2,
+			input: UpdateProdukInput{NamaProduk: ptr("Sepatu Lari")},
+			want:  map[string]interface{}{"nama_produk": "Sepatu Lari", "slug": "sepatu-lari"},
+		},
+		{
+			name:  "stok produk tanpa varian",
+			id:    1,
+			input: UpdateProdukInput{Stok: ptr(0)},
+			want:  map[string]interface{}{"stok": 0},
+		},
+		{
+			name: "tidak ada yang diubah",
+			id:   1,
+			code: apperror.CodeBadRequest, key: "common.nothing_changed",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := produkFixture()
+			_, err := newTestProdukService(db).Update(context.Background(), owner, tc.id, tc.input)
+			wantAppError(t, err, tc.code, tc.key)
+
+			var want []map[string]interface{}
+			if tc.want != nil {
+				want = append(want, tc.want)
+			}
+			if !reflect.DeepEqual(db.updates, want) {
+				t.Errorf("update %v, seharusnya %v", db.updates, want)
+			}
+		})
+	}
+}
+
+func TestOwnedProduk(t *testing.T) {
+	cases := []struct {
+		name  string
+		actor models.User
+		id    uint64
+		code  apperror.Code
+		key   string
+	}{
+		{"pemilik toko", models.User{ID: 7}, 1, "", ""},
+		{"user lain", models.User{ID: 8}, 1, apperror.CodeForbidden, "product.edit_forbidden"},
+		// admin pun hanya boleh mengubah produk tokonya sendiri
+		{"admin toko lain", models.User{ID: 8, Roles: []models.UserRole{{Role: "admin"}}}, 1, apperror.CodeForbidden, "product.edit_forbidden"},
+		{"toko tidak termuat", models.User{ID: 7}, 3, apperror.CodeForbidden, "toko.not_found"},
+		{"produk tidak ada", models.User{ID: 7}, 99, apperror.CodeNotFound, "product.not_found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := produkFixture()
+			product, err := ownedProduk(context.Background(), fakeProdukRepo{db: db}, tc.actor, tc.id, "product.edit_forbidden")
+			wantAppError(t, err, tc.code, tc.key)
+			if err == nil && product.ID != tc.id {
+				t.Errorf("produk %d, seharusnya %d", product.ID, tc.id)
+			}
+			if err != nil && product != nil {
+				t.Errorf("produk %d dikembalikan bersama error", product.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"go-crud/imageproc"
+	"go-crud/repositories"
+	"go-crud/search"
+	"go-crud/storage"
+)
+
+// Services berisi semua service domain, dirakit sekali saat server start
+type Services struct {
+	Users        UserService
+	Toko         TokoService
+	Produk       ProdukService
+	FotoProduk   FotoProdukService
+	Varian       VarianService
+	Transactions TransactionService
+}
+
+// photos batas upload foto produk dan toko (config storage)
+func New(repos *repositories.Repositories, hooks AccountHooks, photos PhotoLimits) *Services {
+	return &Services{
+		Users:        NewUserService(repos.Users, repos.Tx, hooks),
+		Toko:         NewTokoService(repos.Toko, storage.Default, imageproc.Default, photos),
+		Produk:       NewProdukService(repos.Produk, repos.Toko, repos.Categories, repos.FotoProduk, repos.Varian, repos.Tx, storage.Default, search.Products),
+		FotoProduk:   NewFotoProdukService(repos.Produk, repos.FotoProduk, repos.Tx, storage.Default, imageproc.Default, photos),
+		Varian:       NewVarianService(repos.Produk, repos.Varian, repos.FotoProduk, repos.Tx),
+		Transactions: NewTransactionService(repos.Transactions, repos.Produk, repos.Varian, repos.Tx),
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-crud/apperror"
+	"go-crud/imageproc"
+	"go-crud/models"
+	"go-crud/rbac"
+	"go-crud/repositories"
+	"go-crud/storage"
+)
+
+type TokoService interface {
+	List(ctx context.Context) ([]models.Toko, error)
+	Get(ctx context.Context, id uint64) (*models.Toko, error)
+	GetByUser(ctx context.Context, userID uint64) (*models.Toko, error)
+	Update(ctx context.Context, actor models.User, id uint64, input UpdateTokoInput) (*models.Toko, error)
+	// UploadPhoto mengganti foto toko dengan file upload; varian thumbnail
+	// dan web dibuat di background
+	UploadPhoto(ctx context.Context, actor models.User, id uint64, file PhotoUpload) (*models.Toko, error)
+	Deactivate(ctx context.Context, id uint64) error
+}
+
+// UpdateTokoInput: string kosong berarti tidak diubah
+type UpdateTokoInput struct {
+	NamaToko string
+	UrlFoto  string
+}
+
+type tokoService struct {
+	toko   repositories.TokoRepository
+	blobs  storage.BlobStore
+	queue  *imageproc.Queue
+	limits PhotoLimits
+}
+
+func NewTokoService(toko repositories.TokoRepository, blobs storage.BlobStore, queue *imageproc.Queue, limits PhotoLimits) TokoService {
+	return &tokoService{toko: toko, blobs: blobs, queue: queue, limits: limits}
+}
+
+var errTokoNotFound = apperror.NotFound("toko.not_found")
+
+func (s *tokoService) List(ctx context.Context) ([]models.Toko, error) {
+	return s.toko.FindAll(ctx)
+}
+
+func (s *tokoService) Get(ctx context.Context, id uint64) (*models.Toko, error) {
+	toko, err := s.toko.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, errTokoNotFound
+	}
+	return toko, err
+}
+
+func (s *tokoService) GetByUser(ctx context.Context, userID uint64) (*models.Toko, error) {
+	toko, err := s.toko.FindByUserID(ctx, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, errTokoNotFound
+	}
+	return toko, err
+}
+
+func (s *tokoService) Update(ctx context.Context, actor models.User, id uint64, input UpdateTokoInput) (*models.Toko, error) {
+	toko, err := s.owned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if input.NamaToko != "" {
+		updates["nama_toko"] = input.NamaToko
+	}
+	if input.UrlFoto != "" {
+		// URL dari luar menggantikan foto upload beserta variannya
+		updates["url_foto"] = input.UrlFoto
+		updates["url_foto_thumb"] = nil
+		updates["foto_key"] = ""
+		updates["foto_thumb_key"] = ""
+		updates["foto_web_key"] = ""
+		updates["foto_status"] = ""
+	}
+	if len(updates) == 0 {
+		return nil, apperror.BadRequest("common.nothing_changed")
+	}
+
+	old := *toko
+	if err := s.toko.Update(ctx, toko, updates); err != nil {
+		return nil, err
+	}
+	if input.UrlFoto != "" {
+		deleteBlobs(s.blobs, old.FotoKey, old.FotoThumbKey, old.FotoWebKey)
+	}
+	return toko, nil
+}
+
+func (s *tokoService) UploadPhoto(ctx context.Context, actor models.User, id uint64, file PhotoUpload) (*models.Toko, error) {
+	toko, err := s.owned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	contentType, body, err := checkPhoto(file, s.limits.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := storage.NewKey(fmt.Sprintf("toko/%d", id), photoTypes[contentType])
+	if err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(ctx, key, body, file.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	// file asli dipakai sampai varian web selesai dibuat
+	old := *toko
+	err = s.toko.Update(ctx, toko, map[string]interface{}{
+		"url_foto":       s.blobs.URL(key),
+		"url_foto_thumb": nil,
+		"foto_key":       key,
+		"foto_thumb_key": "",
+		"foto_web_key":   "",
+		"foto_status":    models.VarianPending,
+	})
+	if err != nil {
+		deleteBlobs(s.blobs, key)
+		return nil, err
+	}
+	deleteBlobs(s.blobs, old.FotoKey, old.FotoThumbKey, old.FotoWebKey)
+	s.queue.Enqueue(imageproc.Job{Kind: imageproc.JobToko, ID: id})
+	return toko, nil
+}
+
+// owned toko yang boleh diubah actor: pemiliknya atau yang punya izin toko
+func (s *tokoService) owned(ctx context.Context, actor models.User, id uint64) (*models.Toko, error) {
+	toko, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if toko.IDUser != actor.ID && !rbac.Can(actor, rbac.PermTokoWrite) {
+		return nil, apperror.Forbidden("toko.edit_forbidden")
+	}
+	return toko, nil
+}
+
+// Deactivate menonaktifkan toko. Belum ada kolom status, jadi untuk sekarang
+// hanya memastikan tokonya ada.
+func (s *tokoService) Deactivate(ctx context.Context, id uint64) error {
+	_, err := s.Get(ctx, id)
+	return err
+}
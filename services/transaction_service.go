@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/models"
+	"go-crud/rbac"
+	"go-crud/repositories"
+	"strconv"
+	"time"
+)
+
+type TransactionService interface {
+	// Create membuat transaksi untuk actor: stok dikurangi, harga produk (atau
+	// varian) saat ini di-snapshot ke log_produk, semuanya dalam satu
+	// transaksi database
+	Create(ctx context.Context, actor models.User, input CreateTransactionInput) (*models.Trx, error)
+	List(ctx context.Context) ([]models.Trx, error)
+	Get(ctx context.Context, actor models.User, id uint64) (*models.Trx, error)
+}
+
+type CreateTransactionInput struct {
+	MethodBayar      string
+	AlamatPengiriman uint64
+	Items            []TransactionItem
+}
+
+type TransactionItem struct {
+	IDProduk uint64
+	// IDVarian wajib untuk produk bervarian, harus nil untuk produk lain
+	IDVarian  *uint64
+	Kuantitas int
+}
+
+type transactionService struct {
+	trx    repositories.TransactionRepository
+	produk repositories.ProdukRepository
+	varian repositories.VarianRepository
+	tx     repositories.Transactor
+}
+
+func NewTransactionService(trx repositories.TransactionRepository, produk repositories.ProdukRepository, varian repositories.VarianRepository, tx repositories.Transactor) TransactionService {
+	return &transactionService{trx: trx, produk: produk, varian: varian, tx: tx}
+}
+
+func (s *transactionService) Create(ctx context.Context, actor models.User, input CreateTransactionInput) (*models.Trx, error) {
+	if len(input.Items) == 0 {
+		return nil, apperror.Validation("transaction.empty", nil)
+	}
+	for _, item := range input.Items {
+		if item.IDProduk == 0 || item.Kuantitas <= 0 {
+			return nil, apperror.Validation("transaction.invalid_product", nil)
+		}
+	}
+
+	now := time.Now()
+	trx := models.Trx{
+		IDUser:           actor.ID,
+		AlamatPengiriman: &input.AlamatPengiriman,
+		KodeInvoice:      "INV-" + strconv.FormatInt(now.Unix(), 10),
+		MethodBayar:      &input.MethodBayar,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.trx.Create(ctx, &trx); err != nil {
+			return err
+		}
+
+		totalHarga := 0
+		for _, item := range input.Items {
+			product, err := s.produk.FindByID(ctx, item.IDProduk)
+			if errors.Is(err, repositories.ErrNotFound) {
+				return errProdukNotFound
+			}
+			if err != nil {
+				return err
+			}
+
+			varian, err := pickVarian(product, item.IDVarian)
+			if err != nil {
+				return err
+			}
+
+			// Kurangi stok; stok produk bervarian adalah total stok varian,
+			// jadi ikut dikurangi
+			hargaReseller, hargaKonsumen := product.HargaReseller, product.HargaKonsumen
+			nama := product.NamaProduk
+			if varian != nil {
+				hargaReseller, hargaKonsumen = varian.HargaReseller, varian.HargaKonsumen
+				nama += " (" + varian.Nama + ")"
+				ok, err := s.varian.DecrementStock(ctx, varian.ID, item.Kuantitas)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return apperror.OutOfStock("transaction.out_of_stock").WithArgs(nama)
+				}
+			}
+			ok, err := s.produk.DecrementStock(ctx, product.ID, item.Kuantitas)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return apperror.OutOfStock("transaction.out_of_stock").WithArgs(nama)
+			}
+
+			// Hitung subtotal
+			subtotal := item.Kuantitas * hargaKonsumen
+			totalHarga += subtotal
+
+			// Simpan log produk
+			logProduk := models.LogProduk{
+				IDProduk:      product.ID,
+				NamaProduk:    product.NamaProduk,
+				Slug:          Slug(product.NamaProduk),
+				HargaReseller: hargaReseller,
+				HargaKonsumen: hargaKonsumen,
+				Deskripsi:     product.Deskripsi,
+				IDToko:        product.IDToko,
+				IDCategory:    product.IDCategory,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			if varian != nil {
+				logProduk.IDVarian = &varian.ID
+				logProduk.SKU = varian.SKU
+				logProduk.NamaVarian = varian.Nama
+			}
+			if err := s.trx.CreateLogProduk(ctx, &logProduk); err != nil {
+				return err
+			}
+
+			// Simpan detail transaksi
+			detail := models.DetailTrx{
+				IDTrx:       trx.ID,
+				IDLogProduk: logProduk.ID,
+				IDToko:      product.IDToko,
+				Kuantitas:   item.Kuantitas,
+				HargaTotal:  subtotal,
+			}
+			if err := s.trx.CreateDetail(ctx, &detail); err != nil {
+				return err
+			}
+		}
+
+		// Simpan total harga ke transaksi
+		return s.trx.UpdateTotal(ctx, &trx, totalHarga)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &trx, nil
+}
+
+// pickVarian varian yang dibeli, nil untuk produk tanpa varian
+func pickVarian(product *models.Produk, id *uint64) (*models.VarianProduk, error) {
+	if len(product.Varian) == 0 {
+		if id != nil {
+			return nil, apperror.Validation("transaction.variant_not_found", nil).WithArgs(product.NamaProduk)
+		}
+		return nil, nil
+	}
+	if id == nil {
+		return nil, apperror.Validation("transaction.variant_required", nil).WithArgs(product.NamaProduk)
+	}
+	for i := range product.Varian {
+		if product.Varian[i].ID == *id {
+			return &product.Varian[i], nil
+		}
+	}
+	return nil, apperror.Validation("transaction.variant_not_found", nil).WithArgs(product.NamaProduk)
+}
+
+func (s *transactionService) List(ctx context.Context) ([]models.Trx, error) {
+	return s.trx.FindAll(ctx)
+}
+
+func (s *transactionService) Get(ctx context.Context, actor models.User, id uint64) (*models.Trx, error) {
+	trx, err := s.trx.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, apperror.NotFound("transaction.not_found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// hanya pemilik transaksi atau yang punya permission transaction:read
+	if trx.IDUser != actor.ID && !rbac.Can(actor, rbac.PermTransactionReadAll) {
+		return nil, apperror.Forbidden("transaction.forbidden")
+	}
+	return trx, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"go-crud/apperror"
+	"go-crud/models"
+	"reflect"
+	"testing"
+)
+
+// produk tanpa varian dan produk bervarian (stok produk = total stok varian)
+func transactionFixture() *fakeDB {
+	return newFakeDB(
+		models.Produk{
+			ID: 1, NamaProduk: "Kaos Polos", HargaReseller: 10000, HargaKonsumen: 15000, Stok: 10,
+			Deskripsi: ptr("katun"), IDToko: 4, IDCategory: ptr(uint64(3)),
+		},
+		models.Produk{
+			ID: 2, NamaProduk: "Sepatu Lari", HargaReseller: 300000, HargaKonsumen: 350000, Stok: 7, IDToko: 5,
+			Varian: []models.VarianProduk{
+				{ID: 21, IDProduk: 2, SKU: "SPT-40", Nama: "Ukuran: 40", HargaReseller: 300000, HargaKonsumen: 350000, Stok: 2},
+				{ID: 22, IDProduk: 2, SKU: "SPT-41", Nama: "Ukuran: 41", HargaReseller: 320000, HargaKonsumen: 375000, Stok: 5},
+			},
+		},
+	)
+}
+
+func newTestTransactionService(db *fakeDB) TransactionService {
+	return NewTransactionService(fakeTrxRepo{db: db}, fakeProdukRepo{db: db}, fakeVarianRepo{db: db}, fakeTx{db: db})
+}
+
+func TestCreateTransactionRejected(t *testing.T) {
+	cases := []struct {
+		name  string
+		items []TransactionItem
+		code  apperror.Code
+		key   string
+		args  []interface{}
+	}{
+		{"tanpa item", nil, apperror.CodeValidation, "transaction.empty", nil},
+		{"kuantitas nol", []TransactionItem{{IDProduk: 1}}, apperror.CodeValidation, "transaction.invalid_product", nil},
+		{"produk tidak ada", []TransactionItem{{IDProduk: 9, Kuantitas: 1}}, apperror.CodeNotFound, "product.not_found", nil},
+		{
+			"varian wajib untuk produk bervarian",
+			[]TransactionItem{{IDProduk: 2, Kuantitas: 1}},
+			apperror.CodeValidation, "transaction.variant_required", []interface{}{"Sepatu Lari"},
+		},
+		{
+			"varian bukan milik produk",
+			[]TransactionItem{{IDProduk: 2, IDVarian: ptr(uint64(99)), Kuantitas: 1}},
+			apperror.CodeValidation, "transaction.variant_not_found", []interface{}{"Sepatu Lari"},
+		},
+		{
+			"varian untuk produk tanpa varian",
+			[]TransactionItem{{IDProduk: 1, IDVarian: ptr(uint64(21)), Kuantitas: 1}},
+			apperror.CodeValidation, "transaction.variant_not_found", []interface{}{"Kaos Polos"},
+		},
+		{
+			"stok produk habis di item kedua",
+			[]TransactionItem{{IDProduk: 2, IDVarian: ptr(uint64(22)), Kuantitas: 1}, {IDProduk: 1, Kuantitas: 11}},
+			apperror.CodeOutOfStock, "transaction.out_of_stock", []interface{}{"Kaos Polos"},
+		},
+		{
+			"stok varian habis di item kedua",
+			[]TransactionItem{{IDProduk: 1, Kuantitas: 2}, {IDProduk: 2, IDVarian: ptr(uint64(21)), Kuantitas: 3}},
+			apperror.CodeOutOfStock, "transaction.out_of_stock", []interface{}{"Sepatu Lari (Ukuran: 40)"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := transactionFixture()
+			before := db.clone()
+
+			trx, err := newTestTransactionService(db).Create(context.Background(), models.User{ID: 7}, CreateTransactionInput{Items: tc.items})
+			wantAppError(t, err, tc.code, tc.key)
+			if trx != nil {
+				t.Errorf("transaksi %+v dikembalikan bersama error", trx)
+			}
+			if appErr, _ := apperror.As(err); !reflect.DeepEqual(appErr.Args, tc.args) {
+				t.Errorf("args %v, seharusnya %v", appErr.Args, tc.args)
+			}
+			// item yang sudah diproses ikut dibatalkan
+			if !reflect.DeepEqual(db, before) {
+				t.Errorf("data berubah setelah rollback:\n%+v\nseharusnya\n%+v", db, before)
+			}
+		})
+	}
+}
+
+func TestCreateTransactionSnapshot(t *testing.T) {
+	db := transactionFixture()
+	input := CreateTransactionInput{
+		MethodBayar:      "transfer",
+		AlamatPengiriman: 12,
+		Items: []TransactionItem{
+			{IDProduk: 1, Kuantitas: 2},
+			{IDProduk: 2, IDVarian: ptr(uint64(22)), Kuantitas: 3},
+		},
+	}
+	trx, err := newTestTransactionService(db).Create(context.Background(), models.User{ID: 7}, input)
+	wantAppError(t, err, "", "")
+
+	if trx.IDUser != 7 || *trx.AlamatPengiriman != 12 || *trx.MethodBayar != "transfer" {
+		t.Errorf("transaksi %+v tidak sesuai input", trx)
+	}
+	// 2 x 15000 + 3 x harga konsumen varian
+	if want := 2*15000 + 3*375000; trx.HargaTotal != want || db.trx[0].HargaTotal != want {
+		t.Errorf("total %d (tersimpan %d), seharusnya %d", trx.HargaTotal, db.trx[0].HargaTotal, want)
+	}
+
+	if got := db.produk[1].Stok; got != 8 {
+		t.Errorf("stok produk 1 = %d, seharusnya 8", got)
+	}
+	// stok varian dan total stok produknya sama-sama berkurang
+	if got := db.produk[2].Stok; got != 4 {
+		t.Errorf("stok produk 2 = %d, seharusnya 4", got)
+	}
+	if got := db.produk[2].Varian[1].Stok; got != 2 {
+		t.Errorf("stok varian 22 = %d, seharusnya 2", got)
+	}
+
+	// log_produk menyimpan salinan data produk (dan varian) saat dibeli
+	wantLogs := []models.LogProduk{
+		{
+			ID: 1, IDProduk: 1, NamaProduk: "Kaos Polos", Slug: "kaos-polos", HargaReseller: 10000, HargaKonsumen: 15000,
+			Deskripsi: ptr("katun"), IDToko: 4, IDCategory: ptr(uint64(3)),
+		},
+		{
+			ID: 2, IDProduk: 2, NamaProduk: "Sepatu Lari", Slug: "sepatu-lari", HargaReseller: 320000, HargaKonsumen: 375000,
+			IDToko: 5, IDVarian: ptr(uint64(22)), SKU: "SPT-41", NamaVarian: "Ukuran: 41",
+		},
+	}
+	if len(db.logs) != len(wantLogs) {
+		t.Fatalf("%d log produk, seharusnya %d", len(db.logs), len(wantLogs))
+	}
+	for i, want := range wantLogs {
+		got := db.logs[i]
+		want.CreatedAt, want.UpdatedAt = got.CreatedAt, got.UpdatedAt
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("log %d:\n%+v\nseharusnya\n%+v", i, got, want)
+		}
+	}
+
+	wantDetails := []models.DetailTrx{
+		{ID: 1, IDTrx: trx.ID, IDLogProduk: 1, IDToko: 4, Kuantitas: 2, HargaTotal: 30000},
+		{ID: 2, IDTrx: trx.ID, IDLogProduk: 2, IDToko: 5, Kuantitas: 3, HargaTotal: 1125000},
+	}
+	if !reflect.DeepEqual(db.details, wantDetails) {
+		t.Errorf("detail:\n%+v\nseharusnya\n%+v", db.details, wantDetails)
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/models"
+	"go-crud/rbac"
+	"go-crud/repositories"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserService mengatur data akun. Permission kasar (misal user:read untuk
+// daftar semua user) dicek middleware di route; service mengecek aturan per
+// objek seperti "hanya pemilik akun atau yang punya user:write".
+type UserService interface {
+	List(ctx context.Context) ([]models.User, error)
+	// FindByID tanpa pengecekan akses, untuk kode internal dan command CLI
+	FindByID(ctx context.Context, id uint64) (*models.User, error)
+	Get(ctx context.Context, actor models.User, id uint64) (*models.User, error)
+	Update(ctx context.Context, actor models.User, id uint64, input UpdateUserInput) (*models.User, error)
+	Delete(ctx context.Context, id uint64) error
+}
+
+// UpdateUserInput: field nil berarti tidak diubah
+type UpdateUserInput struct {
+	Nama         *string
+	KataSandi    *string
+	NoTelp       *string
+	TanggalLahir *time.Time
+	JenisKelamin *string
+	Tentang      *string
+	Pekerjaan    *string
+	Email        *string
+	IDProvinsi   *string
+	IDKota       *string
+	Bahasa       *string
+}
+
+// AccountHooks menangani efek samping perubahan akun di luar domain user
+// (email verifikasi, sesi login). Dipanggil di dalam transaksi yang sama
+// dengan penyimpanan user.
+type AccountHooks interface {
+	EmailChanged(ctx context.Context, user models.User) error
+	PasswordChanged(ctx context.Context, user models.User) error
+}
+
+type userService struct {
+	users repositories.UserRepository
+	tx    repositories.Transactor
+	hooks AccountHooks
+}
+
+func NewUserService(users repositories.UserRepository, tx repositories.Transactor, hooks AccountHooks) UserService {
+	return &userService{users: users, tx: tx, hooks: hooks}
+}
+
+var errUserNotFound = apperror.NotFound("user.not_found")
+
+func (s *userService) List(ctx context.Context) ([]models.User, error) {
+	return s.users.FindAll(ctx)
+}
+
+func (s *userService) FindByID(ctx context.Context, id uint64) (*models.User, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, errUserNotFound
+	}
+	return user, err
+}
+
+func (s *userService) Get(ctx context.Context, actor models.User, id uint64) (*models.User, error) {
+	user, err := s.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !rbac.Can(actor, rbac.PermUserRead) && actor.ID != user.ID {
+		return nil, apperror.Forbidden("user.view_forbidden")
+	}
+	return user, nil
+}
+
+func (s *userService) Update(ctx context.Context, actor models.User, id uint64, input UpdateUserInput) (*models.User, error) {
+	user, err := s.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !rbac.Can(actor, rbac.PermUserWrite) && actor.ID != user.ID {
+		return nil, apperror.Forbidden("user.edit_forbidden")
+	}
+
+	if input.Nama != nil {
+		user.Nama = *input.Nama
+	}
+	passwordChanged := false
+	if input.KataSandi != nil && *input.KataSandi != "" {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(*input.KataSandi), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		user.KataSandi = string(hashed)
+		passwordChanged = true
+	}
+	if input.NoTelp != nil {
+		user.NoTelp = input.NoTelp
+	}
+	if input.TanggalLahir != nil {
+		user.TanggalLahir = input.TanggalLahir
+	}
+	if input.JenisKelamin != nil {
+		if *input.JenisKelamin != models.JenisKelaminLakiLaki && *input.JenisKelamin != models.JenisKelaminPerempuan {
+			return nil, apperror.Validation("user.invalid_gender", nil)
+		}
+		user.JenisKelamin = input.JenisKelamin
+	}
+	if input.Tentang != nil {
+		user.Tentang = input.Tentang
+	}
+	if input.Pekerjaan != nil {
+		user.Pekerjaan = input.Pekerjaan
+	}
+	emailChanged := false
+	if input.Email != nil && *input.Email != user.Email {
+		// email baru wajib diverifikasi ulang
+		user.Email = *input.Email
+		user.EmailVerifiedAt = nil
+		emailChanged = true
+	}
+	if input.IDProvinsi != nil {
+		user.IDProvinsi = input.IDProvinsi
+	}
+	if input.IDKota != nil {
+		user.IDKota = input.IDKota
+	}
+	if input.Bahasa != nil {
+		user.Bahasa = input.Bahasa
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.users.Save(ctx, user); err != nil {
+			return err
+		}
+		if emailChanged {
+			if err := s.hooks.EmailChanged(ctx, *user); err != nil {
+				return err
+			}
+		}
+		// ganti kata sandi = keluarkan dari semua sesi
+		if passwordChanged {
+			return s.hooks.PasswordChanged(ctx, *user)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *userService) Delete(ctx context.Context, id uint64) error {
+	return s.users.Delete(ctx, id)
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-crud/apperror"
+	"go-crud/models"
+	"go-crud/repositories"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type VarianService interface {
+	// Get opsi dan varian produk
+	Get(ctx context.Context, idProduk uint64) (*ProdukVarian, error)
+	// Replace mengganti semua opsi dan varian produk (pemilik toko). Varian
+	// yang kombinasinya sama dengan sebelumnya tetap memakai ID yang sama
+	// beserta fotonya; kombinasi yang tidak dikirim dihapus.
+	Replace(ctx context.Context, actor models.User, idProduk uint64, input ReplaceVarianInput) (*ProdukVarian, error)
+	// Update mengubah satu varian (pemilik toko)
+	Update(ctx context.Context, actor models.User, idProduk, id uint64, input UpdateVarianInput) (*ProdukVarian, error)
+}
+
+// ProdukVarian opsi (urut seperti input) dan varian satu produk
+type ProdukVarian struct {
+	Opsi   []models.OpsiProduk
+	Varian []models.VarianProduk
+}
+
+type OpsiInput struct {
+	Nama  string
+	Nilai []string
+}
+
+type VarianInput struct {
+	// Opsi nama opsi -> nilai, harus berisi tepat satu nilai untuk setiap opsi
+	Opsi          map[string]string
+	SKU           string
+	HargaReseller int
+	HargaKonsumen int
+	Stok          int
+}
+
+// ReplaceVarianInput Opsi dan Varian kosong menghapus semua varian produk
+type ReplaceVarianInput struct {
+	Opsi   []OpsiInput
+	Varian []VarianInput
+}
+
+// UpdateVarianInput field nil berarti tidak diubah
+type UpdateVarianInput struct {
+	SKU           *string
+	HargaReseller *int
+	HargaKonsumen *int
+	Stok          *int
+	// IDFoto foto produk khusus varian ini; slice kosong melepas semuanya
+	IDFoto *[]uint64
+}
+
+type varianService struct {
+	produk repositories.ProdukRepository
+	varian repositories.VarianRepository
+	fotos  repositories.FotoProdukRepository
+	tx     repositories.Transactor
+}
+
+func NewVarianService(produk repositories.ProdukRepository, varian repositories.VarianRepository, fotos repositories.FotoProdukRepository, tx repositories.Transactor) VarianService {
+	return &varianService{produk: produk, varian: varian, fotos: fotos, tx: tx}
+}
+
+var errVarianNotFound = apperror.NotFound("variant.not_found")
+
+func (s *varianService) Get(ctx context.Context, idProduk uint64) (*ProdukVarian, error) {
+	product, err := s.produk.FindByID(ctx, idProduk)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, errProdukNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ProdukVarian{Opsi: product.Opsi, Varian: product.Varian}, nil
+}
+
+// varianPlan satu varian hasil validasi input: label kombinasi dan indeks
+// nilai di setiap opsi
+type varianPlan struct {
+	VarianInput
+	nama  string
+	nilai []int
+}
+
+// planVarian memeriksa input dan menyusun label setiap kombinasi, misal
+// "Ukuran: XL, Warna: Merah" sesuai urutan opsi
+func planVarian(input ReplaceVarianInput) ([]varianPlan, error) {
+	if len(input.Opsi) == 0 && len(input.Varian) > 0 {
+		return nil, apperror.Validation("variant.options_required", nil)
+	}
+	if len(input.Opsi) > 0 && len(input.Varian) == 0 {
+		return nil, apperror.Validation("variant.variants_required", nil)
+	}
+
+	seenOpsi := map[string]bool{}
+	for _, opsi := range input.Opsi {
+		key := strings.ToLower(opsi.Nama)
+		if seenOpsi[key] {
+			return nil, apperror.Validation("variant.duplicate_option", nil).WithArgs(opsi.Nama)
+		}
+		seenOpsi[key] = true
+		seenNilai := map[string]bool{}
+		for _, nilai := range opsi.Nilai {
+			key := strings.ToLower(nilai)
+			if seenNilai[key] {
+				return nil, apperror.Validation("variant.duplicate_value", nil).WithArgs(nilai, opsi.Nama)
+			}
+			seenNilai[key] = true
+		}
+	}
+
+	plans := make([]varianPlan, 0, len(input.Varian))
+	seenNama := map[string]bool{}
+	seenSKU := map[string]bool{}
+	for _, v := range input.Varian {
+		if len(v.Opsi) != len(input.Opsi) {
+			return nil, apperror.Validation("variant.invalid_combination", nil).WithArgs(v.SKU)
+		}
+		plan := varianPlan{VarianInput: v, nilai: make([]int, len(input.Opsi))}
+		labels := make([]string, len(input.Opsi))
+		for i, opsi := range input.Opsi {
+			nilai, ok := v.Opsi[opsi.Nama]
+			if !ok {
+				return nil, apperror.Validation("variant.invalid_combination", nil).WithArgs(v.SKU)
+			}
+			idx := slices.Index(opsi.Nilai, nilai)
+			if idx < 0 {
+				return nil, apperror.Validation("variant.unknown_value", nil).WithArgs(nilai, opsi.Nama)
+			}
+			plan.nilai[i] = idx
+			labels[i] = opsi.Nama + ": " + nilai
+		}
+		plan.nama = strings.Join(labels, ", ")
+		if seenNama[plan.nama] {
+			return nil, apperror.Validation("variant.duplicate_combination", nil).WithArgs(plan.nama)
+		}
+		seenNama[plan.nama] = true
+		if seenSKU[v.SKU] {
+			return nil, apperror.Validation("variant.repeated_sku", nil).WithArgs(v.SKU)
+		}
+		seenSKU[v.SKU] = true
+		if v.HargaReseller > v.HargaKonsumen {
+			return nil, apperror.Validation("product.reseller_price_too_high", nil)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+func (s *varianService) Replace(ctx context.Context, actor models.User, idProduk uint64, input ReplaceVarianInput) (*ProdukVarian, error) {
+	plans, err := planVarian(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		product, err := ownedProduk(ctx, s.produk, actor, idProduk, "product.edit_forbidden")
+		if err != nil {
+			return err
+		}
+
+		opsi := make([]models.OpsiProduk, len(input.Opsi))
+		for i, o := range input.Opsi {
+			opsi[i] = models.OpsiProduk{IDProduk: idProduk, Nama: o.Nama, Urutan: i + 1}
+			for j, nilai := range o.Nilai {
+				opsi[i].Nilai = append(opsi[i].Nilai, models.NilaiOpsi{Nilai: nilai, Urutan: j + 1})
+			}
+		}
+		if err := s.varian.ReplaceOpsi(ctx, idProduk, opsi); err != nil {
+			return err
+		}
+
+		// kombinasi lama dicocokkan lewat label; yang tidak dikirim dihapus
+		// dulu supaya SKU-nya bisa dipakai varian lain
+		existing := map[string]models.VarianProduk{}
+		for _, v := range product.Varian {
+			existing[v.Nama] = v
+		}
+		var removed []uint64
+		for _, v := range product.Varian {
+			if !slices.ContainsFunc(plans, func(p varianPlan) bool { return p.nama == v.Nama }) {
+				removed = append(removed, v.ID)
+			}
+		}
+		if err := s.fotos.ClearVarian(ctx, removed); err != nil {
+			return err
+		}
+		if err := s.varian.Delete(ctx, removed); err != nil {
+			return err
+		}
+
+		// SKU varian lama yang berubah diparkir dulu supaya dua varian bisa
+		// bertukar SKU tanpa menabrak unique index di tengah jalan
+		for _, plan := range plans {
+			if v, ok := existing[plan.nama]; ok && v.SKU != plan.SKU {
+				if err := s.varian.Update(ctx, &v, map[string]interface{}{"sku": parkedSKU(v.ID)}); err != nil {
+					return err
+				}
+			}
+		}
+
+		varian := make([]models.VarianProduk, 0, len(plans))
+		for _, plan := range plans {
+			if err := s.checkSKU(ctx, plan.SKU, idProduk); err != nil {
+				return err
+			}
+			v, ok := existing[plan.nama]
+			if ok {
+				err = s.varian.Update(ctx, &v, map[string]interface{}{
+					"sku":            plan.SKU,
+					"harga_reseller": plan.HargaReseller,
+					"harga_konsumen": plan.HargaKonsumen,
+					"stok":           plan.Stok,
+				})
+			} else {
+				v = models.VarianProduk{
+					IDProduk:      idProduk,
+					SKU:           plan.SKU,
+					Nama:          plan.nama,
+					HargaReseller: plan.HargaReseller,
+					HargaKonsumen: plan.HargaKonsumen,
+					Stok:          plan.Stok,
+				}
+				err = s.varian.Create(ctx, &v)
+			}
+			if err != nil {
+				return err
+			}
+
+			idNilai := make([]uint64, len(plan.nilai))
+			for i, idx := range plan.nilai {
+				idNilai[i] = opsi[i].Nilai[idx].ID
+			}
+			if err := s.varian.SetNilai(ctx, v.ID, idNilai); err != nil {
+				return err
+			}
+			varian = append(varian, v)
+		}
+		return s.summarize(ctx, product, varian)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, idProduk)
+}
+
+func (s *varianService) Update(ctx context.Context, actor models.User, idProduk, id uint64, input UpdateVarianInput) (*ProdukVarian, error) {
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		product, err := ownedProduk(ctx, s.produk, actor, idProduk, "product.edit_forbidden")
+		if err != nil {
+			return err
+		}
+		idx := slices.IndexFunc(product.Varian, func(v models.VarianProduk) bool { return v.ID == id })
+		if idx < 0 {
+			return errVarianNotFound
+		}
+		v := &product.Varian[idx]
+
+		// sama seperti produk, dicek terhadap gabungan nilai lama dan baru
+		reseller, konsumen := v.HargaReseller, v.HargaKonsumen
+		if input.HargaReseller != nil {
+			reseller = *input.HargaReseller
+		}
+		if input.HargaKonsumen != nil {
+			konsumen = *input.HargaKonsumen
+		}
+		if reseller > konsumen {
+			return apperror.Validation("product.reseller_price_too_high", nil)
+		}
+
+		updates := map[string]interface{}{}
+		if input.SKU != nil && *input.SKU != v.SKU {
+			for _, other := range product.Varian {
+				if other.SKU == *input.SKU {
+					return apperror.Conflict("variant.duplicate_sku").WithArgs(*input.SKU)
+				}
+			}
+			if err := s.checkSKU(ctx, *input.SKU, idProduk); err != nil {
+				return err
+			}
+			updates["sku"] = *input.SKU
+		}
+		if input.HargaReseller != nil {
+			updates["harga_reseller"] = *input.HargaReseller
+		}
+		if input.HargaKonsumen != nil {
+			updates["harga_konsumen"] = *input.HargaKonsumen
+		}
+		if input.Stok != nil {
+			updates["stok"] = *input.Stok
+		}
+		if len(updates) == 0 && input.IDFoto == nil {
+			return apperror.BadRequest("common.nothing_changed")
+		}
+
+		if input.IDFoto != nil {
+			for _, idFoto := range *input.IDFoto {
+				if !slices.ContainsFunc(product.FotoProduk, func(f models.FotoProduk) bool { return f.ID == idFoto }) {
+					return errFotoNotFound
+				}
+			}
+			if err := s.fotos.AssignVarian(ctx, idProduk, id, *input.IDFoto); err != nil {
+				return err
+			}
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := s.varian.Update(ctx, v, updates); err != nil {
+			return err
+		}
+		return s.summarize(ctx, product, product.Varian)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, idProduk)
+}
+
+// parkedSKU SKU sementara yang unik per varian selama Replace berjalan
+func parkedSKU(id uint64) string {
+	return "~replace-" + strconv.FormatUint(id, 10)
+}
+
+func (s *varianService) checkSKU(ctx context.Context, sku string, idProduk uint64) error {
+	taken, err := s.varian.SKUTaken(ctx, sku, idProduk)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return apperror.Conflict("variant.duplicate_sku").WithArgs(sku)
+	}
+	return nil
+}
+
+// summarize menyimpan stok total dan harga termurah varian ke produk supaya
+// filter, urutan harga dan tampilan daftar produk tetap memakai kolom produk.
+// Produk tanpa varian lagi tetap memakai nilai terakhir.
+func (s *varianService) summarize(ctx context.Context, product *models.Produk, varian []models.VarianProduk) error {
+	if len(varian) == 0 {
+		return nil
+	}
+	stok, reseller, konsumen := 0, varian[0].HargaReseller, varian[0].HargaKonsumen
+	for _, v := range varian {
+		stok += v.Stok
+		reseller = min(reseller, v.HargaReseller)
+		konsumen = min(konsumen, v.HargaKonsumen)
+	}
+	return s.produk.Update(ctx, product, map[string]interface{}{
+		"stok":           stok,
+		"harga_reseller": reseller,
+		"harga_konsumen": konsumen,
+	})
+}
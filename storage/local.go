@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ================================
+// 📁 LocalStore
+// ================================
+
+// LocalPrefix path tempat file LocalStore disajikan oleh server (lihat Handler)
+const LocalPrefix = "/uploads"
+
+// LocalStore menyimpan file di folder lokal. Cocok untuk satu instance server;
+// kalau server lebih dari satu pakai S3Store.
+type LocalStore struct {
+	Dir     string
+	BaseURL string
+}
+
+func NewLocalStore(dir, baseURL string) *LocalStore {
+	return &LocalStore{Dir: dir, BaseURL: baseURL}
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(cleaned)), nil
+}
+
+func (s *LocalStore) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	// tulis ke file sementara lalu rename, supaya file setengah jadi tidak
+	// pernah tersaji
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *LocalStore) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return joinURL(s.BaseURL, key)
+}
+
+// Handler menyajikan file LocalStore di LocalPrefix. Dengan driver lain
+// (file ada di S3) selalu 404.
+func Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		local, ok := Default.(*LocalStore)
+		if !ok {
+			return echo.ErrNotFound
+		}
+		p, err := local.path(c.Param("*"))
+		if err != nil {
+			return echo.ErrNotFound
+		}
+		// key acak dan tidak pernah ditimpa, jadi boleh di-cache selamanya
+		c.Response().Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+		if err := c.File(p); err != nil {
+			return echo.NewHTTPError(http.StatusNotFound).SetInternal(err)
+		}
+		return nil
+	}
+}
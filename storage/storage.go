@@ -0,0 +1,81 @@
+// Package storage menyimpan file upload (foto produk) lewat BlobStore, dengan
+// implementasi folder lokal dan object storage yang kompatibel S3 (AWS S3,
+// MinIO, ...).
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-crud/config"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrNotFound dikembalikan Open kalau object tidak ada
+var ErrNotFound = errors.New("object tidak ditemukan")
+
+// BlobStore penyimpanan file berdasarkan key berbentuk path dengan pemisah
+// "/", misal "produk/12/3f9a....jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete tidak error kalau object memang sudah tidak ada
+	Delete(ctx context.Context, key string) error
+	// URL alamat publik object untuk dikirim ke client
+	URL(key string) string
+}
+
+// Default dipakai service, di-set oleh Init saat server start
+var Default BlobStore = NewLocalStore("uploads", LocalPrefix)
+
+// New membuat BlobStore sesuai storage.driver
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		base := cfg.PublicBaseURL
+		if base == "" {
+			base = LocalPrefix
+		}
+		return NewLocalStore(cfg.LocalDir, base), nil
+	case "s3":
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("storage driver tidak dikenal: %s", cfg.Driver)
+	}
+}
+
+func Init(cfg config.StorageConfig) error {
+	s, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	Default = s
+	return nil
+}
+
+// NewKey key acak di bawah prefix dengan ekstensi ext (termasuk titik),
+// supaya nama file dari client tidak pernah dipakai sebagai path
+func NewKey(prefix, ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return path.Join(prefix, hex.EncodeToString(b)) + ext, nil
+}
+
+// cleanKey menolak key kosong dan key yang keluar dari root ("../")
+func cleanKey(key string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+key), "/")
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", fmt.Errorf("key tidak valid: %q", key)
+	}
+	return cleaned, nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
@@ -2,6 +2,7 @@ package utils
 
 type BaseResponse struct {
 	Status  bool        `json:"status"`
+	Code    string      `json:"code,omitempty"` // kode error stabil (apperror.Code), kosong kalau sukses
 	Message string      `json:"message"`
 	Errors  interface{} `json:"errors"`
 	Data    interface{} `json:"data"`
@@ -23,4 +24,24 @@ func ErrorResponse(message string, errs []string) BaseResponse {
 		Errors:  errs,
 		Data:    nil,
 	}
-}
\ No newline at end of file
+}
+
+// FieldError adalah error validasi untuk satu field request. Sebelum
+// diterjemahkan, Message berisi key katalog i18n dan Args argumennya.
+type FieldError struct {
+	Field   string        `json:"field"`
+	Rule    string        `json:"rule"`
+	Message string        `json:"message"`
+	Args    []interface{} `json:"-"`
+}
+
+// ValidationErrorResponse sama seperti ErrorResponse, tapi Errors berisi
+// error per field supaya client bisa menandai input yang salah
+func ValidationErrorResponse(message string, errs []FieldError) BaseResponse {
+	return BaseResponse{
+		Status:  false,
+		Message: message,
+		Errors:  errs,
+		Data:    nil,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -29,6 +30,18 @@ var httpClient = &http.Client{
 	Timeout: 5 * time.Second,
 }
 
+// Base URL API wilayah, bisa diganti lewat konfigurasi (WILAYAH_API_URL)
+var wilayahBaseURL = "https://www.emsifa.com/api-wilayah-indonesia/api"
+
+func SetWilayahBaseURL(url string) {
+	wilayahBaseURL = strings.TrimRight(url, "/")
+}
+
+// WilayahURL membentuk URL lengkap, contoh WilayahURL("provinces.json")
+func WilayahURL(path string) string {
+	return wilayahBaseURL + "/" + path
+}
+
 // ================================
 // 🔹 GetProvinceByID
 // ================================
@@ -37,7 +50,7 @@ func GetProvinceByID(id string) (*Province, error) {
 		return nil, errors.New("id provinsi tidak boleh kosong")
 	}
 
-	url := fmt.Sprintf("https://www.emsifa.com/api-wilayah-indonesia/api/province/%s.json", id)
+	url := WilayahURL(fmt.Sprintf("province/%s.json", id))
 	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil data provinsi: %v", err)
@@ -64,7 +77,7 @@ func GetCityByID(id string) (*City, error) {
 		return nil, errors.New("id kota tidak boleh kosong")
 	}
 
-	url := fmt.Sprintf("https://www.emsifa.com/api-wilayah-indonesia/api/regency/%s.json", id)
+	url := WilayahURL(fmt.Sprintf("regency/%s.json", id))
 	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil data kota: %v", err)
@@ -87,7 +100,7 @@ func GetCityByID(id string) (*City, error) {
 // 🔹 GetAllProvinces
 // ================================
 func GetAllProvinces() ([]Province, error) {
-	url := "https://www.emsifa.com/api-wilayah-indonesia/api/provinces.json"
+	url := WilayahURL("provinces.json")
 	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil daftar provinsi: %v", err)
@@ -114,7 +127,7 @@ func GetCitiesByProvinceID(provinceID string) ([]City, error) {
 		return nil, errors.New("id provinsi tidak boleh kosong")
 	}
 
-	url := fmt.Sprintf("https://www.emsifa.com/api-wilayah-indonesia/api/regencies/%s.json", provinceID)
+	url := WilayahURL(fmt.Sprintf("regencies/%s.json", provinceID))
 	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil daftar kota: %v", err)
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ================================
+// 🔹 PESAN ERROR
+// ================================
+
+// message mengembalikan key katalog i18n beserta argumennya untuk satu
+// error validasi, diterjemahkan belakangan oleh HTTP error handler
+func message(fe validator.FieldError) (string, []interface{}) {
+	switch fe.Tag() {
+	case "required", "notblank", "required_without":
+		return "validation.required", nil
+	case "email":
+		return "validation.email", nil
+	case "url":
+		return "validation.url", nil
+	case "oneof":
+		return "validation.oneof", args(strings.Join(strings.Fields(fe.Param()), ", "))
+	case "min", "gte":
+		return "validation.min" + unit(fe), args(fe.Param())
+	case "max", "lte":
+		return "validation.max" + unit(fe), args(fe.Param())
+	case "len":
+		return "validation.len" + unit(fe), args(fe.Param())
+	case "ltefield":
+		return "validation.ltefield", args(SnakeCase(fe.Param()))
+	case "phone_id":
+		return "validation.phone_id", nil
+	case "provinsi_id":
+		return "validation.provinsi_id", nil
+	case "kota_id":
+		if fe.Param() != "" {
+			return "validation.kota_id_prefix", args(SnakeCase(fe.Param()))
+		}
+		return "validation.kota_id", nil
+	case "harga":
+		return "validation.harga", args(MaxHarga)
+	}
+	return "validation.invalid", args(fe.Tag())
+}
+
+func args(a ...interface{}) []interface{} { return a }
+
+// unit akhiran key sesuai tipe field: _chars untuk string, _items untuk
+// slice, kosong untuk bilangan
+func unit(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return "_chars"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "_items"
+	}
+	return ""
+}
+
+// SnakeCase mengubah nama field Go (HargaKonsumen) ke nama json (harga_konsumen)
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// awal kata baru: setelah huruf kecil, atau akhir singkatan (IDProvinsi)
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
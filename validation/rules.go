@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+)
+
+// ================================
+// 🔹 RULE KHUSUS
+// ================================
+
+// MaxHarga batas atas harga produk (Rp 1 miliar)
+const MaxHarga = 1_000_000_000
+
+var (
+	// nomor HP Indonesia: 08xx, 628xx atau +628xx, total 10-15 digit
+	phoneIDPattern = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,11}$`)
+	// id wilayah mengikuti kode Kemendagri yang dipakai API EMSIFA
+	provinsiIDPattern = regexp.MustCompile(`^[0-9]{2}$`)
+	kotaIDPattern     = regexp.MustCompile(`^[0-9]{4}$`)
+)
+
+// Pattern mengembalikan regex untuk rule khusus berbasis pola (phone_id,
+// provinsi_id, kota_id), dipakai generator OpenAPI
+func Pattern(rule string) (string, bool) {
+	switch rule {
+	case "phone_id":
+		return phoneIDPattern.String(), true
+	case "provinsi_id":
+		return provinsiIDPattern.String(), true
+	case "kota_id":
+		return kotaIDPattern.String(), true
+	}
+	return "", false
+}
+
+func registerRules(v *validator.Validate) {
+	// error hanya mungkin kalau nama tag bentrok, jadi cukup panic saat start
+	must(v.RegisterValidation("phone_id", phoneID))
+	must(v.RegisterValidation("provinsi_id", provinsiID))
+	must(v.RegisterValidation("kota_id", kotaID))
+	must(v.RegisterValidation("harga", harga))
+	// notblank: string tidak boleh kosong atau hanya spasi
+	must(v.RegisterValidation("notblank", validators.NotBlank))
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// phone_id: nomor HP Indonesia tanpa spasi atau tanda "-"
+func phoneID(fl validator.FieldLevel) bool {
+	return phoneIDPattern.MatchString(fl.Field().String())
+}
+
+// provinsi_id: 2 digit, misal "32" untuk Jawa Barat
+func provinsiID(fl validator.FieldLevel) bool {
+	return provinsiIDPattern.MatchString(fl.Field().String())
+}
+
+// kota_id: 4 digit, misal "3273" untuk Kota Bandung. Parameter opsional
+// berisi nama field provinsi di struct yang sama (kota_id=IDProvinsi); kalau
+// field itu diisi, 2 digit pertama kota harus sama dengan id provinsinya.
+func kotaID(fl validator.FieldLevel) bool {
+	kota := fl.Field().String()
+	if !kotaIDPattern.MatchString(kota) {
+		return false
+	}
+	if fl.Param() == "" {
+		return true
+	}
+
+	field, kind, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+	if !found || kind != reflect.String || field.String() == "" {
+		return true
+	}
+	return strings.HasPrefix(kota, field.String())
+}
+
+// harga: rupiah bulat antara 0 dan MaxHarga
+func harga(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() >= 0 && field.Int() <= MaxHarga
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint() <= MaxHarga
+	}
+	return false
+}
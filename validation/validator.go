@@ -0,0 +1,81 @@
+package validation
+
+import (
+	"errors"
+	"go-crud/i18n"
+	"go-crud/utils"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ================================
+// 🔹 VALIDATOR
+// ================================
+
+// Validator membungkus go-playground/validator dan memenuhi echo.Validator,
+// jadi handler cukup memanggil c.Validate(&req) setelah c.Bind(&req).
+type Validator struct {
+	validate *validator.Validate
+}
+
+// Errors adalah hasil validasi yang gagal, satu item per field
+type Errors []utils.FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + i18n.Translate(i18n.Default, fe.Message, fe.Args...)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func New() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// nama field di pesan error mengikuti tag json, sama dengan yang dikirim client
+	v.RegisterTagNameFunc(jsonName)
+
+	registerRules(v)
+	return &Validator{validate: v}
+}
+
+// Validate memvalidasi struct berdasarkan tag `validate`. Error validasi
+// dikembalikan sebagai Errors dengan nama field berupa path json tanpa nama
+// struct terluar (misal "detail_trx[0].kuantitas"); error lain apa adanya.
+func (v *Validator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	root := reflect.Indirect(reflect.ValueOf(i)).Type().Name()
+	out := make(Errors, 0, len(verrs))
+	for _, fe := range verrs {
+		key, args := message(fe)
+		out = append(out, utils.FieldError{
+			Field:   strings.TrimPrefix(fe.Namespace(), root+"."),
+			Rule:    fe.Tag(),
+			Message: key,
+			Args:    args,
+		})
+	}
+	return out
+}
+
+func jsonName(f reflect.StructField) string {
+	name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		// struct parameter query (GET) tidak punya tag json
+		name = f.Tag.Get("query")
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}